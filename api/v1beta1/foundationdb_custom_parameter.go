@@ -45,6 +45,29 @@ func (customParameters FoundationDBCustomParameters) GetKnobsForCLI() []string {
 	return args
 }
 
+// GetName provides the name of the parameter, without the value.
+func (customParameter FoundationDBCustomParameter) GetName() string {
+	return strings.TrimSpace(strings.Split(string(customParameter), "=")[0])
+}
+
+// GetValue provides the value of the parameter with the given name, and
+// whether the parameter is present.
+func (customParameters FoundationDBCustomParameters) GetValue(name string) (string, bool) {
+	for _, parameter := range customParameters {
+		if parameter.GetName() != name {
+			continue
+		}
+
+		components := strings.SplitN(string(parameter), "=", 2)
+		if len(components) < 2 {
+			return "", true
+		}
+		return strings.TrimSpace(components[1]), true
+	}
+
+	return "", false
+}
+
 // ValidateCustomParameters ensures that no duplicate values are set and that no
 // protected/forbidden parameters are set. Theoretically we could also check if FDB
 // supports the given parameter.
@@ -54,8 +77,7 @@ func (customParameters FoundationDBCustomParameters) ValidateCustomParameters()
 	violations := make([]string, 0)
 
 	for _, parameter := range customParameters {
-		parameterName := strings.Split(string(parameter), "=")[0]
-		parameterName = strings.TrimSpace(parameterName)
+		parameterName := parameter.GetName()
 
 		if _, ok := parameters[parameterName]; !ok {
 			parameters[parameterName] = None{}
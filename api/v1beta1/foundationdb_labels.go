@@ -37,6 +37,32 @@ const (
 	// deployments to a cluster.
 	BackupDeploymentLabel = "foundationdb.org/backup-for"
 
+	// ClientLibrariesLabel provides the label that requests the injection of
+	// the client libraries of a cluster into an application pod. The value is
+	// the name of the cluster.
+	ClientLibrariesLabel = "foundationdb.org/client-libraries-for"
+
+	// ClientConfigSourceAnnotation provides the annotation we use to record the
+	// cluster that a published connection string belongs to, in the format
+	// namespace/name.
+	ClientConfigSourceAnnotation = "foundationdb.org/client-config-source"
+
+	// ClientConfigTargetFinalizer provides the finalizer we use to delete the
+	// client config targets in other namespaces when a cluster is deleted.
+	// Owner references can't point to objects in other namespaces, so the
+	// garbage collector won't delete them.
+	ClientConfigTargetFinalizer = "foundationdb.org/client-config-targets"
+
+	// UpgradeCheckVersionAnnotation is an annotation key that requests the
+	// pre-flight checks for an upgrade to the given version.
+	UpgradeCheckVersionAnnotation = "foundationdb.org/upgrade-check-version"
+
+	// UpgradeCheckRequestAnnotation is an annotation key that identifies a
+	// request for the pre-flight checks for an upgrade. The operator runs the
+	// checks once for every request, reports the request in the status and
+	// then removes the annotations for the request.
+	UpgradeCheckRequestAnnotation = "foundationdb.org/upgrade-check-request"
+
 	// PublicIPSourceAnnotation is an annotation key that specifies where a pod
 	// gets its public IP from.
 	PublicIPSourceAnnotation = "foundationdb.org/public-ip-source"
@@ -45,6 +71,11 @@ const (
 	// IP for a pod.
 	PublicIPAnnotation = "foundationdb.org/public-ip"
 
+	// SubstitutionVariableAnnotationPrefix is the prefix for the annotations
+	// that hold the values of substitution variables that are read from node
+	// labels.
+	SubstitutionVariableAnnotationPrefix = "foundationdb.org/substitution-"
+
 	// FDBProcessGroupIDLabel represents the label that is used to represent a instance ID
 	FDBProcessGroupIDLabel = "foundationdb.org/fdb-process-group-id"
 
@@ -68,4 +99,12 @@ const (
 	// FDBLocalityDCIDKey represents the key in the locality map that holds
 	// the DC ID.
 	FDBLocalityDCIDKey = "dcid"
+
+	// FDBLocalityDataHallKey represents the key in the locality map that
+	// holds the data hall.
+	FDBLocalityDataHallKey = "data_hall"
+
+	// FDBLocalityDNSNameKey represents the key in the locality map that
+	// holds the DNS name for the pod.
+	FDBLocalityDNSNameKey = "dns_name"
 )
@@ -0,0 +1,285 @@
+/*
+ * foundationdb_maintenance_window.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2021 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta1
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxMaintenanceWindowSearch defines how far into the future we search for
+// the next start of a maintenance window.
+const maxMaintenanceWindowSearch = 5 * 366 * 24 * time.Hour
+
+// MaintenanceWindow defines a recurring time window in which the operator is
+// allowed to perform disruptive operations.
+type MaintenanceWindow struct {
+	// Schedule defines when the maintenance window starts, in the cron format
+	// with the five fields minute, hour, day of month, month and day of week.
+	// Every field supports wildcards, lists, ranges and steps, e.g.
+	// "0 2 * * 1-5" starts a window at 02:00 on every weekday.
+	// +kubebuilder:validation:MaxLength=100
+	Schedule string `json:"schedule"`
+
+	// Duration defines how long the maintenance window stays open after it
+	// started.
+	Duration metav1.Duration `json:"duration"`
+
+	// TimeZone defines the IANA time zone that the schedule is evaluated in.
+	// Defaults to UTC.
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// Validate checks that the schedule, the time zone and the duration of the
+// maintenance window are valid.
+func (window MaintenanceWindow) Validate() error {
+	_, _, err := window.parse()
+	if err != nil {
+		return err
+	}
+
+	if window.Duration.Duration <= 0 {
+		return fmt.Errorf("maintenance window \"%s\" must have a positive duration", window.Schedule)
+	}
+
+	return nil
+}
+
+// IsOpen determines if the maintenance window is open at the given time. If
+// the window is closed this also returns the time when it opens next. The
+// returned time is zero if the window will not open in the foreseeable future.
+func (window MaintenanceWindow) IsOpen(now time.Time) (bool, time.Time, error) {
+	schedule, location, err := window.parse()
+	if err != nil {
+		return false, time.Time{}, err
+	}
+
+	// The window is open if it started after now - duration and not after
+	// now.
+	start := schedule.next(now.Add(-window.Duration.Duration).In(location))
+	if start.IsZero() {
+		return false, time.Time{}, nil
+	}
+
+	if !start.After(now) {
+		return true, time.Time{}, nil
+	}
+
+	return false, start, nil
+}
+
+// ClosesAt determines when the maintenance window closes, if it is open at
+// the given time. If the window is closed this returns the zero time.
+func (window MaintenanceWindow) ClosesAt(now time.Time) (time.Time, error) {
+	schedule, location, err := window.parse()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	start := schedule.next(now.Add(-window.Duration.Duration).In(location))
+	if start.IsZero() || start.After(now) {
+		return time.Time{}, nil
+	}
+
+	return start.Add(window.Duration.Duration), nil
+}
+
+// parse parses the schedule and the time zone of the window.
+func (window MaintenanceWindow) parse() (*cronSchedule, *time.Location, error) {
+	schedule, err := parseCronSchedule(window.Schedule)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	location := time.UTC
+	if window.TimeZone != "" {
+		location, err = time.LoadLocation(window.TimeZone)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid time zone in maintenance window \"%s\": %w", window.Schedule, err)
+		}
+	}
+
+	return schedule, location, nil
+}
+
+// cronSchedule provides a parsed cron schedule. Every field is stored as a
+// bit set of the allowed values.
+type cronSchedule struct {
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+
+	// restrictedDays defines whether both the day of month and the day of
+	// week are restricted. In that case a day matches if it matches either
+	// of the fields.
+	restrictedDays bool
+}
+
+// cronField defines the bounds of a field in a cron schedule.
+type cronField struct {
+	name string
+	min  int
+	max  int
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	{name: "day of week", min: 0, max: 7},
+}
+
+// parseCronSchedule parses a schedule in the five field cron format.
+func parseCronSchedule(schedule string) (*cronSchedule, error) {
+	fields := strings.Fields(schedule)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("invalid schedule \"%s\": expected %d fields but got %d", schedule, len(cronFields), len(fields))
+	}
+
+	values := make([]uint64, len(fields))
+	for index, field := range fields {
+		value, err := parseCronField(field, cronFields[index])
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule \"%s\": %w", schedule, err)
+		}
+		values[index] = value
+	}
+
+	// Sunday can be specified as 0 or 7.
+	if values[4]&(1<<7) != 0 {
+		values[4] |= 1
+	}
+
+	return &cronSchedule{
+		minute:         values[0],
+		hour:           values[1],
+		dayOfMonth:     values[2],
+		month:          values[3],
+		dayOfWeek:      values[4],
+		restrictedDays: !strings.HasPrefix(fields[2], "*") && !strings.HasPrefix(fields[4], "*"),
+	}, nil
+}
+
+// parseCronField parses a single field of a cron schedule into a bit set.
+func parseCronField(field string, bounds cronField) (uint64, error) {
+	var result uint64
+
+	for _, part := range strings.Split(field, ",") {
+		rangePart := part
+		step := 1
+
+		if strings.Contains(part, "/") {
+			split := strings.SplitN(part, "/", 2)
+			rangePart = split[0]
+
+			var err error
+			step, err = strconv.Atoi(split[1])
+			if err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step \"%s\" in %s field", split[1], bounds.name)
+			}
+		}
+
+		start, end := bounds.min, bounds.max
+		if rangePart != "*" {
+			split := strings.SplitN(rangePart, "-", 2)
+
+			var err error
+			start, err = strconv.Atoi(split[0])
+			if err != nil {
+				return 0, fmt.Errorf("invalid value \"%s\" in %s field", split[0], bounds.name)
+			}
+
+			end = start
+			if len(split) == 2 {
+				end, err = strconv.Atoi(split[1])
+				if err != nil {
+					return 0, fmt.Errorf("invalid value \"%s\" in %s field", split[1], bounds.name)
+				}
+			} else if step > 1 {
+				end = bounds.max
+			}
+		}
+
+		if start < bounds.min || end > bounds.max || start > end {
+			return 0, fmt.Errorf("value \"%s\" is out of range for %s field", rangePart, bounds.name)
+		}
+
+		for value := start; value <= end; value += step {
+			result |= 1 << uint(value)
+		}
+	}
+
+	return result, nil
+}
+
+// matchesDay determines if the schedule matches the day of the given time.
+func (schedule *cronSchedule) matchesDay(t time.Time) bool {
+	dayOfMonth := schedule.dayOfMonth&(1<<uint(t.Day())) != 0
+	dayOfWeek := schedule.dayOfWeek&(1<<uint(t.Weekday())) != 0
+
+	if schedule.restrictedDays {
+		return dayOfMonth || dayOfWeek
+	}
+
+	return dayOfMonth && dayOfWeek
+}
+
+// next returns the first time after the given time that matches the
+// schedule. The result is in the location of the given time. This returns
+// the zero time if no matching time could be found.
+func (schedule *cronSchedule) next(after time.Time) time.Time {
+	location := after.Location()
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := after.Add(maxMaintenanceWindowSearch)
+
+	for t.Before(limit) {
+		if schedule.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, location)
+			continue
+		}
+
+		if !schedule.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, location)
+			continue
+		}
+
+		if schedule.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, location)
+			continue
+		}
+
+		if schedule.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
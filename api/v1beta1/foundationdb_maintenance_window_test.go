@@ -0,0 +1,235 @@
+/*
+ * foundationdb_maintenance_window_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2021 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta1
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("MaintenanceWindow", func() {
+	When("validating a maintenance window", func() {
+		DescribeTable("should return the expected result",
+			func(window MaintenanceWindow, valid bool) {
+				err := window.Validate()
+				if valid {
+					Expect(err).NotTo(HaveOccurred())
+				} else {
+					Expect(err).To(HaveOccurred())
+				}
+			},
+			Entry("valid window",
+				MaintenanceWindow{Schedule: "30 2 * * 1-5", Duration: metav1.Duration{Duration: time.Hour}},
+				true),
+			Entry("valid window with lists and steps",
+				MaintenanceWindow{Schedule: "0,30 */4 1-15/2 1,6 7", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: "America/Los_Angeles"},
+				true),
+			Entry("missing fields",
+				MaintenanceWindow{Schedule: "0 2 * *", Duration: metav1.Duration{Duration: time.Hour}},
+				false),
+			Entry("value out of range",
+				MaintenanceWindow{Schedule: "0 24 * * *", Duration: metav1.Duration{Duration: time.Hour}},
+				false),
+			Entry("invalid step",
+				MaintenanceWindow{Schedule: "*/0 * * * *", Duration: metav1.Duration{Duration: time.Hour}},
+				false),
+			Entry("invalid time zone",
+				MaintenanceWindow{Schedule: "0 2 * * *", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: "Mars/Olympus"},
+				false),
+			Entry("missing duration",
+				MaintenanceWindow{Schedule: "0 2 * * *"},
+				false),
+		)
+	})
+
+	When("checking if a maintenance window is open", func() {
+		type testCase struct {
+			window            MaintenanceWindow
+			now               time.Time
+			expectedOpen      bool
+			expectedNextStart time.Time
+		}
+
+		DescribeTable("should return the expected result",
+			func(tc testCase) {
+				open, nextStart, err := tc.window.IsOpen(tc.now)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(open).To(Equal(tc.expectedOpen))
+				Expect(nextStart.Equal(tc.expectedNextStart)).To(BeTrue(), "expected %s but got %s", tc.expectedNextStart, nextStart)
+			},
+			Entry("inside of a daily window",
+				testCase{
+					window:       MaintenanceWindow{Schedule: "0 2 * * *", Duration: metav1.Duration{Duration: 2 * time.Hour}},
+					now:          time.Date(2021, 11, 10, 3, 30, 0, 0, time.UTC),
+					expectedOpen: true,
+				}),
+			Entry("at the start of a daily window",
+				testCase{
+					window:       MaintenanceWindow{Schedule: "0 2 * * *", Duration: metav1.Duration{Duration: 2 * time.Hour}},
+					now:          time.Date(2021, 11, 10, 2, 0, 0, 0, time.UTC),
+					expectedOpen: true,
+				}),
+			Entry("at the end of a daily window",
+				testCase{
+					window:            MaintenanceWindow{Schedule: "0 2 * * *", Duration: metav1.Duration{Duration: 2 * time.Hour}},
+					now:               time.Date(2021, 11, 10, 4, 0, 0, 0, time.UTC),
+					expectedOpen:      false,
+					expectedNextStart: time.Date(2021, 11, 11, 2, 0, 0, 0, time.UTC),
+				}),
+			Entry("before a weekday window on a weekend",
+				testCase{
+					// November 13th 2021 is a Saturday.
+					window:            MaintenanceWindow{Schedule: "0 2 * * 1-5", Duration: metav1.Duration{Duration: time.Hour}},
+					now:               time.Date(2021, 11, 13, 12, 0, 0, 0, time.UTC),
+					expectedOpen:      false,
+					expectedNextStart: time.Date(2021, 11, 15, 2, 0, 0, 0, time.UTC),
+				}),
+			Entry("with a window that spans midnight",
+				testCase{
+					window:       MaintenanceWindow{Schedule: "0 22 * * *", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+					now:          time.Date(2021, 11, 10, 1, 0, 0, 0, time.UTC),
+					expectedOpen: true,
+				}),
+			Entry("with a window in a different time zone",
+				testCase{
+					window:            MaintenanceWindow{Schedule: "0 2 * * *", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: "America/New_York"},
+					now:               time.Date(2021, 11, 10, 2, 30, 0, 0, time.UTC),
+					expectedOpen:      false,
+					expectedNextStart: time.Date(2021, 11, 10, 7, 0, 0, 0, time.UTC),
+				}),
+			Entry("inside of a window in a different time zone",
+				testCase{
+					window:       MaintenanceWindow{Schedule: "0 2 * * *", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: "America/New_York"},
+					now:          time.Date(2021, 11, 10, 7, 30, 0, 0, time.UTC),
+					expectedOpen: true,
+				}),
+			Entry("with a restricted day of month and day of week",
+				testCase{
+					// November 12th 2021 is a Friday.
+					window:            MaintenanceWindow{Schedule: "0 0 20 * 5", Duration: metav1.Duration{Duration: time.Hour}},
+					now:               time.Date(2021, 11, 10, 12, 0, 0, 0, time.UTC),
+					expectedOpen:      false,
+					expectedNextStart: time.Date(2021, 11, 12, 0, 0, 0, 0, time.UTC),
+				}),
+			Entry("with a window that never opens",
+				testCase{
+					window:       MaintenanceWindow{Schedule: "0 0 31 2 *", Duration: metav1.Duration{Duration: time.Hour}},
+					now:          time.Date(2021, 11, 10, 12, 0, 0, 0, time.UTC),
+					expectedOpen: false,
+				}),
+		)
+	})
+
+	When("checking if a cluster is in a maintenance window", func() {
+		now := time.Date(2021, 11, 10, 12, 0, 0, 0, time.UTC)
+
+		DescribeTable("should return the expected result",
+			func(windows []MaintenanceWindow, expectedOpen bool, expectedNextStart time.Time) {
+				cluster := &FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						MaintenanceWindows: windows,
+					},
+				}
+
+				open, nextStart, err := cluster.IsInMaintenanceWindow(now)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(open).To(Equal(expectedOpen))
+				Expect(nextStart.Equal(expectedNextStart)).To(BeTrue(), "expected %s but got %s", expectedNextStart, nextStart)
+			},
+			Entry("without maintenance windows",
+				nil,
+				true,
+				time.Time{}),
+			Entry("with one open window",
+				[]MaintenanceWindow{
+					{Schedule: "0 2 * * *", Duration: metav1.Duration{Duration: time.Hour}},
+					{Schedule: "0 11 * * *", Duration: metav1.Duration{Duration: 2 * time.Hour}},
+				},
+				true,
+				time.Time{}),
+			Entry("with only closed windows",
+				[]MaintenanceWindow{
+					{Schedule: "0 2 * * *", Duration: metav1.Duration{Duration: time.Hour}},
+					{Schedule: "0 18 * * *", Duration: metav1.Duration{Duration: time.Hour}},
+				},
+				false,
+				time.Date(2021, 11, 10, 18, 0, 0, 0, time.UTC)),
+		)
+	})
+
+	When("checking when a maintenance window closes", func() {
+		DescribeTable("should return the expected result",
+			func(window MaintenanceWindow, now time.Time, expected time.Time) {
+				closesAt, err := window.ClosesAt(now)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(closesAt.Equal(expected)).To(BeTrue(), "expected %s but got %s", expected, closesAt)
+			},
+			Entry("inside of a daily window",
+				MaintenanceWindow{Schedule: "0 2 * * *", Duration: metav1.Duration{Duration: 2 * time.Hour}},
+				time.Date(2021, 11, 10, 3, 30, 0, 0, time.UTC),
+				time.Date(2021, 11, 10, 4, 0, 0, 0, time.UTC)),
+			Entry("outside of a daily window",
+				MaintenanceWindow{Schedule: "0 2 * * *", Duration: metav1.Duration{Duration: 2 * time.Hour}},
+				time.Date(2021, 11, 10, 4, 0, 0, 0, time.UTC),
+				time.Time{}),
+			Entry("with a window that spans midnight",
+				MaintenanceWindow{Schedule: "0 22 * * *", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+				time.Date(2021, 11, 10, 1, 0, 0, 0, time.UTC),
+				time.Date(2021, 11, 10, 2, 0, 0, 0, time.UTC)),
+		)
+	})
+
+	When("checking when the maintenance windows of a cluster close", func() {
+		now := time.Date(2021, 11, 10, 12, 0, 0, 0, time.UTC)
+
+		DescribeTable("should return the expected result",
+			func(windows []MaintenanceWindow, expected time.Time) {
+				cluster := &FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						MaintenanceWindows: windows,
+					},
+				}
+
+				end, err := cluster.GetMaintenanceWindowEnd(now)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(end.Equal(expected)).To(BeTrue(), "expected %s but got %s", expected, end)
+			},
+			Entry("without maintenance windows",
+				nil,
+				time.Time{}),
+			Entry("with overlapping open windows",
+				[]MaintenanceWindow{
+					{Schedule: "0 11 * * *", Duration: metav1.Duration{Duration: 2 * time.Hour}},
+					{Schedule: "30 11 * * *", Duration: metav1.Duration{Duration: 3 * time.Hour}},
+				},
+				time.Date(2021, 11, 10, 14, 30, 0, 0, time.UTC)),
+			Entry("with only closed windows",
+				[]MaintenanceWindow{
+					{Schedule: "0 2 * * *", Duration: metav1.Duration{Duration: time.Hour}},
+				},
+				time.Time{}),
+		)
+	})
+})
@@ -87,6 +87,52 @@ type FoundationDBStatusClusterInfo struct {
 	// FaultTolerance provides information about the fault tolerance status
 	// of the cluster.
 	FaultTolerance FaultTolerance `json:"fault_tolerance,omitempty"`
+
+	// RecoveryState provides information about the current recovery state of
+	// the database.
+	RecoveryState RecoveryState `json:"recovery_state,omitempty"`
+
+	// DatacenterLag provides information about how far the remote data
+	// center lags behind the primary data center. This is only reported if
+	// the database stores data in multiple regions.
+	DatacenterLag *DatacenterLag `json:"datacenter_lag,omitempty"`
+
+	// StorageWiggler provides information about the perpetual storage
+	// wiggle.
+	StorageWiggler FoundationDBStatusStorageWiggler `json:"storage_wiggler,omitempty"`
+}
+
+// FoundationDBStatusStorageWiggler provides information about the perpetual
+// storage wiggle.
+type FoundationDBStatusStorageWiggler struct {
+	// WiggleServerAddresses provides the addresses of the storage servers
+	// that are currently being wiggled.
+	WiggleServerAddresses []ProcessAddress `json:"wiggle_server_addresses,omitempty"`
+}
+
+// RecoveryState provides information about the current recovery state of
+// the database.
+type RecoveryState struct {
+	// Name defines the name of the recovery state, e.g. fully_recovered.
+	Name string `json:"name,omitempty"`
+
+	// Description provides a human readable description of the recovery
+	// state.
+	Description string `json:"description,omitempty"`
+}
+
+// RecoveryStateFullyRecovered defines the recovery state of a database that
+// has completed its recovery.
+const RecoveryStateFullyRecovered = "fully_recovered"
+
+// DatacenterLag provides information about how far the remote data center
+// lags behind the primary data center.
+type DatacenterLag struct {
+	// Seconds provides the lag in seconds.
+	Seconds float64 `json:"seconds,omitempty"`
+
+	// Versions provides the lag in versions.
+	Versions int64 `json:"versions,omitempty"`
 }
 
 // FaultTolerance provides information about the fault tolerance status
@@ -131,6 +177,21 @@ type FoundationDBStatusProcessInfo struct {
 type FoundationDBStatusProcessRoleInfo struct {
 	// Role defines the role a process currently has
 	Role string `json:"role,omitempty"`
+
+	// StorageMetadata provides information about a storage server. This is
+	// only reported for storage roles.
+	StorageMetadata *FoundationDBStatusStorageMetadata `json:"storage_metadata,omitempty"`
+}
+
+// FoundationDBStatusStorageMetadata provides information about a storage
+// server.
+type FoundationDBStatusStorageMetadata struct {
+	// CreatedTimestamp provides the time when the storage server was created,
+	// in seconds since the epoch.
+	CreatedTimestamp float64 `json:"created_time_timestamp,omitempty"`
+
+	// StorageEngine provides the storage engine the storage server uses.
+	StorageEngine StorageEngine `json:"storage_engine,omitempty"`
 }
 
 // FoundationDBStatusDataStatistics provides information about the data in
@@ -270,4 +331,20 @@ type ProcessRole string
 const (
 	// ProcessRoleCoordinator model for FDB coordinator role
 	ProcessRoleCoordinator ProcessRole = "coordinator"
+	// ProcessRoleMaster model for FDB master role
+	ProcessRoleMaster ProcessRole = "master"
+	// ProcessRoleStorage model for FDB storage role
+	ProcessRoleStorage ProcessRole = "storage"
+	// ProcessRoleLog model for FDB log role
+	ProcessRoleLog ProcessRole = "log"
+	// ProcessRoleProxy model for FDB proxy role
+	ProcessRoleProxy ProcessRole = "proxy"
+	// ProcessRoleGrvProxy model for FDB grv_proxy role
+	ProcessRoleGrvProxy ProcessRole = "grv_proxy"
+	// ProcessRoleCommitProxy model for FDB commit_proxy role
+	ProcessRoleCommitProxy ProcessRole = "commit_proxy"
+	// ProcessRoleResolver model for FDB resolver role
+	ProcessRoleResolver ProcessRole = "resolver"
+	// ProcessRoleLogRouter model for FDB router role
+	ProcessRoleLogRouter ProcessRole = "router"
 )
@@ -67,6 +67,10 @@ var _ = Describe("FoundationDBStatus", func() {
 				},
 				Cluster: FoundationDBStatusClusterInfo{
 					// In FDB 6.1 this would be machines failures.
+					RecoveryState: RecoveryState{
+						Name:        "fully_recovered",
+						Description: "Recovery complete.",
+					},
 					FaultTolerance: FaultTolerance{
 						MaxZoneFailuresWithoutLosingAvailability: 0,
 						MaxZoneFailuresWithoutLosingData:         0,
@@ -381,6 +385,10 @@ var _ = Describe("FoundationDBStatus", func() {
 					DatabaseStatus: FoundationDBStatusClientDBStatus{Available: true, Healthy: true},
 				},
 				Cluster: FoundationDBStatusClusterInfo{
+					RecoveryState: RecoveryState{
+						Name:        "fully_recovered",
+						Description: "Recovery complete.",
+					},
 					FaultTolerance: FaultTolerance{
 						MaxZoneFailuresWithoutLosingAvailability: 1,
 						MaxZoneFailuresWithoutLosingData:         1,
@@ -585,6 +593,7 @@ var _ = Describe("FoundationDBStatus", func() {
 						State:      FoundationDBStatusDataState{Description: "", Healthy: true, Name: "healthy"},
 					},
 					FullReplication: true,
+					DatacenterLag:   &DatacenterLag{},
 					Clients: FoundationDBStatusClusterClientInfo{
 						Count: 8,
 						SupportedVersions: []FoundationDBStatusSupportedVersion{
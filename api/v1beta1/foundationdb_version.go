@@ -159,6 +159,30 @@ func (version FdbVersion) HasNonBlockingExcludes(useNonBlockingExcludes bool) bo
 	return version.IsAtLeast(FdbVersion{Major: 6, Minor: 3, Patch: 5}) && useNonBlockingExcludes
 }
 
+// SupportsDNSInClusterFile determines if a version has support for DNS names
+// in the cluster file.
+func (version FdbVersion) SupportsDNSInClusterFile() bool {
+	return version.IsAtLeast(FdbVersion{Major: 7, Minor: 0, Patch: 0})
+}
+
+// SupportsRuntimeKnobs determines if a version has support for changing
+// knobs through the configuration database while the processes are running.
+func (version FdbVersion) SupportsRuntimeKnobs() bool {
+	return version.IsAtLeast(FdbVersion{Major: 7, Minor: 1, Patch: 0})
+}
+
+// HasSeparatedProxies determines if a version has separate roles for GRV
+// proxies and commit proxies.
+func (version FdbVersion) HasSeparatedProxies() bool {
+	return version.IsAtLeast(FdbVersion{Major: 7, Minor: 0, Patch: 0})
+}
+
+// HasPerpetualStorageWiggle determines if a version has support for the
+// perpetual storage wiggle and for configuring the storage migration type.
+func (version FdbVersion) HasPerpetualStorageWiggle() bool {
+	return version.IsAtLeast(FdbVersion{Major: 7, Minor: 0, Patch: 0})
+}
+
 // NextMajorVersion returns the next major version of FoundationDB.
 func (version FdbVersion) NextMajorVersion() FdbVersion {
 	return FdbVersion{Major: version.Major + 1, Minor: 0, Patch: 0}
@@ -195,6 +219,10 @@ var Versions = struct {
 	WithBinariesFromMainContainer, WithoutBinariesFromMainContainer,
 	WithRatekeeperRole, WithoutRatekeeperRole,
 	WithSidecarCrashOnEmpty, WithoutSidecarCrashOnEmpty,
+	WithDNSInClusterFile, WithoutDNSInClusterFile,
+	WithRuntimeKnobs, WithoutRuntimeKnobs,
+	WithSeparatedProxies, WithoutSeparatedProxies,
+	WithPerpetualStorageWiggle, WithoutPerpetualStorageWiggle,
 	MinimumVersion,
 	Default FdbVersion
 }{
@@ -211,5 +239,13 @@ var Versions = struct {
 	WithoutRatekeeperRole:                FdbVersion{Major: 6, Minor: 1, Patch: 12},
 	WithSidecarCrashOnEmpty:              FdbVersion{Major: 6, Minor: 2, Patch: 20},
 	WithoutSidecarCrashOnEmpty:           FdbVersion{Major: 6, Minor: 2, Patch: 15},
+	WithDNSInClusterFile:                 FdbVersion{Major: 7, Minor: 0, Patch: 0},
+	WithoutDNSInClusterFile:              FdbVersion{Major: 6, Minor: 3, Patch: 13},
+	WithRuntimeKnobs:                     FdbVersion{Major: 7, Minor: 1, Patch: 0},
+	WithoutRuntimeKnobs:                  FdbVersion{Major: 7, Minor: 0, Patch: 0},
+	WithSeparatedProxies:                 FdbVersion{Major: 7, Minor: 0, Patch: 0},
+	WithoutSeparatedProxies:              FdbVersion{Major: 6, Minor: 3, Patch: 13},
+	WithPerpetualStorageWiggle:           FdbVersion{Major: 7, Minor: 0, Patch: 0},
+	WithoutPerpetualStorageWiggle:        FdbVersion{Major: 6, Minor: 3, Patch: 13},
 	MinimumVersion:                       FdbVersion{Major: 6, Minor: 1, Patch: 12},
 }
@@ -0,0 +1,96 @@
+/*
+ * foundationdbcluster_crd_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2021 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta1
+
+import (
+	"io/ioutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+	"sigs.k8s.io/yaml"
+)
+
+var _ = Describe("FoundationDBCluster CRD", func() {
+	var validator *validate.SchemaValidator
+
+	BeforeEach(func() {
+		data, err := ioutil.ReadFile("../../config/crd/bases/apps.foundationdb.org_foundationdbclusters.yaml")
+		Expect(err).NotTo(HaveOccurred())
+
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		err = yaml.Unmarshal(data, crd)
+		Expect(err).NotTo(HaveOccurred())
+
+		var schema *apiextensionsv1.JSONSchemaProps
+		for _, version := range crd.Spec.Versions {
+			if version.Name == GroupVersion.Version {
+				schema = version.Schema.OpenAPIV3Schema
+			}
+		}
+		Expect(schema).NotTo(BeNil())
+
+		internalSchema := &apiextensions.JSONSchemaProps{}
+		err = apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(schema, internalSchema, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		validator, _, err = validation.NewSchemaValidator(&apiextensions.CustomResourceValidation{OpenAPIV3Schema: internalSchema})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	DescribeTable("validating the redundancy mode",
+		func(redundancyMode RedundancyMode, valid bool) {
+			cluster := &FoundationDBCluster{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: GroupVersion.String(),
+					Kind:       "FoundationDBCluster",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sample-cluster",
+					Namespace: "default",
+				},
+				Spec: FoundationDBClusterSpec{
+					Version: Versions.Default.String(),
+					DatabaseConfiguration: DatabaseConfiguration{
+						RedundancyMode: redundancyMode,
+					},
+				},
+			}
+
+			object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(cluster)
+			Expect(err).NotTo(HaveOccurred())
+
+			result := validator.Validate(object)
+			Expect(result.IsValid()).To(Equal(valid), "%v", result.Errors)
+		},
+		Entry("single", RedundancyModeSingle, true),
+		Entry("double", RedundancyModeDouble, true),
+		Entry("triple", RedundancyModeTriple, true),
+		Entry("three_data_hall", RedundancyModeThreeDataHall, true),
+		Entry("unknown", RedundancyMode("quadruple"), false),
+	)
+})
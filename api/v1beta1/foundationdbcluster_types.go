@@ -30,6 +30,7 @@ import (
 	"strings"
 	"time"
 
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/utils/pointer"
 
 	"github.com/go-logr/logr"
@@ -94,6 +95,11 @@ type FoundationDBClusterSpec struct {
 	// Processes defines process-level settings.
 	Processes map[ProcessClass]ProcessSettings `json:"processes,omitempty"`
 
+	// ProcessGroupParameterOverrides defines additional custom parameters for
+	// individual process groups, keyed by the process group ID. These are
+	// applied on top of the custom parameters for the process class.
+	ProcessGroupParameterOverrides map[string]ProcessGroupParameterOverride `json:"processGroupParameterOverrides,omitempty"`
+
 	// ProcessCounts defines the number of processes to configure for each
 	// process class. You can generally omit this, to allow the operator to
 	// infer the process counts based on the database configuration.
@@ -162,23 +168,55 @@ type FoundationDBClusterSpec struct {
 	// format.
 	TrustedCAs []string `json:"trustedCAs,omitempty"`
 
+	// TLSCertificates defines where the processes get their TLS certificates
+	// from. When this is set, the operator mounts the certificates into the
+	// pods and bounces the processes when the certificates change.
+	TLSCertificates *TLSCertificateConfig `json:"tlsCertificates,omitempty"`
+
+	// SidecarAuthentication defines how the operator authenticates its
+	// requests to the sidecar.
+	SidecarAuthentication *SidecarAuthenticationConfig `json:"sidecarAuthentication,omitempty"`
+
 	// SidecarVariables defines Custom variables that the sidecar should make
 	// available for substitution in the monitor conf file.
 	SidecarVariables []string `json:"sidecarVariables,omitempty"`
 
+	// SubstitutionVariables defines additional variables that are available
+	// for substitution in the custom parameters and locality settings, along
+	// with the source of their values.
+	SubstitutionVariables []SubstitutionVariable `json:"substitutionVariables,omitempty"`
+
 	// LogGroup defines the log group to use for the trace logs for the cluster.
 	LogGroup string `json:"logGroup,omitempty"`
 
 	// DataCenter defines the data center where these processes are running.
 	DataCenter string `json:"dataCenter,omitempty"`
 
+	// PrimaryDataCenter defines the data center that should act as the
+	// primary. If this is set, the operator changes the region priorities so
+	// that this data center has the highest priority, after the data center
+	// has caught up. Changing this field triggers a fail over or a fail back.
+	PrimaryDataCenter string `json:"primaryDataCenter,omitempty"`
+
 	// DataHall defines the data hall where these processes are running.
+	// If this starts with a $, the value is read from the environment
+	// variable with that name, which must also be listed in the
+	// SidecarVariables or the SubstitutionVariables. If this is not set and
+	// the fault domain has a DataHallKey, the data hall is read from the node
+	// label with that key.
 	DataHall string `json:"dataHall,omitempty"`
 
 	// AutomationOptions defines customization for enabling or disabling certain
 	// operations in the operator.
 	AutomationOptions FoundationDBClusterAutomationOptions `json:"automationOptions,omitempty"`
 
+	// MaintenanceWindows defines the time windows in which the operator is
+	// allowed to perform disruptive operations, like bouncing processes,
+	// deleting pods for updates and changing coordinators. If this is empty,
+	// disruptive operations can be performed at any time.
+	// +kubebuilder:validation:MaxItems=100
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
 	// InstanceIDPrefix defines a prefix to append to the instance IDs in the
 	// locality fields.
 	//
@@ -212,6 +250,16 @@ type FoundationDBClusterSpec struct {
 	// Routing defines the configuration for routing to our pods.
 	Routing RoutingConfig `json:"routing,omitempty"`
 
+	// ClientLibraries defines the client libraries the operator publishes
+	// for application pods.
+	ClientLibraries ClientLibraryConfig `json:"clientLibraries,omitempty"`
+
+	// ClientConfigTargets defines the config maps and secrets in other
+	// namespaces where the operator publishes the connection string of the
+	// cluster for applications.
+	// +kubebuilder:validation:MaxItems=100
+	ClientConfigTargets []ClientConfigTarget `json:"clientConfigTargets,omitempty"`
+
 	// IgnoreUpgradabilityChecks determines whether we should skip the check for
 	// client compatibility when performing an upgrade.
 	IgnoreUpgradabilityChecks bool `json:"ignoreUpgradabilityChecks,omitempty"`
@@ -323,6 +371,12 @@ type FoundationDBClusterSpec struct {
 	// storage processes
 	StorageServersPerPod int `json:"storageServersPerPod,omitempty"`
 
+	// LogServersPerPod defines how many Log Servers should run in a single
+	// process group (Pod) for the log and transaction process classes. This
+	// means that you end up with ProcessCounts["log"] * LogServersPerPod log
+	// processes.
+	LogServersPerPod int `json:"logServersPerPod,omitempty"`
+
 	// MinimumUptimeSecondsForBounce defines the minimum time, in seconds, that the
 	// processes in the cluster must have been up for before the operator can
 	// execute a bounce.
@@ -349,6 +403,13 @@ type FoundationDBClusterSpec struct {
 	// the coordinator selection process could conflict.
 	CoordinatorSelection []CoordinatorSelectionSetting `json:"coordinatorSelection,omitempty"`
 
+	// UseDedicatedCoordinators defines whether the operator should run the
+	// coordinators in dedicated process groups with the coordinator process
+	// class. When this is enabled, only processes with the coordinator
+	// process class are eligible as coordinators, and the CoordinatorSelection
+	// is ignored.
+	UseDedicatedCoordinators *bool `json:"useDedicatedCoordinators,omitempty"`
+
 	// LabelConfig allows customizing labels used by the operator.
 	LabelConfig LabelConfig `json:"labels,omitempty"`
 
@@ -430,6 +491,12 @@ type FoundationDBClusterStatus struct {
 	// currently running.
 	RunningVersion string `json:"runningVersion,omitempty"`
 
+	// PreviousVersion defines the version of FoundationDB that the cluster
+	// was running before the last upgrade. Setting the version in the spec to
+	// this version rolls back the upgrade if both versions are
+	// protocol-compatible.
+	PreviousVersion string `json:"previousVersion,omitempty"`
+
 	// ConnectionString defines the contents of the cluster file.
 	ConnectionString string `json:"connectionString,omitempty"`
 
@@ -454,6 +521,10 @@ type FoundationDBClusterStatus struct {
 	// If there are more than one value in the slice the reconcile phase is not finished.
 	StorageServersPerDisk []int `json:"storageServersPerDisk,omitempty"`
 
+	// LogServersPerDisk defines the logServersPerPod observed in the cluster.
+	// If there are more than one value in the slice the reconcile phase is not finished.
+	LogServersPerDisk []int `json:"logServersPerDisk,omitempty"`
+
 	// ImageTypes defines the kinds of images that are in use in the cluster.
 	// If there is more than one value in the slice the reconcile phase is not
 	// finished.
@@ -466,6 +537,314 @@ type FoundationDBClusterStatus struct {
 
 	// Locks contains information about the locking system.
 	Locks LockSystemStatus `json:"locks,omitempty"`
+
+	// ReconciliationHistory contains the most recent reconciliation attempts
+	// that did not complete. The newest entry is the last entry in the list.
+	// +kubebuilder:validation:MaxItems=100
+	ReconciliationHistory []ReconciliationHistoryEntry `json:"reconciliationHistory,omitempty"`
+
+	// ActiveFreezes contains the freezes from the automation options that
+	// were active during the last reconciliation.
+	ActiveFreezes []AutomationFreeze `json:"activeFreezes,omitempty"`
+
+	// DataCenters provides the global view of all data centers that have
+	// processes reporting to the database, including the data centers that
+	// are managed by other instances of the operator.
+	DataCenters []DataCenterStatus `json:"dataCenters,omitempty"`
+
+	// ActivePrimaryDataCenter provides the data center that currently acts as
+	// the primary.
+	ActivePrimaryDataCenter string `json:"activePrimaryDataCenter,omitempty"`
+
+	// FailOver provides the progress of a fail over to the primary data
+	// center from the spec. This is empty if no fail over is in progress.
+	FailOver *FailOverStatus `json:"failOver,omitempty"`
+
+	// UpgradeCheck provides the results of the last pre-flight checks for an
+	// upgrade to the version requested through the
+	// foundationdb.org/upgrade-check-version annotation.
+	UpgradeCheck *UpgradeCheckStatus `json:"upgradeCheck,omitempty"`
+
+	// UpgradeProgress provides the progress of an upgrade to the version from
+	// the spec. This is empty if all processes run the version from the spec.
+	UpgradeProgress *UpgradeProgress `json:"upgradeProgress,omitempty"`
+
+	// ClientConfigTargets provides the config maps and secrets where the
+	// operator has published the connection string, in the format
+	// kind/namespace/name.
+	ClientConfigTargets []string `json:"clientConfigTargets,omitempty"`
+
+	// TLSCertificate provides information about the TLS certificate in the
+	// secret from the spec.
+	TLSCertificate *TLSCertificateStatus `json:"tlsCertificate,omitempty"`
+
+	// TLSMigration provides the progress of a migration between TLS and
+	// non-TLS listeners. This is empty if no migration is in progress.
+	TLSMigration *TLSMigrationStatus `json:"tlsMigration,omitempty"`
+
+	// ProcessGroupParameterOverrides provides the parameter overrides from the
+	// spec that are currently applied to process groups.
+	ProcessGroupParameterOverrides map[string]ProcessGroupParameterOverride `json:"processGroupParameterOverrides,omitempty"`
+
+	// RuntimeKnobs provides the knobs that the operator has set in the
+	// configuration database, keyed by the knob name without the knob_
+	// prefix.
+	RuntimeKnobs map[string]string `json:"runtimeKnobs,omitempty"`
+}
+
+// TLSMigrationPhase describes the phase of a migration between TLS and
+// non-TLS listeners.
+// +kubebuilder:validation:MaxLength=100
+type TLSMigrationPhase string
+
+const (
+	// TLSMigrationPhaseEnablingDualListeners defines that the processes are
+	// bounced to listen on both TLS and non-TLS addresses.
+	TLSMigrationPhaseEnablingDualListeners TLSMigrationPhase = "EnablingDualListeners"
+
+	// TLSMigrationPhaseChangingCoordinators defines that the operator waits
+	// for the coordinators to be changed to the new address scheme.
+	TLSMigrationPhaseChangingCoordinators TLSMigrationPhase = "ChangingCoordinators"
+
+	// TLSMigrationPhaseRemovingOldListener defines that the processes are
+	// bounced to listen only on the new address scheme.
+	TLSMigrationPhaseRemovingOldListener TLSMigrationPhase = "RemovingOldListener"
+)
+
+// TLSMigrationStatus describes the progress of a migration between TLS and
+// non-TLS listeners.
+type TLSMigrationStatus struct {
+	// TLS defines whether the processes will listen on TLS addresses once
+	// the migration is complete.
+	TLS bool `json:"tls,omitempty"`
+
+	// Phase provides the current phase of the migration.
+	Phase TLSMigrationPhase `json:"phase"`
+
+	// StartTime provides the time when the migration started.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+}
+
+// TLSCertificateStatus describes a TLS certificate that the operator has
+// observed.
+type TLSCertificateStatus struct {
+	// Hash provides a hash of the certificate and the private key.
+	Hash string `json:"hash"`
+
+	// NotAfter provides the time when the certificate expires.
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+}
+
+// StorageWiggleStatus describes the progress of the perpetual storage wiggle
+// for the storage servers in a process group.
+type StorageWiggleStatus struct {
+	// InProgress indicates whether the wiggle is currently replacing one of
+	// the storage servers in the process group.
+	InProgress bool `json:"inProgress,omitempty"`
+
+	// LastRefreshed provides the time when the oldest storage server in the
+	// process group was created. All storage servers in the process group
+	// have been refreshed since this time.
+	LastRefreshed *metav1.Time `json:"lastRefreshed,omitempty"`
+
+	// StorageEngine provides the storage engine of the storage servers in
+	// the process group. If the storage servers use different storage
+	// engines, this provides the one that differs from the database
+	// configuration.
+	StorageEngine StorageEngine `json:"storageEngine,omitempty"`
+}
+
+// UpgradeProgress describes the progress of an upgrade.
+type UpgradeProgress struct {
+	// Version provides the version the cluster is upgraded to.
+	Version string `json:"version"`
+
+	// ProcessVersions provides the number of processes that report each
+	// version.
+	ProcessVersions map[string]int `json:"processVersions,omitempty"`
+
+	// PendingUpgrades provides the process groups that are marked as ready
+	// for the upgrade in the locking system.
+	PendingUpgrades []string `json:"pendingUpgrades,omitempty"`
+}
+
+// UpgradeCheckStatus provides the results of the pre-flight checks for an
+// upgrade to a new version of FoundationDB.
+type UpgradeCheckStatus struct {
+	// Version provides the version the checks were run for.
+	Version string `json:"version"`
+
+	// RequestID provides the request from the
+	// foundationdb.org/upgrade-check-request annotation that the checks were
+	// run for.
+	RequestID string `json:"requestID,omitempty"`
+
+	// Timestamp provides the time when the checks were run.
+	Timestamp *metav1.Time `json:"timestamp,omitempty"`
+
+	// Passed defines whether all checks passed and the upgrade can be
+	// started.
+	Passed bool `json:"passed,omitempty"`
+
+	// UnsupportedClients provides the connected clients that don't support
+	// the protocol version of the new version.
+	UnsupportedClients []UpgradeCheckClient `json:"unsupportedClients,omitempty"`
+
+	// Images provides the images that will be used for the new version.
+	Images []string `json:"images,omitempty"`
+
+	// ImagesResolved defines whether the images for all process classes
+	// could be resolved for the new version.
+	ImagesResolved bool `json:"imagesResolved,omitempty"`
+
+	// SidecarsSupported defines whether the sidecars support the new
+	// version.
+	SidecarsSupported bool `json:"sidecarsSupported,omitempty"`
+
+	// Errors provides the problems that were found during the checks.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// UpgradeCheckClient describes a connected client that blocks an upgrade.
+type UpgradeCheckClient struct {
+	FoundationDBStatusConnectedClient `json:",inline"`
+
+	// Version provides the highest version of FoundationDB the client
+	// supports.
+	Version string `json:"version,omitempty"`
+}
+
+// FailOverPhase describes the phase of a fail over.
+// +kubebuilder:validation:MaxLength=100
+type FailOverPhase string
+
+const (
+	// FailOverPhaseWaitingForCatchUp defines that the operator waits for the
+	// new primary data center to catch up with the current primary.
+	FailOverPhaseWaitingForCatchUp FailOverPhase = "WaitingForCatchUp"
+
+	// FailOverPhaseRecovering defines that the region priorities have been
+	// changed and the operator waits for the database to recover in the new
+	// primary data center.
+	FailOverPhaseRecovering FailOverPhase = "Recovering"
+)
+
+// FailOverStatus describes the progress of a fail over.
+type FailOverStatus struct {
+	// From provides the data center that was the primary when the fail over
+	// started.
+	From string `json:"from,omitempty"`
+
+	// To provides the data center that should become the primary.
+	To string `json:"to"`
+
+	// Phase provides the current phase of the fail over.
+	Phase FailOverPhase `json:"phase"`
+
+	// StartTime provides the time when the fail over started.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+}
+
+// DataCenterStatus describes the processes of a single data center, as
+// reported by the database.
+type DataCenterStatus struct {
+	// ID provides the dcid locality of the data center.
+	ID string `json:"id"`
+
+	// Managed defines whether this data center is managed by this instance of
+	// the operator.
+	Managed bool `json:"managed,omitempty"`
+
+	// ProcessCounts provides the number of processes in this data center for
+	// each process class.
+	ProcessCounts ProcessCounts `json:"processCounts,omitempty"`
+
+	// RoleCounts provides the number of roles that the processes in this
+	// data center have been recruited for, as reported by the database. The
+	// log roles are counted in logs in every data center.
+	RoleCounts RoleCounts `json:"roleCounts,omitempty"`
+
+	// ExcludedProcesses provides the number of excluded processes in this
+	// data center.
+	ExcludedProcesses int `json:"excludedProcesses,omitempty"`
+
+	// Coordinators provides the number of coordinators in this data center.
+	Coordinators int `json:"coordinators,omitempty"`
+
+	// OperatorInstances provides the IDs of the operator instances that have
+	// registered themselves in the database for this data center.
+	OperatorInstances []string `json:"operatorInstances,omitempty"`
+}
+
+// ReconciliationHistoryEntry describes a reconciliation attempt that was
+// stopped or delayed by a sub-reconciler.
+type ReconciliationHistoryEntry struct {
+	// Timestamp provides the last time this outcome was observed.
+	Timestamp metav1.Time `json:"timestamp,omitempty"`
+
+	// SubReconciler provides the name of the sub-reconciler that stopped or
+	// delayed the reconciliation.
+	SubReconciler string `json:"subReconciler,omitempty"`
+
+	// Message provides the message the sub-reconciler returned.
+	Message string `json:"message,omitempty"`
+
+	// Error provides the error the sub-reconciler returned, if any.
+	Error string `json:"error,omitempty"`
+
+	// Generation provides the generation of the spec that was being
+	// reconciled.
+	Generation int64 `json:"generation,omitempty"`
+
+	// Delayed defines whether the sub-reconciler only delayed the requeue
+	// and the remaining sub-reconcilers were still run.
+	Delayed bool `json:"delayed,omitempty"`
+
+	// Count provides the number of consecutive attempts that ended with this
+	// outcome.
+	Count int `json:"count,omitempty"`
+}
+
+// matches determines if two history entries describe the same outcome.
+func (entry ReconciliationHistoryEntry) matches(other ReconciliationHistoryEntry) bool {
+	return entry.SubReconciler == other.SubReconciler &&
+		entry.Message == other.Message &&
+		entry.Error == other.Error &&
+		entry.Generation == other.Generation &&
+		entry.Delayed == other.Delayed
+}
+
+// AddReconciliationHistoryEntry records a reconciliation attempt in the
+// status. If the newest entry describes the same outcome, the existing entry
+// will be updated instead of adding a new one. The history is limited to
+// the given number of entries and the oldest entries are dropped first.
+// This returns true if a new entry was added.
+func (clusterStatus *FoundationDBClusterStatus) AddReconciliationHistoryEntry(entry ReconciliationHistoryEntry, limit int) bool {
+	if limit <= 0 {
+		clusterStatus.ReconciliationHistory = nil
+		return false
+	}
+
+	historyLength := len(clusterStatus.ReconciliationHistory)
+	if historyLength > 0 {
+		latest := &clusterStatus.ReconciliationHistory[historyLength-1]
+		if latest.matches(entry) {
+			latest.Timestamp = entry.Timestamp
+			latest.Count++
+			return false
+		}
+	}
+
+	if entry.Count == 0 {
+		entry.Count = 1
+	}
+
+	clusterStatus.ReconciliationHistory = append(clusterStatus.ReconciliationHistory, entry)
+	if len(clusterStatus.ReconciliationHistory) > limit {
+		clusterStatus.ReconciliationHistory = clusterStatus.ReconciliationHistory[len(clusterStatus.ReconciliationHistory)-limit:]
+	}
+
+	return true
 }
 
 // LockSystemStatus provides a summary of the status of the locking system.
@@ -499,6 +878,17 @@ type ProcessGroupStatus struct {
 	ExclusionSkipped bool `json:"exclusionSkipped,omitempty"`
 	// ProcessGroupConditions represents a list of degraded conditions that the process group is in.
 	ProcessGroupConditions []*ProcessGroupCondition `json:"processGroupConditions,omitempty"`
+	// TLSCertificate represents the TLS certificate that the processes in the
+	// process group have loaded.
+	TLSCertificate *TLSCertificateStatus `json:"tlsCertificate,omitempty"`
+	// ActiveKnobs represents the knobs that are active for the processes in
+	// the process group, from the command line and from the configuration
+	// database. This is only reported for versions that support runtime
+	// knobs.
+	ActiveKnobs map[string]string `json:"activeKnobs,omitempty"`
+	// StorageWiggle represents the progress of the perpetual storage wiggle
+	// for the storage servers in the process group.
+	StorageWiggle *StorageWiggleStatus `json:"storageWiggle,omitempty"`
 }
 
 // IsExcluded returns if a process group is excluded
@@ -854,6 +1244,16 @@ const (
 	SidecarUnreachable ProcessGroupConditionType = "SidecarUnreachable"
 	// PodPending represents a process group where the pod is in a pending state.
 	PodPending ProcessGroupConditionType = "PodPending"
+	// CoordinatorUnreachable represents a process group that hosts a coordinator
+	// which is reported as unreachable in the database status.
+	CoordinatorUnreachable ProcessGroupConditionType = "CoordinatorUnreachable"
+	// NodeUnschedulable represents a process group that hosts a coordinator
+	// and where the pod is running on a node that is marked as unschedulable,
+	// e.g. because the node is drained.
+	NodeUnschedulable ProcessGroupConditionType = "NodeUnschedulable"
+	// OutdatedTLSCertificate represents a process group whose processes have
+	// not loaded the current TLS certificate yet.
+	OutdatedTLSCertificate ProcessGroupConditionType = "OutdatedTLSCertificate"
 	// ReadyCondition is currently only used in the metrics.
 	ReadyCondition ProcessGroupConditionType = "Ready"
 )
@@ -871,6 +1271,9 @@ func AllProcessGroupConditionTypes() []ProcessGroupConditionType {
 		MissingProcesses,
 		SidecarUnreachable,
 		PodPending,
+		CoordinatorUnreachable,
+		NodeUnschedulable,
+		OutdatedTLSCertificate,
 		ReadyCondition,
 	}
 }
@@ -898,6 +1301,12 @@ func GetProcessGroupConditionType(processGroupConditionType string) (ProcessGrou
 		return SidecarUnreachable, nil
 	case "PodPending":
 		return PodPending, nil
+	case "CoordinatorUnreachable":
+		return CoordinatorUnreachable, nil
+	case "NodeUnschedulable":
+		return NodeUnschedulable, nil
+	case "OutdatedTLSCertificate":
+		return OutdatedTLSCertificate, nil
 	}
 
 	return "", fmt.Errorf("unknown process group condition type: %s", processGroupConditionType)
@@ -976,6 +1385,10 @@ type ClusterGenerationStatus struct {
 	// NeedsLockConfigurationChanges provides the last generation that is
 	// pending a change to the configuration of the locking system.
 	NeedsLockConfigurationChanges int64 `json:"needsLockConfigurationChanges,omitempty"`
+
+	// NeedsRuntimeKnobUpdate provides the last generation that is pending a
+	// change to the knobs in the configuration database.
+	NeedsRuntimeKnobUpdate int64 `json:"needsRuntimeKnobUpdate,omitempty"`
 }
 
 // ClusterHealth represents different views into health in the cluster status.
@@ -1023,6 +1436,16 @@ type RoleCounts struct {
 	Resolvers  int `json:"resolvers,omitempty"`
 	LogRouters int `json:"log_routers,omitempty"`
 	RemoteLogs int `json:"remote_logs,omitempty"`
+
+	// GrvProxies defines the number of GRV proxies. This is only used in
+	// FDB 7.0 and later, and replaces the Proxies count when either this
+	// or CommitProxies is set.
+	GrvProxies int `json:"grv_proxies,omitempty"`
+
+	// CommitProxies defines the number of commit proxies. This is only used
+	// in FDB 7.0 and later, and replaces the Proxies count when either this
+	// or GrvProxies is set.
+	CommitProxies int `json:"commit_proxies,omitempty"`
 }
 
 // Map returns a map from process classes to the desired count for that role
@@ -1081,6 +1504,8 @@ type ProcessCounts struct {
 	Ratekeeper        int `json:"ratekeeper,omitempty"`
 	StorageCache      int `json:"storage_cache,omitempty"`
 	BackupWorker      int `json:"backup,omitempty"`
+	GrvProxy          int `json:"grv_proxy,omitempty"`
+	CommitProxy       int `json:"commit_proxy,omitempty"`
 
 	// Deprecated: This is unsupported and any processes with this process class
 	// will fail to start.
@@ -1191,6 +1616,12 @@ type FoundationDBClusterAutomationOptions struct {
 	// further reconciliation.
 	IgnorePendingPodsDuration time.Duration `json:"ignorePendingPodsDuration,omitempty"`
 
+	// CoordinatorUnreachableDuration defines how long a coordinator can be
+	// reported as unreachable before the operator moves the coordinator to
+	// another process.
+	// Default: 2 minutes
+	CoordinatorUnreachableDuration time.Duration `json:"coordinatorUnreachableDuration,omitempty"`
+
 	// EnforceFullReplicationForDeletion defines if the operator is only allowed to delete Pods
 	// if the cluster is fully replicated. If the cluster is not fully replicated the Operator won't
 	// delete any Pods that are marked for removal.
@@ -1220,8 +1651,91 @@ type FoundationDBClusterAutomationOptions struct {
 	// +kubebuilder:validation:Enum=All;Zone;ProcessGroup
 	// +kubebuilder:default:=Zone
 	DeletionMode DeletionMode `json:"deletionMode,omitempty"`
+
+	// ManagePodDisruptionBudgets defines whether the operator should create
+	// and maintain a PodDisruptionBudget for every process class of the
+	// cluster. The budgets are derived from the fault tolerance of the
+	// redundancy mode.
+	// The default is false.
+	ManagePodDisruptionBudgets *bool `json:"managePodDisruptionBudgets,omitempty"`
+
+	// Freezes defines time-bound freezes for individual automated operations.
+	// While a freeze is active the operator will not perform the frozen
+	// operation, but the rest of the reconciliation continues.
+	// +kubebuilder:validation:MaxItems=100
+	Freezes []AutomationFreeze `json:"freezes,omitempty"`
+
+	// RuntimeKnobs defines the names of knobs, without the knob_ prefix,
+	// that can be changed while the processes are running. For versions that
+	// support the configuration database, the operator sets these knobs
+	// through the database instead of the monitor conf, so changing them
+	// does not require bouncing the processes.
+	// +kubebuilder:validation:MaxItems=100
+	RuntimeKnobs []string `json:"runtimeKnobs,omitempty"`
+
+	// StorageWiggle contains options for managing the perpetual storage
+	// wiggle.
+	StorageWiggle StorageWiggleOptions `json:"storageWiggle,omitempty"`
+
+	// ReplaceOnStorageEngineChange defines whether the operator should
+	// replace the storage process groups whose storage servers use a
+	// different storage engine than the database. This only applies when
+	// the database doesn't move the storage servers to the new storage
+	// engine through the perpetual storage wiggle.
+	// The default is false.
+	ReplaceOnStorageEngineChange *bool `json:"replaceOnStorageEngineChange,omitempty"`
+}
+
+// StorageWiggleOptions controls how the operator manages the perpetual
+// storage wiggle.
+type StorageWiggleOptions struct {
+	// PauseOutsideMaintenanceWindows defines whether the operator should
+	// pause the perpetual storage wiggle while the cluster is outside of its
+	// maintenance windows.
+	// The default is false.
+	PauseOutsideMaintenanceWindows *bool `json:"pauseOutsideMaintenanceWindows,omitempty"`
+
+	// UseForStorageEngineChanges defines whether the operator should let the
+	// perpetual storage wiggle move the storage servers to a new storage
+	// engine, instead of replacing the storage process groups. This only
+	// applies when the wiggle is enabled in the database configuration.
+	// The default is true.
+	UseForStorageEngineChanges *bool `json:"useForStorageEngineChanges,omitempty"`
 }
 
+// AutomationFreeze defines a freeze for an automated operation.
+type AutomationFreeze struct {
+	// Operation defines the operation that is frozen.
+	// +kubebuilder:validation:Enum=Exclusions;CoordinatorChanges;Replacements
+	Operation AutomationOperation `json:"operation"`
+
+	// Until defines when the freeze expires. If this is unset the freeze is
+	// active until it is removed from the spec.
+	Until *metav1.Time `json:"until,omitempty"`
+
+	// Reason provides a human readable explanation for the freeze, which is
+	// included in the events the operator emits.
+	Reason string `json:"reason,omitempty"`
+}
+
+// IsActive determines if the freeze is active at the given time.
+func (freeze AutomationFreeze) IsActive(now time.Time) bool {
+	return freeze.Until == nil || now.Before(freeze.Until.Time)
+}
+
+// AutomationOperation defines an automated operation that can be frozen.
+type AutomationOperation string
+
+const (
+	// AutomationOperationExclusions covers the exclusion of processes.
+	AutomationOperationExclusions AutomationOperation = "Exclusions"
+	// AutomationOperationCoordinatorChanges covers changes to the coordinators.
+	AutomationOperationCoordinatorChanges AutomationOperation = "CoordinatorChanges"
+	// AutomationOperationReplacements covers the automatic replacement of
+	// failed or misconfigured process groups.
+	AutomationOperationReplacements AutomationOperation = "Replacements"
+)
+
 // AutomaticReplacementOptions controls options for automatically replacing
 // failed processes.
 type AutomaticReplacementOptions struct {
@@ -1282,6 +1796,181 @@ func (processSettings *ProcessSettings) GetAllowTagOverride() bool {
 	return *processSettings.AllowTagOverride
 }
 
+// ProcessGroupParameterOverride defines custom parameters for a single process
+// group.
+type ProcessGroupParameterOverride struct {
+	// CustomParameters defines additional parameters to pass to the fdbserver
+	// processes in the process group. These replace parameters with the same
+	// name from the process settings.
+	CustomParameters FoundationDBCustomParameters `json:"customParameters,omitempty"`
+
+	// ExpirationTime defines when the operator should stop applying the
+	// override. If this is empty, the override is applied until it is removed
+	// from the spec.
+	ExpirationTime *metav1.Time `json:"expirationTime,omitempty"`
+}
+
+// IsExpired determines whether the override has passed its expiration time.
+func (override ProcessGroupParameterOverride) IsExpired(now time.Time) bool {
+	return override.ExpirationTime != nil && !now.Before(override.ExpirationTime.Time)
+}
+
+// GetActiveParameterOverride provides the parameter override for a process
+// group, if it has one that has not expired.
+func (cluster *FoundationDBCluster) GetActiveParameterOverride(processGroupID string) *ProcessGroupParameterOverride {
+	override, present := cluster.Spec.ProcessGroupParameterOverrides[processGroupID]
+	if !present || override.IsExpired(time.Now()) {
+		return nil
+	}
+
+	return &override
+}
+
+// HasParameterOverride determines whether the spec has a parameter override
+// for a process group, including overrides that have expired.
+func (cluster *FoundationDBCluster) HasParameterOverride(processGroupID string) bool {
+	_, present := cluster.Spec.ProcessGroupParameterOverrides[processGroupID]
+	return present
+}
+
+// GetActiveParameterOverrides returns all parameter overrides that have not
+// expired at the given time.
+func (cluster *FoundationDBCluster) GetActiveParameterOverrides(now time.Time) map[string]ProcessGroupParameterOverride {
+	var activeOverrides map[string]ProcessGroupParameterOverride
+	for processGroupID, override := range cluster.Spec.ProcessGroupParameterOverrides {
+		if override.IsExpired(now) {
+			continue
+		}
+
+		if activeOverrides == nil {
+			activeOverrides = make(map[string]ProcessGroupParameterOverride)
+		}
+		activeOverrides[processGroupID] = override
+	}
+
+	return activeOverrides
+}
+
+// GetNextParameterOverrideExpiration returns the earliest expiration time of
+// the parameter overrides that are active at the given time, or nil if none
+// of them expire.
+func (cluster *FoundationDBCluster) GetNextParameterOverrideExpiration(now time.Time) *time.Time {
+	var next *time.Time
+	for _, override := range cluster.GetActiveParameterOverrides(now) {
+		if override.ExpirationTime == nil {
+			continue
+		}
+
+		if next == nil || override.ExpirationTime.Time.Before(*next) {
+			expiration := override.ExpirationTime.Time
+			next = &expiration
+		}
+	}
+
+	return next
+}
+
+// GetProcessGroupCustomParameters provides the custom parameters for a process
+// group, merging the parameters from the process settings with the active
+// override for the process group.
+func (cluster *FoundationDBCluster) GetProcessGroupCustomParameters(processClass ProcessClass, processGroupID string) FoundationDBCustomParameters {
+	parameters := cluster.GetProcessSettings(processClass).CustomParameters
+	override := cluster.GetActiveParameterOverride(processGroupID)
+	if override == nil || len(override.CustomParameters) == 0 {
+		return parameters
+	}
+
+	overridden := make(map[string]bool, len(override.CustomParameters))
+	for _, parameter := range override.CustomParameters {
+		overridden[parameter.GetName()] = true
+	}
+
+	merged := make(FoundationDBCustomParameters, 0, len(parameters)+len(override.CustomParameters))
+	for _, parameter := range parameters {
+		if !overridden[parameter.GetName()] {
+			merged = append(merged, parameter)
+		}
+	}
+
+	return append(merged, override.CustomParameters...)
+}
+
+// GetRunningVersion provides the version that the cluster is running, falling
+// back to the version from the spec if the status has no running version.
+func (cluster *FoundationDBCluster) GetRunningVersion() string {
+	if cluster.Status.RunningVersion == "" {
+		return cluster.Spec.Version
+	}
+
+	return cluster.Status.RunningVersion
+}
+
+// GetRuntimeKnobs provides the knobs from the custom parameters that the
+// operator should set through the configuration database, keyed by the knob
+// name without the knob_ prefix.
+//
+// This only includes knobs that are listed in the RuntimeKnobs automation
+// option and that have the same value for every process and no parameter
+// override in the spec, since the operator sets them for the whole database.
+// Other knobs remain in the monitor conf.
+// This will be empty if the running version does not support runtime knobs.
+func (cluster *FoundationDBCluster) GetRuntimeKnobs() map[string]string {
+	if len(cluster.Spec.AutomationOptions.RuntimeKnobs) == 0 {
+		return nil
+	}
+
+	version, err := ParseFdbVersion(cluster.GetRunningVersion())
+	if err != nil || !version.SupportsRuntimeKnobs() {
+		return nil
+	}
+
+	processClasses := make([]ProcessClass, 0, len(cluster.Spec.Processes)+1)
+	processClasses = append(processClasses, ProcessClassGeneral)
+	for processClass := range cluster.Spec.Processes {
+		if processClass != ProcessClassGeneral {
+			processClasses = append(processClasses, processClass)
+		}
+	}
+
+	var knobs map[string]string
+	for _, name := range cluster.Spec.AutomationOptions.RuntimeKnobs {
+		parameterName := "knob_" + name
+		value, present := cluster.GetProcessSettings(ProcessClassGeneral).CustomParameters.GetValue(parameterName)
+		if !present {
+			continue
+		}
+
+		consistent := true
+		for _, processClass := range processClasses {
+			classValue, classPresent := cluster.GetProcessSettings(processClass).CustomParameters.GetValue(parameterName)
+			if !classPresent || classValue != value {
+				consistent = false
+				break
+			}
+		}
+
+		// Overrides count until they are removed from the spec, even after
+		// they have expired, so the knobs don't depend on the current time.
+		for _, override := range cluster.Spec.ProcessGroupParameterOverrides {
+			if _, overridden := override.CustomParameters.GetValue(parameterName); overridden {
+				consistent = false
+				break
+			}
+		}
+
+		if !consistent {
+			continue
+		}
+
+		if knobs == nil {
+			knobs = make(map[string]string)
+		}
+		knobs[name] = value
+	}
+
+	return knobs
+}
+
 // GetProcessSettings gets settings for a process.
 func (cluster *FoundationDBCluster) GetProcessSettings(processClass ProcessClass) ProcessSettings {
 	merged := ProcessSettings{}
@@ -1339,8 +2028,20 @@ func (cluster *FoundationDBCluster) GetRoleCountsWithDefaults() RoleCounts {
 	if counts.Logs == 0 {
 		counts.Logs = 3
 	}
-	if counts.Proxies == 0 {
-		counts.Proxies = 3
+	if cluster.UsesSeparatedProxies() {
+		if counts.GrvProxies == 0 {
+			counts.GrvProxies = 1
+		}
+		if counts.CommitProxies == 0 {
+			counts.CommitProxies = 2
+		}
+		counts.Proxies = 0
+	} else {
+		if counts.Proxies == 0 {
+			counts.Proxies = 3
+		}
+		counts.GrvProxies = 0
+		counts.CommitProxies = 0
 	}
 	if counts.Resolvers == 0 {
 		counts.Resolvers = 1
@@ -1362,6 +2063,22 @@ func (cluster *FoundationDBCluster) GetRoleCountsWithDefaults() RoleCounts {
 	return *counts
 }
 
+// UsesSeparatedProxies determines whether the database should be configured
+// with separate counts for GRV proxies and commit proxies rather than a single
+// proxy count.
+//
+// This requires a version of FDB that has separated proxies, and a GRV proxy
+// or commit proxy count in the spec.
+func (cluster *FoundationDBCluster) UsesSeparatedProxies() bool {
+	version, err := ParseFdbVersion(cluster.GetRunningVersion())
+	if err != nil || !version.HasSeparatedProxies() {
+		return false
+	}
+
+	counts := cluster.Spec.DatabaseConfiguration.RoleCounts
+	return counts.GrvProxies != 0 || counts.CommitProxies != 0
+}
+
 // calculateProcessCount determines the process count from a given role count.
 //
 // alternatives provides a list of other process counts that can fulfill this
@@ -1445,6 +2162,10 @@ func (cluster *FoundationDBCluster) GetProcessCountsWithDefaults() (ProcessCount
 		}
 	}
 
+	if cluster.UseDedicatedCoordinators() && processCounts.Coordinator == 0 {
+		processCounts.Coordinator = cluster.calculateProcessCount(true, cluster.DesiredCoordinatorCount())
+	}
+
 	if isSatellite && !isMain {
 		if processCounts.Log == 0 {
 			processCounts.Log = 1 + satelliteLogs
@@ -1470,8 +2191,13 @@ func (cluster *FoundationDBCluster) GetProcessCountsWithDefaults() (ProcessCount
 	if processCounts.Stateless == 0 {
 		primaryStatelessCount := cluster.calculateProcessCountFromRole(1, processCounts.Master) +
 			cluster.calculateProcessCountFromRole(1, processCounts.ClusterController) +
-			cluster.calculateProcessCountFromRole(roleCounts.Proxies, processCounts.Proxy) +
 			cluster.calculateProcessCountFromRole(roleCounts.Resolvers, processCounts.Resolution, processCounts.Resolver)
+		if cluster.UsesSeparatedProxies() {
+			primaryStatelessCount += cluster.calculateProcessCountFromRole(roleCounts.GrvProxies, processCounts.GrvProxy) +
+				cluster.calculateProcessCountFromRole(roleCounts.CommitProxies, processCounts.CommitProxy)
+		} else {
+			primaryStatelessCount += cluster.calculateProcessCountFromRole(roleCounts.Proxies, processCounts.Proxy)
+		}
 		if version.HasRatekeeperRole() {
 			primaryStatelessCount += cluster.calculateProcessCountFromRole(1, processCounts.Ratekeeper) +
 				cluster.calculateProcessCountFromRole(1, processCounts.DataDistributor)
@@ -1493,7 +2219,7 @@ func DesiredFaultTolerance(redundancyMode RedundancyMode) int {
 		return 0
 	case RedundancyModeDouble, RedundancyModeUnset:
 		return 1
-	case RedundancyModeTriple:
+	case RedundancyModeTriple, RedundancyModeThreeDataHall:
 		return 2
 	default:
 		return 0
@@ -1513,7 +2239,7 @@ func MinimumFaultDomains(redundancyMode RedundancyMode) int {
 		return 1
 	case RedundancyModeDouble, RedundancyModeUnset:
 		return 2
-	case RedundancyModeTriple:
+	case RedundancyModeTriple, RedundancyModeThreeDataHall:
 		return 3
 	default:
 		return 1
@@ -1533,6 +2259,12 @@ func (cluster *FoundationDBCluster) DesiredCoordinatorCount() int {
 		return 9
 	}
 
+	// In the three_data_hall mode we want to survive the loss of a data hall
+	// and an additional zone, so we recruit three coordinators per data hall.
+	if cluster.Spec.DatabaseConfiguration.RedundancyMode == RedundancyModeThreeDataHall {
+		return 9
+	}
+
 	return cluster.MinimumFaultDomains() + cluster.DesiredFaultTolerance()
 }
 
@@ -1634,6 +2366,18 @@ func (cluster *FoundationDBCluster) CheckReconciliation(log logr.Logger) (bool,
 		reconciled = false
 	}
 
+	if cluster.Status.TLSMigration != nil {
+		logger.Info("Pending TLS migration", "state", "HasExtraListeners", "phase", cluster.Status.TLSMigration.Phase)
+		cluster.Status.Generations.HasExtraListeners = cluster.ObjectMeta.Generation
+		reconciled = false
+	}
+
+	if !equality.Semantic.DeepEqual(cluster.Status.RuntimeKnobs, cluster.GetRuntimeKnobs()) {
+		logger.Info("Pending runtime knob update", "state", "NeedsRuntimeKnobUpdate")
+		cluster.Status.Generations.NeedsRuntimeKnobUpdate = cluster.ObjectMeta.Generation
+		reconciled = false
+	}
+
 	lockDenyMap := make(map[string]bool, len(cluster.Spec.LockOptions.DenyList))
 	for _, denyListEntry := range cluster.Spec.LockOptions.DenyList {
 		lockDenyMap[denyListEntry.ID] = denyListEntry.Allow
@@ -1680,6 +2424,29 @@ func (cluster *FoundationDBCluster) GetStorageServersPerPod() int {
 	return cluster.Spec.StorageServersPerPod
 }
 
+// GetLogServersPerPod returns the LogServers per Pod.
+func (cluster *FoundationDBCluster) GetLogServersPerPod() int {
+	if cluster.Spec.LogServersPerPod <= 1 {
+		return 1
+	}
+
+	return cluster.Spec.LogServersPerPod
+}
+
+// GetDesiredServersPerPod returns the number of processes that should run in
+// a Pod for the process class.
+func (cluster *FoundationDBCluster) GetDesiredServersPerPod(pClass ProcessClass) int {
+	if pClass == ProcessClassStorage {
+		return cluster.GetStorageServersPerPod()
+	}
+
+	if pClass.IsLogProcess() {
+		return cluster.GetLogServersPerPod()
+	}
+
+	return 1
+}
+
 // CountsAreSatisfied checks whether the current counts of processes satisfy
 // a desired set of counts.
 func (counts ProcessCounts) CountsAreSatisfied(currentCounts ProcessCounts) bool {
@@ -1769,10 +2536,11 @@ func (str *ConnectionString) GenerateNewGenerationID() error {
 
 // ProcessAddress provides a structured address for a process.
 type ProcessAddress struct {
-	IPAddress   net.IP          `json:"address,omitempty"`
-	Placeholder string          `json:"-"`
-	Port        int             `json:"port,omitempty"`
-	Flags       map[string]bool `json:"flags,omitempty"`
+	IPAddress     net.IP          `json:"address,omitempty"`
+	Placeholder   string          `json:"-"`
+	StringAddress string          `json:"-"`
+	Port          int             `json:"port,omitempty"`
+	Flags         map[string]bool `json:"flags,omitempty"`
 }
 
 // NewProcessAddress creates a new ProcessAddress if the provided placeholder is a valid IP address it will be set as
@@ -1798,7 +2566,7 @@ func NewProcessAddress(address net.IP, placeholder string, port int, flags map[s
 
 // IsEmpty returns true if a ProcessAddress is not set
 func (address ProcessAddress) IsEmpty() bool {
-	return address.IPAddress == nil
+	return address.IPAddress == nil && address.StringAddress == ""
 }
 
 // Equal checks if two ProcessAddress are the same
@@ -1807,6 +2575,10 @@ func (address ProcessAddress) Equal(addressB ProcessAddress) bool {
 		return false
 	}
 
+	if address.StringAddress != addressB.StringAddress {
+		return false
+	}
+
 	if address.Port != addressB.Port {
 		return false
 	}
@@ -1889,6 +2661,24 @@ func (address ProcessAddress) MarshalJSON() ([]byte, error) {
 	return []byte(fmt.Sprintf("\"%s\"", address.String())), nil
 }
 
+// isDNSName checks if the host is a valid DNS name. Labels that only consist
+// of digits are rejected, so that malformed IP addresses like 127.0.0.256
+// are not treated as DNS names.
+func isDNSName(host string) bool {
+	if len(validation.IsDNS1123Subdomain(host)) > 0 {
+		return false
+	}
+
+	for _, label := range strings.Split(host, ".") {
+		_, err := strconv.Atoi(label)
+		if err == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
 // ParseProcessAddress parses a structured address from its string
 // representation.
 func ParseProcessAddress(address string) (ProcessAddress, error) {
@@ -1916,6 +2706,11 @@ func ParseProcessAddress(address string) (ProcessAddress, error) {
 		// part of the ProcessAddress.
 		if err == nil {
 			result.IPAddress = net.ParseIP(addr)
+			// If the host is not an IP address it could be a DNS name
+			// e.g. for coordinators in a cluster file with DNS names.
+			if result.IPAddress == nil && isDNSName(addr) {
+				result.StringAddress = addr
+			}
 			iPort, err := strconv.Atoi(port)
 			if err != nil {
 				return result, err
@@ -1940,7 +2735,7 @@ func ParseProcessAddress(address string) (ProcessAddress, error) {
 		tmpStr = tmpStr[:idx]
 	}
 
-	if result.IPAddress == nil {
+	if result.IsEmpty() {
 		return result, fmt.Errorf("invalid address: %s", address)
 	}
 
@@ -1978,20 +2773,36 @@ func ParseProcessAddressesFromCmdline(cmdline string) ([]ProcessAddress, error)
 	return parseAddresses(strings.Split(res[1], ","))
 }
 
-// String gets the string representation of an address.
-func (address ProcessAddress) String() string {
+// host returns the host part of an address. This is either the placeholder,
+// the DNS name or the IP address.
+func (address ProcessAddress) host() string {
+	// We have to do this since we are creating a template file for the processes.
+	// The template file will contain variables like POD_IP which is not a valid net.IP :)
+	if address.Placeholder != "" {
+		return address.Placeholder
+	}
+
+	if address.StringAddress != "" {
+		return address.StringAddress
+	}
+
+	return address.IPAddress.String()
+}
+
+// IsDNSAddress returns true if the address uses a DNS name instead of an IP
+// address.
+func (address ProcessAddress) IsDNSAddress() bool {
+	return address.StringAddress != ""
+}
+
+// String gets the string representation of an address.
+func (address ProcessAddress) String() string {
 	if address.Port == 0 {
-		return address.IPAddress.String()
+		return address.host()
 	}
 
 	var sb strings.Builder
-	// We have to do this since we are creating a template file for the processes.
-	// The template file will contain variables like POD_IP which is not a valid net.IP :)
-	if address.Placeholder == "" {
-		sb.WriteString(net.JoinHostPort(address.IPAddress.String(), strconv.Itoa(address.Port)))
-	} else {
-		sb.WriteString(net.JoinHostPort(address.Placeholder, strconv.Itoa(address.Port)))
-	}
+	sb.WriteString(net.JoinHostPort(address.host(), strconv.Itoa(address.Port)))
 
 	flags := address.SortedFlags()
 
@@ -2005,10 +2816,10 @@ func (address ProcessAddress) String() string {
 // StringWithoutFlags gets the string representation of an address without flags.
 func (address ProcessAddress) StringWithoutFlags() string {
 	if address.Port == 0 {
-		return address.IPAddress.String()
+		return address.host()
 	}
 
-	return net.JoinHostPort(address.IPAddress.String(), strconv.Itoa(address.Port))
+	return net.JoinHostPort(address.host(), strconv.Itoa(address.Port))
 }
 
 // GetFullAddress gets the full public address we should use for a process.
@@ -2120,6 +2931,19 @@ type FoundationDBClusterFaultDomain struct {
 	// KCs in the data center. This is only used in the `kubernetes-cluster`
 	// fault domain strategy.
 	ZoneIndex int `json:"zoneIndex,omitempty"`
+
+	// DataHallKey provides a topology key for the data halls that contain the
+	// fault domains, e.g. `topology.kubernetes.io/zone`. If this is set, the
+	// operator spreads the pods of every process class evenly across the
+	// data halls. If this is not set and the DataHall reads a substitution
+	// variable from a node label, that label is used as the key.
+	DataHallKey string `json:"dataHallKey,omitempty"`
+
+	// UseTopologySpreadConstraints defines whether the operator should
+	// generate topology spread constraints for the fault domain key in
+	// addition to the pod anti-affinity rules.
+	// The default is false.
+	UseTopologySpreadConstraints *bool `json:"useTopologySpreadConstraints,omitempty"`
 }
 
 // RedundancyMode defines the core replication factor for the database
@@ -2133,6 +2957,9 @@ const (
 	RedundancyModeDouble RedundancyMode = "double"
 	// RedundancyModeTriple defines the replication factor 3.
 	RedundancyModeTriple RedundancyMode = "triple"
+	// RedundancyModeThreeDataHall defines the replication factor 3 with one
+	// replica in each of three data halls.
+	RedundancyModeThreeDataHall RedundancyMode = "three_data_hall"
 	// RedundancyModeUnset defines the replication factor unset.
 	RedundancyModeUnset RedundancyMode = ""
 )
@@ -2152,11 +2979,28 @@ const (
 	StorageEngineMemory2 StorageEngine = "memory-2"
 )
 
+// StorageMigrationType defines how the database moves existing storage
+// servers to a new storage engine.
+// +kubebuilder:validation:MaxLength=100
+type StorageMigrationType string
+
+const (
+	// StorageMigrationTypeDisabled defines that existing storage servers keep
+	// their storage engine until they are replaced.
+	StorageMigrationTypeDisabled StorageMigrationType = "disabled"
+	// StorageMigrationTypeAggressive defines that data distribution replaces
+	// all storage servers with the wrong storage engine right away.
+	StorageMigrationTypeAggressive StorageMigrationType = "aggressive"
+	// StorageMigrationTypeGradual defines that the perpetual storage wiggle
+	// moves the storage servers to the new storage engine one at a time.
+	StorageMigrationTypeGradual StorageMigrationType = "gradual"
+)
+
 // DatabaseConfiguration represents the configuration of the database
 type DatabaseConfiguration struct {
 	// RedundancyMode defines the core replication factor for the database.
 	// +kubebuilder:validation:Optional
-	// +kubebuilder:validation:Enum=single;double;triple
+	// +kubebuilder:validation:Enum=single;double;triple;three_data_hall
 	// +kubebuilder:default:double
 	RedundancyMode RedundancyMode `json:"redundancy_mode,omitempty"`
 
@@ -2166,6 +3010,24 @@ type DatabaseConfiguration struct {
 	// +kubebuilder:default:=ssd-2
 	StorageEngine StorageEngine `json:"storage_engine,omitempty"`
 
+	// PerpetualStorageWiggle defines whether the database continuously
+	// replaces its storage servers in the background, one process at a time.
+	// A value of 1 enables the wiggle and a value of 0 disables it. If this is
+	// unset the operator will not change the setting in the database.
+	// This requires FoundationDB 7.0 or later.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	PerpetualStorageWiggle *int `json:"perpetual_storage_wiggle,omitempty"`
+
+	// StorageMigrationType defines how the database moves existing storage
+	// servers to a new storage engine. If this is unset and the perpetual
+	// storage wiggle is enabled, the operator uses the gradual migration
+	// unless this is disabled in the automation options.
+	// This requires FoundationDB 7.0 or later.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=disabled;aggressive;gradual
+	StorageMigrationType *StorageMigrationType `json:"storage_migration_type,omitempty"`
+
 	// UsableRegions defines how many regions the database should store data in.
 	UsableRegions int `json:"usable_regions,omitempty"`
 
@@ -2312,11 +3174,28 @@ func (configuration DatabaseConfiguration) GetConfigurationString() (string, err
 	configurationString := fmt.Sprintf("%s %s", configuration.RedundancyMode, configuration.StorageEngine)
 
 	counts := configuration.RoleCounts.Map()
+	usesSeparatedProxies := configuration.GrvProxies != 0 || configuration.CommitProxies != 0
 	configurationString += fmt.Sprintf(" usable_regions=%d", configuration.UsableRegions)
 	for _, role := range roleNames {
-		if role != ProcessClassStorage {
-			configurationString += fmt.Sprintf(" %s=%d", role, counts[role])
+		if role == ProcessClassStorage {
+			continue
+		}
+		// Only one kind of proxy configuration is in use at a time, so we
+		// leave out the counts for the kind that is not in use.
+		if usesSeparatedProxies && role == "proxies" && counts[role] == 0 {
+			continue
+		}
+		if !usesSeparatedProxies && (role == "grv_proxies" || role == "commit_proxies") {
+			continue
 		}
+		configurationString += fmt.Sprintf(" %s=%d", role, counts[role])
+	}
+
+	if configuration.PerpetualStorageWiggle != nil {
+		configurationString += fmt.Sprintf(" perpetual_storage_wiggle=%d", *configuration.PerpetualStorageWiggle)
+	}
+	if configuration.StorageMigrationType != nil {
+		configurationString += fmt.Sprintf(" storage_migration_type=%s", *configuration.StorageMigrationType)
 	}
 
 	flags := configuration.VersionFlags.Map()
@@ -2345,7 +3224,7 @@ func (configuration DatabaseConfiguration) GetConfigurationString() (string, err
 // DesiredDatabaseConfiguration builds the database configuration for the
 // cluster based on its spec.
 func (cluster *FoundationDBCluster) DesiredDatabaseConfiguration() DatabaseConfiguration {
-	configuration := cluster.Spec.DatabaseConfiguration.NormalizeConfiguration()
+	configuration := cluster.Spec.DatabaseConfiguration.WithPrimaryDataCenter(cluster.Spec.PrimaryDataCenter).NormalizeConfiguration()
 
 	configuration.RoleCounts = cluster.GetRoleCountsWithDefaults()
 	configuration.RoleCounts.Storage = 0
@@ -2355,9 +3234,105 @@ func (cluster *FoundationDBCluster) DesiredDatabaseConfiguration() DatabaseConfi
 	if configuration.StorageEngine == StorageEngineMemory {
 		configuration.StorageEngine = StorageEngineMemory2
 	}
+
+	runningVersion, err := ParseFdbVersion(cluster.GetRunningVersion())
+	if err != nil || !runningVersion.HasPerpetualStorageWiggle() {
+		configuration.PerpetualStorageWiggle = nil
+		configuration.StorageMigrationType = nil
+	} else if pointer.IntDeref(configuration.PerpetualStorageWiggle, 0) == 1 {
+		if configuration.StorageMigrationType == nil && cluster.GetUseStorageWiggleForStorageEngineChanges() {
+			migrationType := StorageMigrationTypeGradual
+			configuration.StorageMigrationType = &migrationType
+		}
+	}
+
 	return configuration
 }
 
+// ClearMissingStorageWiggleOptions clears the storage wiggle options in the
+// given configuration that the operator does not manage for this cluster.
+//
+// This allows us to compare the spec to the live configuration while ignoring
+// storage wiggle options that are unset in the spec.
+func (cluster *FoundationDBCluster) ClearMissingStorageWiggleOptions(configuration *DatabaseConfiguration) {
+	desiredConfiguration := cluster.DesiredDatabaseConfiguration()
+	if desiredConfiguration.PerpetualStorageWiggle == nil {
+		configuration.PerpetualStorageWiggle = nil
+	}
+	if desiredConfiguration.StorageMigrationType == nil {
+		configuration.StorageMigrationType = nil
+	}
+}
+
+// ClearUnusedProxyCounts clears the proxy counts in the given configuration
+// for the kind of proxy configuration that the spec does not use.
+//
+// FDB 7.0 and later always report the GRV proxy and commit proxy counts, and
+// no longer report a single proxy count, so when the spec only has a proxy
+// count we compare it to the sum of the reported counts. This allows us to
+// compare the spec to the live configuration.
+func (cluster *FoundationDBCluster) ClearUnusedProxyCounts(configuration *DatabaseConfiguration) {
+	if cluster.UsesSeparatedProxies() {
+		configuration.Proxies = 0
+		return
+	}
+
+	if configuration.Proxies == 0 {
+		configuration.Proxies = configuration.GrvProxies + configuration.CommitProxies
+	}
+	configuration.GrvProxies = 0
+	configuration.CommitProxies = 0
+}
+
+// IgnorePausedStorageWiggle sets the perpetual storage wiggle in the given
+// configuration to the desired value when the operator pauses the wiggle
+// outside of maintenance windows.
+//
+// This allows us to compare the spec to the live configuration while ignoring
+// whether the wiggle is paused right now.
+func (cluster *FoundationDBCluster) IgnorePausedStorageWiggle(configuration *DatabaseConfiguration) {
+	if !cluster.GetPauseStorageWiggleOutsideMaintenanceWindows() || pointer.IntDeref(configuration.PerpetualStorageWiggle, 1) != 0 {
+		return
+	}
+
+	if cluster.CanRunStorageWiggle() {
+		configuration.PerpetualStorageWiggle = pointer.Int(1)
+	}
+}
+
+// IsStorageWigglePaused determines if the operator pauses the perpetual
+// storage wiggle at the given time, because the cluster is outside of its
+// maintenance windows.
+func (cluster *FoundationDBCluster) IsStorageWigglePaused(now time.Time) bool {
+	if !cluster.GetPauseStorageWiggleOutsideMaintenanceWindows() {
+		return false
+	}
+
+	open, _, err := cluster.IsInMaintenanceWindow(now)
+	return err == nil && !open
+}
+
+// UsesDatabaseStorageMigration determines if the database moves the storage
+// servers to a new storage engine by itself, so the operator does not have to
+// replace the storage process groups after the storage engine changes.
+func (cluster *FoundationDBCluster) UsesDatabaseStorageMigration() bool {
+	migrationType := cluster.DesiredDatabaseConfiguration().StorageMigrationType
+	if migrationType == nil || *migrationType == StorageMigrationTypeDisabled {
+		return false
+	}
+
+	return cluster.CanRunStorageWiggle()
+}
+
+// CanRunStorageWiggle determines if the perpetual storage wiggle is able to
+// run for the cluster. When the operator pauses the wiggle outside of
+// maintenance windows, the wiggle still runs during the windows, or all the
+// time when no windows are defined, so this doesn't depend on whether the
+// wiggle is paused right now.
+func (cluster *FoundationDBCluster) CanRunStorageWiggle() bool {
+	return pointer.IntDeref(cluster.DesiredDatabaseConfiguration().PerpetualStorageWiggle, 0) == 1
+}
+
 // ClearMissingVersionFlags clears any version flags in the given configuration that are not
 // set in the configuration in the cluster spec.
 //
@@ -2487,6 +3462,37 @@ func (cluster *FoundationDBCluster) GetPublicIPSource() PublicIPSource {
 	return *source
 }
 
+// UseDNSInClusterFile determines whether we need to use DNS names in the
+// cluster file for the coordinators.
+func (cluster *FoundationDBCluster) UseDNSInClusterFile() bool {
+	if !pointer.BoolDeref(cluster.Spec.Routing.UseDNSInClusterFile, false) {
+		return false
+	}
+
+	version, err := ParseFdbVersion(cluster.Spec.Version)
+	if err != nil {
+		return false
+	}
+
+	return version.SupportsDNSInClusterFile()
+}
+
+// GetDNSDomain gets the domain used when forming DNS names generated for a
+// service.
+func (cluster *FoundationDBCluster) GetDNSDomain() string {
+	if cluster.Spec.Routing.DNSDomain == nil {
+		return "cluster.local"
+	}
+
+	return *cluster.Spec.Routing.DNSDomain
+}
+
+// NeedsHeadlessService determines whether the cluster needs a headless
+// service.
+func (cluster *FoundationDBCluster) NeedsHeadlessService() bool {
+	return pointer.BoolDeref(cluster.Spec.Routing.HeadlessService, false) || cluster.UseDNSInClusterFile()
+}
+
 // FillInDefaultsFromStatus adds in missing fields from the database
 // configuration in the database status to make sure they match the fields that
 // will appear in the cluster spec.
@@ -2861,6 +3867,32 @@ type LockDenyListEntry struct {
 	Allow bool `json:"allow,omitempty"`
 }
 
+// OperatorInstance describes an instance of the operator that manages the
+// processes of a cluster in one Kubernetes cluster. The instances register
+// themselves in the database when locks are enabled, so they can coordinate
+// global operations.
+type OperatorInstance struct {
+	// ID provides the lock ID of the instance.
+	ID string `json:"id"`
+
+	// DataCenter provides the data center that the instance manages.
+	DataCenter string `json:"dataCenter,omitempty"`
+
+	// DatabaseConfigurationHash provides a hash of the database configuration
+	// that the instance wants to apply.
+	DatabaseConfigurationHash string `json:"databaseConfigurationHash,omitempty"`
+
+	// Timestamp provides the time when the instance last registered itself,
+	// as a Unix timestamp.
+	Timestamp int64 `json:"timestamp,omitempty"`
+}
+
+// IsActive determines whether the instance has registered itself within the
+// duration of a lock.
+func (instance OperatorInstance) IsActive(cluster *FoundationDBCluster, now time.Time) bool {
+	return now.Sub(time.Unix(instance.Timestamp, 0)) < cluster.GetLockDuration()
+}
+
 // ServiceConfig allows configuring services that sit in front of our pods.
 // Deprecated: Use RoutingConfig instead.
 type ServiceConfig struct {
@@ -2875,6 +3907,225 @@ type ServiceConfig struct {
 	PublicIPSource *PublicIPSource `json:"publicIPSource,omitempty"`
 }
 
+// ClientLibraryConfig describes the client libraries the operator publishes for
+// application pods.
+type ClientLibraryConfig struct {
+	// Enabled determines if the operator publishes the client libraries for
+	// the running version and the target version of the cluster.
+	//
+	// This defaults to false.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// AdditionalVersions provides versions whose client libraries are
+	// published in addition to the running version and the target version.
+	// +kubebuilder:validation:MaxItems=10
+	AdditionalVersions []string `json:"additionalVersions,omitempty"`
+}
+
+// ClientConfigTarget describes a config map or secret where the operator
+// publishes the connection string of the cluster.
+type ClientConfigTarget struct {
+	// Namespace defines the namespace of the config map or secret.
+	Namespace string `json:"namespace"`
+
+	// Name defines the name of the config map or secret.
+	//
+	// This defaults to the name of the cluster with the suffix
+	// -client-config.
+	Name string `json:"name,omitempty"`
+
+	// Kind defines whether the operator publishes the connection string in
+	// a config map or in a secret.
+	//
+	// This defaults to ConfigMap.
+	// +kubebuilder:validation:Enum=ConfigMap;Secret
+	Kind ClientConfigTargetKind `json:"kind,omitempty"`
+
+	// IncludeTrustedCAs defines whether the operator also publishes the
+	// trusted CAs from the cluster spec as a CA bundle.
+	IncludeTrustedCAs bool `json:"includeTrustedCAs,omitempty"`
+}
+
+// ClientConfigTargetKind describes the kind of object the connection string is
+// published to.
+type ClientConfigTargetKind string
+
+const (
+	// ClientConfigTargetConfigMap publishes the connection string to a
+	// config map.
+	ClientConfigTargetConfigMap ClientConfigTargetKind = "ConfigMap"
+
+	// ClientConfigTargetSecret publishes the connection string to a secret.
+	ClientConfigTargetSecret ClientConfigTargetKind = "Secret"
+)
+
+// GetKind returns the kind of object the connection string is published to,
+// applying the default.
+func (target ClientConfigTarget) GetKind() ClientConfigTargetKind {
+	if target.Kind == "" {
+		return ClientConfigTargetConfigMap
+	}
+	return target.Kind
+}
+
+// GetName returns the name of the config map or secret, applying the default.
+func (target ClientConfigTarget) GetName(clusterName string) string {
+	if target.Name == "" {
+		return fmt.Sprintf("%s-client-config", clusterName)
+	}
+	return target.Name
+}
+
+// Key returns a string that identifies the published object.
+func (target ClientConfigTarget) Key(clusterName string) string {
+	return fmt.Sprintf("%s/%s/%s", target.GetKind(), target.Namespace, target.GetName(clusterName))
+}
+
+// TLSCertificateConfig defines where the processes in a cluster get their TLS
+// certificates from.
+type TLSCertificateConfig struct {
+	// SecretName defines the name of the secret that holds the certificate in
+	// the tls.crt key and the private key in the tls.key key.
+	//
+	// This defaults to the name of the cluster with the suffix -tls.
+	SecretName string `json:"secretName,omitempty"`
+
+	// Issuer defines the name of a certificate issuer that is registered with
+	// the operator. When this is set, the operator requests certificates from
+	// the issuer and stores them in the secret.
+	Issuer string `json:"issuer,omitempty"`
+
+	// RenewBeforeSeconds defines how long before the expiry of the
+	// certificate the operator requests a new certificate from the issuer.
+	//
+	// This defaults to 30 days.
+	// +kubebuilder:validation:Minimum=0
+	RenewBeforeSeconds *int `json:"renewBeforeSeconds,omitempty"`
+}
+
+// GetSecretName returns the name of the secret that holds the TLS
+// certificates, applying the default.
+func (config TLSCertificateConfig) GetSecretName(clusterName string) string {
+	if config.SecretName == "" {
+		return fmt.Sprintf("%s-tls", clusterName)
+	}
+	return config.SecretName
+}
+
+// GetRenewBefore returns how long before the expiry of the certificate the
+// operator requests a new certificate, applying the default.
+func (config TLSCertificateConfig) GetRenewBefore() time.Duration {
+	return time.Duration(pointer.IntDeref(config.RenewBeforeSeconds, 30*24*60*60)) * time.Second
+}
+
+// SubstitutionVariable defines a variable that is available for substitution
+// in the custom parameters and locality settings as $Name. At most one source
+// can be set. When no source is set, the variable must be defined in the
+// environment of the containers through the pod template.
+type SubstitutionVariable struct {
+	// Name provides the name of the variable.
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_]*$`
+	// +kubebuilder:validation:MaxLength=100
+	Name string `json:"name"`
+
+	// NodeLabel defines a label on the node that the pod runs on. The
+	// operator copies the label to an annotation on the pod once the pod is
+	// scheduled, and an init container holds back the other containers until
+	// the annotation is present.
+	NodeLabel string `json:"nodeLabel,omitempty"`
+
+	// PodAnnotation defines an annotation on the pod.
+	PodAnnotation string `json:"podAnnotation,omitempty"`
+
+	// FieldPath defines a field of the pod that is provided through the
+	// downward API, like spec.nodeName.
+	FieldPath string `json:"fieldPath,omitempty"`
+}
+
+// GetNodeLabelAnnotation returns the annotation on the pod that holds the
+// value of the node label for this variable.
+func (variable SubstitutionVariable) GetNodeLabelAnnotation() string {
+	return SubstitutionVariableAnnotationPrefix + strings.ToLower(strings.ReplaceAll(variable.Name, "_", "-"))
+}
+
+// SidecarAuthenticationMode describes how the operator authenticates its
+// requests to the sidecar.
+// +kubebuilder:validation:Enum=MutualTLS;Token
+type SidecarAuthenticationMode string
+
+const (
+	// SidecarAuthenticationModeMutualTLS defines that the operator presents
+	// a client certificate, which the sidecar checks against its peer
+	// verification rules.
+	SidecarAuthenticationModeMutualTLS SidecarAuthenticationMode = "MutualTLS"
+
+	// SidecarAuthenticationModeToken defines that the operator sends a
+	// shared token with every request, in addition to the client certificate
+	// when the sidecar uses TLS.
+	SidecarAuthenticationModeToken SidecarAuthenticationMode = "Token"
+)
+
+// SidecarAuthenticationConfig defines how the operator authenticates its
+// requests to the sidecar.
+type SidecarAuthenticationConfig struct {
+	// Mode defines how the operator authenticates its requests. When this is
+	// set to MutualTLS, the sidecar container must have TLS and peer
+	// verification rules enabled.
+	//
+	// This defaults to MutualTLS.
+	Mode SidecarAuthenticationMode `json:"mode,omitempty"`
+
+	// CertificateFile defines the path to the client certificate in the
+	// operator container.
+	//
+	// This defaults to the FDB_TLS_CERTIFICATE_FILE environment variable.
+	CertificateFile string `json:"certificateFile,omitempty"`
+
+	// KeyFile defines the path to the private key for the client certificate
+	// in the operator container.
+	//
+	// This defaults to the FDB_TLS_KEY_FILE environment variable.
+	KeyFile string `json:"keyFile,omitempty"`
+
+	// CAFile defines the path to the CA file that the operator uses to
+	// verify the sidecar's certificate.
+	//
+	// This defaults to the FDB_TLS_CA_FILE environment variable.
+	CAFile string `json:"caFile,omitempty"`
+
+	// TokenFile defines the path to the file in the operator container that
+	// holds the token for the Token mode.
+	//
+	// This defaults to the FDB_SIDECAR_TOKEN_FILE environment variable.
+	TokenFile string `json:"tokenFile,omitempty"`
+
+	// TokenSecretName defines the name of the secret that provides the token
+	// to the sidecars in the token key. The secret must contain the same
+	// token as the token file.
+	//
+	// This defaults to the name of the cluster with the suffix
+	// -sidecar-token.
+	TokenSecretName string `json:"tokenSecretName,omitempty"`
+}
+
+// GetMode returns how the operator authenticates its requests to the sidecar,
+// applying the default.
+func (config *SidecarAuthenticationConfig) GetMode() SidecarAuthenticationMode {
+	if config == nil || config.Mode == "" {
+		return SidecarAuthenticationModeMutualTLS
+	}
+	return config.Mode
+}
+
+// GetTokenSecretName returns the name of the secret that provides the token
+// to the sidecars, applying the default.
+func (config *SidecarAuthenticationConfig) GetTokenSecretName(clusterName string) string {
+	if config == nil || config.TokenSecretName == "" {
+		return fmt.Sprintf("%s-sidecar-token", clusterName)
+	}
+	return config.TokenSecretName
+}
+
 // RoutingConfig allows configuring routing to our pods, and services that sit
 // in front of them.
 type RoutingConfig struct {
@@ -2893,6 +4144,17 @@ type RoutingConfig struct {
 	// This feature is only supported in FDB 7.0 or later, and requires
 	// dual-stack support in your Kubernetes environment.
 	PodIPFamily *int `json:"podIPFamily,omitempty"`
+
+	// UseDNSInClusterFile determines whether to use DNS names rather than IP
+	// addresses to identify coordinators in the cluster file.
+	// This requires FoundationDB 7.0+ and will create a headless service for
+	// the cluster.
+	// Default: false
+	UseDNSInClusterFile *bool `json:"useDNSInClusterFile,omitempty"`
+
+	// DNSDomain defines the cluster domain used in the DNS names of the pods.
+	// Default: cluster.local
+	DNSDomain *string `json:"dnsDomain,omitempty"`
 }
 
 // RequiredAddressSet provides settings for which addresses we need to listen
@@ -2977,6 +4239,18 @@ const (
 	ProcessClassClusterController ProcessClass = "cluster_controller"
 	// ProcessClassTest model for FDB class test
 	ProcessClassTest ProcessClass = "test"
+	// ProcessClassGrvProxy model for FDB class grv_proxy
+	ProcessClassGrvProxy ProcessClass = "grv_proxy"
+	// ProcessClassCommitProxy model for FDB class commit_proxy
+	ProcessClassCommitProxy ProcessClass = "commit_proxy"
+	// ProcessClassCoordinator model for FDB class coordinator
+	ProcessClassCoordinator ProcessClass = "coordinator"
+	// ProcessClassRatekeeper model for FDB class ratekeeper
+	ProcessClassRatekeeper ProcessClass = "ratekeeper"
+	// ProcessClassDataDistributor model for FDB class data_distributor
+	ProcessClassDataDistributor ProcessClass = "data_distributor"
+	// ProcessClassBackup model for FDB class backup
+	ProcessClassBackup ProcessClass = "backup"
 )
 
 // IsStateful determines whether a process class should store data.
@@ -2984,6 +4258,11 @@ func (pClass ProcessClass) IsStateful() bool {
 	return pClass == ProcessClassStorage || pClass == ProcessClassLog || pClass == ProcessClassTransaction
 }
 
+// IsLogProcess determines whether a process class runs log servers.
+func (pClass ProcessClass) IsLogProcess() bool {
+	return pClass == ProcessClassLog || pClass == ProcessClassTransaction
+}
+
 // AddStorageServerPerDisk adds serverPerDisk to the status field to keep track which ConfigMaps should be kept
 func (clusterStatus *FoundationDBClusterStatus) AddStorageServerPerDisk(serversPerDisk int) {
 	for _, curServersPerDisk := range clusterStatus.StorageServersPerDisk {
@@ -2995,6 +4274,17 @@ func (clusterStatus *FoundationDBClusterStatus) AddStorageServerPerDisk(serversP
 	clusterStatus.StorageServersPerDisk = append(clusterStatus.StorageServersPerDisk, serversPerDisk)
 }
 
+// AddLogServerPerDisk adds serverPerDisk to the status field to keep track which ConfigMaps should be kept
+func (clusterStatus *FoundationDBClusterStatus) AddLogServerPerDisk(serversPerDisk int) {
+	for _, curServersPerDisk := range clusterStatus.LogServersPerDisk {
+		if curServersPerDisk == serversPerDisk {
+			return
+		}
+	}
+
+	clusterStatus.LogServersPerDisk = append(clusterStatus.LogServersPerDisk, serversPerDisk)
+}
+
 // GetMaxConcurrentAutomaticReplacements returns the cluster setting for MaxConcurrentReplacements, defaults to 1 if unset.
 func (cluster *FoundationDBCluster) GetMaxConcurrentAutomaticReplacements() int {
 	return pointer.IntDeref(cluster.Spec.AutomationOptions.Replacements.MaxConcurrentReplacements, 1)
@@ -3010,6 +4300,10 @@ type CoordinatorSelectionSetting struct {
 // IsEligibleAsCandidate checks if the given process has the right process class to be considered a valid coordinator.
 // This method will always return false for non stateful process classes.
 func (cluster *FoundationDBCluster) IsEligibleAsCandidate(pClass ProcessClass) bool {
+	if cluster.UseDedicatedCoordinators() {
+		return cluster.IsDedicatedCoordinator(pClass)
+	}
+
 	if !pClass.IsStateful() {
 		return false
 	}
@@ -3027,6 +4321,18 @@ func (cluster *FoundationDBCluster) IsEligibleAsCandidate(pClass ProcessClass) b
 	return false
 }
 
+// UseDedicatedCoordinators determines whether the coordinators should run in
+// dedicated process groups with the coordinator process class.
+func (cluster *FoundationDBCluster) UseDedicatedCoordinators() bool {
+	return pointer.BoolDeref(cluster.Spec.UseDedicatedCoordinators, false)
+}
+
+// IsDedicatedCoordinator determines whether the process class is the
+// coordinator class and the cluster uses dedicated coordinators.
+func (cluster *FoundationDBCluster) IsDedicatedCoordinator(pClass ProcessClass) bool {
+	return pClass == ProcessClassCoordinator && cluster.UseDedicatedCoordinators()
+}
+
 // GetClassCandidatePriority returns the priority for a class. This will be used to sort the processes for coordinator selection
 func (cluster *FoundationDBCluster) GetClassCandidatePriority(pClass ProcessClass) int {
 	for _, setting := range cluster.Spec.CoordinatorSelection {
@@ -3067,11 +4373,129 @@ func (cluster *FoundationDBCluster) GetIgnorePendingPodsDuration() time.Duration
 	return cluster.Spec.AutomationOptions.IgnorePendingPodsDuration
 }
 
+// GetCoordinatorUnreachableDuration returns the value of CoordinatorUnreachableDuration or 2 minutes if unset.
+func (cluster *FoundationDBCluster) GetCoordinatorUnreachableDuration() time.Duration {
+	if cluster.Spec.AutomationOptions.CoordinatorUnreachableDuration == 0 {
+		return 2 * time.Minute
+	}
+
+	return cluster.Spec.AutomationOptions.CoordinatorUnreachableDuration
+}
+
+// GetActiveFreeze returns the freeze that is active for the operation at the
+// given time. If multiple freezes are active, the one that expires last is
+// returned. If no freeze is active this will return nil.
+func (cluster *FoundationDBCluster) GetActiveFreeze(operation AutomationOperation, now time.Time) *AutomationFreeze {
+	var activeFreeze *AutomationFreeze
+	for index, freeze := range cluster.Spec.AutomationOptions.Freezes {
+		if freeze.Operation != operation || !freeze.IsActive(now) {
+			continue
+		}
+
+		if activeFreeze == nil || freeze.Until == nil || (activeFreeze.Until != nil && freeze.Until.After(activeFreeze.Until.Time)) {
+			activeFreeze = &cluster.Spec.AutomationOptions.Freezes[index]
+		}
+
+		if activeFreeze.Until == nil {
+			break
+		}
+	}
+
+	return activeFreeze
+}
+
+// GetActiveFreezes returns all freezes that are active at the given time.
+func (cluster *FoundationDBCluster) GetActiveFreezes(now time.Time) []AutomationFreeze {
+	var activeFreezes []AutomationFreeze
+	for _, freeze := range cluster.Spec.AutomationOptions.Freezes {
+		if freeze.IsActive(now) {
+			activeFreezes = append(activeFreezes, freeze)
+		}
+	}
+
+	return activeFreezes
+}
+
+// IsInMaintenanceWindow determines if the operator is allowed to perform
+// disruptive operations at the given time. If no maintenance windows are
+// defined this is always true. If the cluster is outside of its maintenance
+// windows this also returns the time when the next window opens.
+func (cluster *FoundationDBCluster) IsInMaintenanceWindow(now time.Time) (bool, time.Time, error) {
+	if len(cluster.Spec.MaintenanceWindows) == 0 {
+		return true, time.Time{}, nil
+	}
+
+	var nextStart time.Time
+	for _, window := range cluster.Spec.MaintenanceWindows {
+		open, start, err := window.IsOpen(now)
+		if err != nil {
+			return false, time.Time{}, err
+		}
+
+		if open {
+			return true, time.Time{}, nil
+		}
+
+		if !start.IsZero() && (nextStart.IsZero() || start.Before(nextStart)) {
+			nextStart = start
+		}
+	}
+
+	return false, nextStart, nil
+}
+
+// GetMaintenanceWindowEnd determines when the currently open maintenance
+// windows close. If the cluster is outside of its maintenance windows, or has
+// no maintenance windows, this returns the zero time.
+func (cluster *FoundationDBCluster) GetMaintenanceWindowEnd(now time.Time) (time.Time, error) {
+	var end time.Time
+	for _, window := range cluster.Spec.MaintenanceWindows {
+		closesAt, err := window.ClosesAt(now)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		if closesAt.After(end) {
+			end = closesAt
+		}
+	}
+
+	return end, nil
+}
+
+// GetPauseStorageWiggleOutsideMaintenanceWindows returns the value of
+// pauseOutsideMaintenanceWindows for the storage wiggle or false if unset.
+func (cluster *FoundationDBCluster) GetPauseStorageWiggleOutsideMaintenanceWindows() bool {
+	return pointer.BoolDeref(cluster.Spec.AutomationOptions.StorageWiggle.PauseOutsideMaintenanceWindows, false)
+}
+
+// GetUseStorageWiggleForStorageEngineChanges returns the value of
+// useForStorageEngineChanges for the storage wiggle or true if unset.
+func (cluster *FoundationDBCluster) GetUseStorageWiggleForStorageEngineChanges() bool {
+	return pointer.BoolDeref(cluster.Spec.AutomationOptions.StorageWiggle.UseForStorageEngineChanges, true)
+}
+
+// GetReplaceOnStorageEngineChange returns the value of
+// replaceOnStorageEngineChange or false if unset.
+func (cluster *FoundationDBCluster) GetReplaceOnStorageEngineChange() bool {
+	return pointer.BoolDeref(cluster.Spec.AutomationOptions.ReplaceOnStorageEngineChange, false)
+}
+
 // GetEnforceFullReplicationForDeletion returns the value of enforceFullReplicationForDeletion or true if unset.
 func (cluster *FoundationDBCluster) GetEnforceFullReplicationForDeletion() bool {
 	return pointer.BoolDeref(cluster.Spec.AutomationOptions.EnforceFullReplicationForDeletion, true)
 }
 
+// GetUseTopologySpreadConstraints returns the value of useTopologySpreadConstraints or false if unset.
+func (cluster *FoundationDBCluster) GetUseTopologySpreadConstraints() bool {
+	return pointer.BoolDeref(cluster.Spec.FaultDomain.UseTopologySpreadConstraints, false)
+}
+
+// GetManagePodDisruptionBudgets returns the value of managePodDisruptionBudgets or false if unset.
+func (cluster *FoundationDBCluster) GetManagePodDisruptionBudgets() bool {
+	return pointer.BoolDeref(cluster.Spec.AutomationOptions.ManagePodDisruptionBudgets, false)
+}
+
 // GetUseNonBlockingExcludes returns the value of useNonBlockingExcludes or false if unset.
 func (cluster *FoundationDBCluster) GetUseNonBlockingExcludes() bool {
 	if cluster.Spec.AutomationOptions.UseNonBlockingExcludes == nil {
@@ -3118,6 +4542,75 @@ const (
 	DeletionModeProcessGroup DeletionMode = "ProcessGroup"
 )
 
+// GetPrimaryDataCenter returns the main data center with the highest
+// priority. This returns an empty string if no regions are configured.
+func (configuration DatabaseConfiguration) GetPrimaryDataCenter() string {
+	primary := ""
+	primaryPriority := 0
+	for _, region := range configuration.Regions {
+		id, priority := getMainDataCenter(region)
+		if id == "" {
+			continue
+		}
+
+		if primary == "" || priority > primaryPriority {
+			primary = id
+			primaryPriority = priority
+		}
+	}
+
+	return primary
+}
+
+// HasMainDataCenter determines if the data center with the given ID is the
+// main data center of one of the regions.
+func (configuration DatabaseConfiguration) HasMainDataCenter(id string) bool {
+	for _, region := range configuration.Regions {
+		mainID, _ := getMainDataCenter(region)
+		if mainID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WithPrimaryDataCenter returns a new DatabaseConfiguration where the main
+// data center with the given ID has the highest priority. This swaps the
+// priorities of the given data center and the current primary. If the data
+// center is not the main data center of a region, the configuration is
+// returned unchanged.
+func (configuration DatabaseConfiguration) WithPrimaryDataCenter(id string) DatabaseConfiguration {
+	newConfiguration := configuration.DeepCopy()
+	currentPrimary := configuration.GetPrimaryDataCenter()
+	if id == "" || id == currentPrimary || !configuration.HasMainDataCenter(id) {
+		return *newConfiguration
+	}
+
+	priorities := configuration.getRegionPriorities()
+	newPriority := priorities[currentPrimary]
+	currentPriority := priorities[id]
+	if newPriority == currentPriority {
+		newPriority++
+	}
+
+	for _, region := range newConfiguration.Regions {
+		for dataCenterIndex, dataCenter := range region.DataCenters {
+			if dataCenter.Satellite != 0 {
+				continue
+			}
+
+			if dataCenter.ID == id {
+				region.DataCenters[dataCenterIndex].Priority = newPriority
+			} else if dataCenter.ID == currentPrimary {
+				region.DataCenters[dataCenterIndex].Priority = currentPriority
+			}
+		}
+	}
+
+	return *newConfiguration
+}
+
 // FailOver returns a new DatabaseConfiguration that switches the priority for the main and remote DC
 func (configuration *DatabaseConfiguration) FailOver() DatabaseConfiguration {
 	if len(configuration.Regions) <= 1 {
@@ -3172,3 +4665,9 @@ func (configuration *DatabaseConfiguration) FailOver() DatabaseConfiguration {
 
 	return *newConfiguration
 }
+
+// ShouldPublishClientLibraries determines if the operator should publish the
+// client libraries for application pods.
+func (cluster *FoundationDBCluster) ShouldPublishClientLibraries() bool {
+	return pointer.BoolDeref(cluster.Spec.ClientLibraries.Enabled, false)
+}
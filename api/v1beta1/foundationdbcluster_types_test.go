@@ -30,6 +30,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
@@ -63,11 +64,13 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 				LogRouters: -1,
 			}))
 			Expect(counts.Map()).To(Equal(map[ProcessClass]int{
-				"logs":        3,
-				"proxies":     3,
-				"resolvers":   1,
-				"remote_logs": -1,
-				"log_routers": -1,
+				"logs":           3,
+				"proxies":        3,
+				"resolvers":      1,
+				"remote_logs":    -1,
+				"log_routers":    -1,
+				"grv_proxies":    0,
+				"commit_proxies": 0,
 			}))
 			Expect(cluster.Spec.DatabaseConfiguration.RoleCounts).To(Equal(RoleCounts{}))
 
@@ -82,11 +85,13 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 				LogRouters: 3,
 			}))
 			Expect(counts.Map()).To(Equal(map[ProcessClass]int{
-				"logs":        3,
-				"proxies":     3,
-				"resolvers":   1,
-				"remote_logs": 3,
-				"log_routers": 3,
+				"logs":           3,
+				"proxies":        3,
+				"resolvers":      1,
+				"remote_logs":    3,
+				"log_routers":    3,
+				"grv_proxies":    0,
+				"commit_proxies": 0,
 			}))
 
 			cluster.Spec.DatabaseConfiguration.RoleCounts = RoleCounts{
@@ -133,6 +138,93 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 		})
 	})
 
+	When("using separated proxies", func() {
+		var cluster *FoundationDBCluster
+
+		BeforeEach(func() {
+			cluster = &FoundationDBCluster{
+				Spec: FoundationDBClusterSpec{
+					Version: Versions.WithSeparatedProxies.String(),
+					DatabaseConfiguration: DatabaseConfiguration{
+						RedundancyMode: RedundancyModeDouble,
+						RoleCounts: RoleCounts{
+							GrvProxies:    2,
+							CommitProxies: 4,
+						},
+					},
+				},
+			}
+		})
+
+		It("should replace the proxy count", func() {
+			Expect(cluster.UsesSeparatedProxies()).To(BeTrue())
+			counts := cluster.GetRoleCountsWithDefaults()
+			Expect(counts.Proxies).To(Equal(0))
+			Expect(counts.GrvProxies).To(Equal(2))
+			Expect(counts.CommitProxies).To(Equal(4))
+		})
+
+		It("should fill in the default for the missing proxy count", func() {
+			cluster.Spec.DatabaseConfiguration.RoleCounts = RoleCounts{GrvProxies: 2}
+			counts := cluster.GetRoleCountsWithDefaults()
+			Expect(counts.GrvProxies).To(Equal(2))
+			Expect(counts.CommitProxies).To(Equal(2))
+		})
+
+		It("should include both proxy counts in the stateless count", func() {
+			counts, err := cluster.GetProcessCountsWithDefaults()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counts.Stateless).To(Equal(12))
+		})
+
+		It("should not count the GRV proxies as stateless with dedicated processes", func() {
+			cluster.Spec.ProcessCounts.GrvProxy = 2
+			counts, err := cluster.GetProcessCountsWithDefaults()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counts.Stateless).To(Equal(10))
+			Expect(counts.GrvProxy).To(Equal(2))
+		})
+
+		It("should only put the separated proxy counts in the configuration string", func() {
+			Expect(cluster.DesiredDatabaseConfiguration().GetConfigurationString()).To(Equal("double ssd-2 usable_regions=1 logs=3 resolvers=1 log_routers=-1 remote_logs=-1 grv_proxies=2 commit_proxies=4 regions=[]"))
+		})
+
+		It("should ignore the single proxy count in the live configuration", func() {
+			liveConfiguration := DatabaseConfiguration{RoleCounts: RoleCounts{Proxies: 6, GrvProxies: 2, CommitProxies: 4}}
+			cluster.ClearUnusedProxyCounts(&liveConfiguration)
+			Expect(liveConfiguration.RoleCounts).To(Equal(RoleCounts{GrvProxies: 2, CommitProxies: 4}))
+		})
+
+		When("the spec does not set the separated proxy counts", func() {
+			BeforeEach(func() {
+				cluster.Spec.DatabaseConfiguration.RoleCounts = RoleCounts{}
+			})
+
+			It("should compare the sum of the live proxy counts to the proxy count", func() {
+				liveConfiguration := cluster.DesiredDatabaseConfiguration()
+				liveConfiguration.Proxies = 0
+				liveConfiguration.GrvProxies = 1
+				liveConfiguration.CommitProxies = 2
+				cluster.ClearUnusedProxyCounts(&liveConfiguration)
+				Expect(liveConfiguration).To(Equal(cluster.DesiredDatabaseConfiguration()))
+			})
+		})
+
+		When("the version does not have separated proxies", func() {
+			BeforeEach(func() {
+				cluster.Spec.Version = Versions.WithoutSeparatedProxies.String()
+			})
+
+			It("should use the proxy count", func() {
+				Expect(cluster.UsesSeparatedProxies()).To(BeFalse())
+				counts := cluster.GetRoleCountsWithDefaults()
+				Expect(counts.Proxies).To(Equal(3))
+				Expect(counts.GrvProxies).To(Equal(0))
+				Expect(counts.CommitProxies).To(Equal(0))
+			})
+		})
+	})
+
 	When("getting the default process counts", func() {
 		It("should return the default process counts", func() {
 			cluster := &FoundationDBCluster{
@@ -461,6 +553,12 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 			Expect(cluster.DesiredFaultTolerance()).To(Equal(1))
 			Expect(cluster.MinimumFaultDomains()).To(Equal(2))
 			Expect(cluster.DesiredCoordinatorCount()).To(Equal(9))
+
+			cluster.Spec.DatabaseConfiguration.UsableRegions = 1
+			cluster.Spec.DatabaseConfiguration.RedundancyMode = RedundancyModeThreeDataHall
+			Expect(cluster.DesiredFaultTolerance()).To(Equal(2))
+			Expect(cluster.MinimumFaultDomains()).To(Equal(3))
+			Expect(cluster.DesiredCoordinatorCount()).To(Equal(9))
 		})
 	})
 
@@ -645,6 +743,125 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 
 			configuration.VersionFlags.LogSpill = 3
 			Expect(configuration.GetConfigurationString()).To(Equal("double ssd usable_regions=1 logs=5 proxies=0 resolvers=0 log_routers=0 remote_logs=0 log_spill:=3 regions=[]"))
+			configuration.VersionFlags.LogSpill = 0
+
+			migrationType := StorageMigrationTypeGradual
+			configuration.PerpetualStorageWiggle = pointer.Int(1)
+			configuration.StorageMigrationType = &migrationType
+			Expect(configuration.GetConfigurationString()).To(Equal("double ssd usable_regions=1 logs=5 proxies=0 resolvers=0 log_routers=0 remote_logs=0 perpetual_storage_wiggle=1 storage_migration_type=gradual regions=[]"))
+		})
+	})
+
+	When("getting the storage wiggle configuration", func() {
+		var cluster *FoundationDBCluster
+
+		BeforeEach(func() {
+			cluster = &FoundationDBCluster{
+				Spec: FoundationDBClusterSpec{
+					Version: Versions.WithPerpetualStorageWiggle.String(),
+					DatabaseConfiguration: DatabaseConfiguration{
+						PerpetualStorageWiggle: pointer.Int(1),
+					},
+				},
+			}
+		})
+
+		It("should enable the wiggle with the gradual storage migration", func() {
+			configuration := cluster.DesiredDatabaseConfiguration()
+			Expect(configuration.PerpetualStorageWiggle).To(Equal(pointer.Int(1)))
+			Expect(configuration.StorageMigrationType).NotTo(BeNil())
+			Expect(*configuration.StorageMigrationType).To(Equal(StorageMigrationTypeGradual))
+			Expect(cluster.UsesDatabaseStorageMigration()).To(BeTrue())
+		})
+
+		It("should keep an explicit storage migration type", func() {
+			migrationType := StorageMigrationTypeDisabled
+			cluster.Spec.DatabaseConfiguration.StorageMigrationType = &migrationType
+			configuration := cluster.DesiredDatabaseConfiguration()
+			Expect(*configuration.StorageMigrationType).To(Equal(StorageMigrationTypeDisabled))
+			Expect(cluster.UsesDatabaseStorageMigration()).To(BeFalse())
+		})
+
+		It("should not set the storage migration type when it is disabled in the automation options", func() {
+			cluster.Spec.AutomationOptions.StorageWiggle.UseForStorageEngineChanges = pointer.Bool(false)
+			Expect(cluster.DesiredDatabaseConfiguration().StorageMigrationType).To(BeNil())
+			Expect(cluster.UsesDatabaseStorageMigration()).To(BeFalse())
+		})
+
+		It("should leave out the wiggle options for versions without the wiggle", func() {
+			cluster.Spec.Version = Versions.WithoutPerpetualStorageWiggle.String()
+			configuration := cluster.DesiredDatabaseConfiguration()
+			Expect(configuration.PerpetualStorageWiggle).To(BeNil())
+			Expect(configuration.StorageMigrationType).To(BeNil())
+		})
+
+		It("should clear the options that are not in the spec from the live configuration", func() {
+			migrationType := StorageMigrationTypeAggressive
+			liveConfiguration := DatabaseConfiguration{
+				PerpetualStorageWiggle: pointer.Int(0),
+				StorageMigrationType:   &migrationType,
+			}
+
+			cluster.Spec.AutomationOptions.StorageWiggle.UseForStorageEngineChanges = pointer.Bool(false)
+			cluster.ClearMissingStorageWiggleOptions(&liveConfiguration)
+			Expect(liveConfiguration.PerpetualStorageWiggle).To(Equal(pointer.Int(0)))
+			Expect(liveConfiguration.StorageMigrationType).To(BeNil())
+		})
+
+		It("should not use the storage migration when the wiggle is disabled", func() {
+			migrationType := StorageMigrationTypeGradual
+			cluster.Spec.DatabaseConfiguration.PerpetualStorageWiggle = pointer.Int(0)
+			cluster.Spec.DatabaseConfiguration.StorageMigrationType = &migrationType
+			Expect(cluster.CanRunStorageWiggle()).To(BeFalse())
+			Expect(cluster.UsesDatabaseStorageMigration()).To(BeFalse())
+		})
+
+		When("pausing the wiggle outside of maintenance windows", func() {
+			var now time.Time
+
+			BeforeEach(func() {
+				now = time.Date(2021, time.June, 1, 12, 0, 0, 0, time.UTC)
+				cluster.Spec.AutomationOptions.StorageWiggle.PauseOutsideMaintenanceWindows = pointer.Bool(true)
+				cluster.Spec.MaintenanceWindows = []MaintenanceWindow{{
+					Schedule: "0 2 * * *",
+					Duration: metav1.Duration{Duration: time.Hour},
+				}}
+			})
+
+			It("should pause the wiggle while the maintenance window is closed", func() {
+				Expect(cluster.IsStorageWigglePaused(now)).To(BeTrue())
+				Expect(cluster.IsStorageWigglePaused(now.Add(-10 * time.Hour))).To(BeFalse())
+			})
+
+			It("should keep the wiggle in the desired configuration", func() {
+				Expect(cluster.DesiredDatabaseConfiguration().PerpetualStorageWiggle).To(Equal(pointer.Int(1)))
+			})
+
+			It("should use the storage migration", func() {
+				Expect(cluster.CanRunStorageWiggle()).To(BeTrue())
+				Expect(cluster.UsesDatabaseStorageMigration()).To(BeTrue())
+			})
+
+			It("should ignore the paused wiggle in the live configuration", func() {
+				liveConfiguration := DatabaseConfiguration{
+					PerpetualStorageWiggle: pointer.Int(0),
+				}
+				cluster.IgnorePausedStorageWiggle(&liveConfiguration)
+				Expect(liveConfiguration.PerpetualStorageWiggle).To(Equal(pointer.Int(1)))
+			})
+
+			It("should not pause the wiggle without maintenance windows", func() {
+				cluster.Spec.MaintenanceWindows = nil
+				Expect(cluster.IsStorageWigglePaused(now)).To(BeFalse())
+			})
+		})
+
+		It("should keep a disabled wiggle in the live configuration", func() {
+			liveConfiguration := DatabaseConfiguration{
+				PerpetualStorageWiggle: pointer.Int(0),
+			}
+			cluster.IgnorePausedStorageWiggle(&liveConfiguration)
+			Expect(liveConfiguration.PerpetualStorageWiggle).To(Equal(pointer.Int(0)))
 		})
 	})
 
@@ -2339,6 +2556,38 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 					input: "127.0.0.A:4500",
 					err:   fmt.Errorf("invalid address: 127.0.0.A:4500"),
 				}),
+			Entry("IPv4 with out of range octet",
+				testCase{
+					input: "127.0.0.256:4500",
+					err:   fmt.Errorf("invalid address: 127.0.0.256:4500"),
+				}),
+			Entry("IPv4 with missing octet",
+				testCase{
+					input: "127.0.1:4500",
+					err:   fmt.Errorf("invalid address: 127.0.1:4500"),
+				}),
+			Entry("DNS name with TLS flag",
+				testCase{
+					input: "sample-cluster-storage-1.sample-cluster.default.svc.cluster.local:4500:tls",
+					expectedAddr: ProcessAddress{
+						StringAddress: "sample-cluster-storage-1.sample-cluster.default.svc.cluster.local",
+						Port:          4500,
+						Flags:         map[string]bool{"tls": true},
+					},
+					expectedStr: "sample-cluster-storage-1.sample-cluster.default.svc.cluster.local:4500:tls",
+					err:         nil,
+				}),
+			Entry("DNS name without TLS flag",
+				testCase{
+					input: "sample-cluster-storage-1.sample-cluster.default.svc.cluster.local:4501",
+					expectedAddr: ProcessAddress{
+						StringAddress: "sample-cluster-storage-1.sample-cluster.default.svc.cluster.local",
+						Port:          4501,
+						Flags:         nil,
+					},
+					expectedStr: "sample-cluster-storage-1.sample-cluster.default.svc.cluster.local:4501",
+					err:         nil,
+				}),
 		)
 	})
 
@@ -2780,6 +3029,17 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 			}
 			Expect(cluster.ShouldUseLocks()).To(BeTrue())
 
+			// A single region with satellites needs an explicit opt-in.
+			cluster.Spec.DatabaseConfiguration.Regions = []Region{
+				{
+					DataCenters: []DataCenter{
+						{ID: "dc1"},
+						{ID: "dc1-sat", Satellite: 1},
+					},
+				},
+			}
+			Expect(cluster.ShouldUseLocks()).To(BeFalse())
+
 			duration := 60
 			cluster.Spec.LockOptions.LockDurationMinutes = &duration
 			Expect(cluster.GetLockDuration()).To(Equal(60 * time.Minute))
@@ -2927,6 +3187,179 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 		)
 	})
 
+	When("adding LogServerPerDisk", func() {
+		It("should only insert duplicates once", func() {
+			status := FoundationDBClusterStatus{}
+			status.AddLogServerPerDisk(1)
+			status.AddLogServerPerDisk(2)
+			status.AddLogServerPerDisk(1)
+			Expect(status.LogServersPerDisk).To(Equal([]int{1, 2}))
+		})
+	})
+
+	When("getting the desired servers per Pod", func() {
+		It("should use the setting for the process class", func() {
+			cluster := &FoundationDBCluster{
+				Spec: FoundationDBClusterSpec{
+					StorageServersPerPod: 2,
+					LogServersPerPod:     3,
+				},
+			}
+
+			Expect(cluster.GetDesiredServersPerPod(ProcessClassStorage)).To(Equal(2))
+			Expect(cluster.GetDesiredServersPerPod(ProcessClassLog)).To(Equal(3))
+			Expect(cluster.GetDesiredServersPerPod(ProcessClassTransaction)).To(Equal(3))
+			Expect(cluster.GetDesiredServersPerPod(ProcessClassStateless)).To(Equal(1))
+		})
+	})
+
+	When("adding a reconciliation history entry", func() {
+		var status FoundationDBClusterStatus
+		var timestamp metav1.Time
+
+		BeforeEach(func() {
+			timestamp = metav1.Unix(1000, 0)
+			status = FoundationDBClusterStatus{}
+		})
+
+		Context("with an empty history", func() {
+			var added bool
+
+			BeforeEach(func() {
+				added = status.AddReconciliationHistoryEntry(ReconciliationHistoryEntry{
+					Timestamp:     timestamp,
+					SubReconciler: "controllers.bounceProcesses",
+					Message:       "Kills are disabled",
+					Generation:    2,
+				}, 3)
+			})
+
+			It("should add the entry with a count of one", func() {
+				Expect(added).To(BeTrue())
+				Expect(status.ReconciliationHistory).To(Equal([]ReconciliationHistoryEntry{
+					{
+						Timestamp:     timestamp,
+						SubReconciler: "controllers.bounceProcesses",
+						Message:       "Kills are disabled",
+						Generation:    2,
+						Count:         1,
+					},
+				}))
+			})
+		})
+
+		Context("with the same outcome as the latest entry", func() {
+			var added bool
+
+			BeforeEach(func() {
+				status.ReconciliationHistory = []ReconciliationHistoryEntry{
+					{
+						Timestamp:     timestamp,
+						SubReconciler: "controllers.bounceProcesses",
+						Message:       "Kills are disabled",
+						Generation:    2,
+						Count:         1,
+					},
+				}
+
+				added = status.AddReconciliationHistoryEntry(ReconciliationHistoryEntry{
+					Timestamp:     metav1.Unix(2000, 0),
+					SubReconciler: "controllers.bounceProcesses",
+					Message:       "Kills are disabled",
+					Generation:    2,
+				}, 3)
+			})
+
+			It("should update the latest entry", func() {
+				Expect(added).To(BeFalse())
+				Expect(len(status.ReconciliationHistory)).To(Equal(1))
+				Expect(status.ReconciliationHistory[0].Count).To(Equal(2))
+				Expect(status.ReconciliationHistory[0].Timestamp).To(Equal(metav1.Unix(2000, 0)))
+			})
+		})
+
+		Context("with a full history", func() {
+			BeforeEach(func() {
+				for i := 1; i <= 4; i++ {
+					status.AddReconciliationHistoryEntry(ReconciliationHistoryEntry{
+						Timestamp:     timestamp,
+						SubReconciler: "controllers.bounceProcesses",
+						Message:       "Kills are disabled",
+						Generation:    int64(i),
+					}, 3)
+				}
+			})
+
+			It("should drop the oldest entries", func() {
+				Expect(len(status.ReconciliationHistory)).To(Equal(3))
+				Expect(status.ReconciliationHistory[0].Generation).To(Equal(int64(2)))
+				Expect(status.ReconciliationHistory[2].Generation).To(Equal(int64(4)))
+			})
+		})
+	})
+
+	When("getting the active freeze for an operation", func() {
+		now := time.Now()
+		earlier := metav1.NewTime(now.Add(-1 * time.Hour))
+		later := metav1.NewTime(now.Add(1 * time.Hour))
+		latest := metav1.NewTime(now.Add(2 * time.Hour))
+
+		type testCase struct {
+			freezes  []AutomationFreeze
+			expected *AutomationFreeze
+		}
+
+		DescribeTable("should return the active freeze",
+			func(tc testCase) {
+				cluster := &FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						AutomationOptions: FoundationDBClusterAutomationOptions{
+							Freezes: tc.freezes,
+						},
+					},
+				}
+
+				Expect(cluster.GetActiveFreeze(AutomationOperationExclusions, now)).To(Equal(tc.expected))
+			},
+			Entry("without freezes",
+				testCase{
+					freezes:  nil,
+					expected: nil,
+				}),
+			Entry("with a freeze for a different operation",
+				testCase{
+					freezes: []AutomationFreeze{
+						{Operation: AutomationOperationCoordinatorChanges},
+					},
+					expected: nil,
+				}),
+			Entry("with an expired freeze",
+				testCase{
+					freezes: []AutomationFreeze{
+						{Operation: AutomationOperationExclusions, Until: &earlier},
+					},
+					expected: nil,
+				}),
+			Entry("with a freeze without expiration",
+				testCase{
+					freezes: []AutomationFreeze{
+						{Operation: AutomationOperationExclusions, Until: &later},
+						{Operation: AutomationOperationExclusions},
+					},
+					expected: &AutomationFreeze{Operation: AutomationOperationExclusions},
+				}),
+			Entry("with multiple active freezes",
+				testCase{
+					freezes: []AutomationFreeze{
+						{Operation: AutomationOperationExclusions, Until: &later},
+						{Operation: AutomationOperationExclusions, Until: &latest},
+						{Operation: AutomationOperationExclusions, Until: &earlier},
+					},
+					expected: &AutomationFreeze{Operation: AutomationOperationExclusions, Until: &latest},
+				}),
+		)
+	})
+
 	When("adding addresses to a process group", func() {
 		type testCase struct {
 			initialProcessGroup  ProcessGroupStatus
@@ -3121,9 +3554,70 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 					pClass:   ProcessClassLog,
 					expected: false,
 				}),
+			Entry("coordinator class with dedicated coordinators is eligible",
+				testCase{
+					cluster: &FoundationDBCluster{
+						Spec: FoundationDBClusterSpec{
+							UseDedicatedCoordinators: pointer.Bool(true),
+						},
+					},
+					pClass:   ProcessClassCoordinator,
+					expected: true,
+				}),
+			Entry("coordinator class without dedicated coordinators is not eligible",
+				testCase{
+					cluster:  &FoundationDBCluster{},
+					pClass:   ProcessClassCoordinator,
+					expected: false,
+				}),
+			Entry("storage class with dedicated coordinators is not eligible",
+				testCase{
+					cluster: &FoundationDBCluster{
+						Spec: FoundationDBClusterSpec{
+							UseDedicatedCoordinators: pointer.Bool(true),
+							CoordinatorSelection: []CoordinatorSelectionSetting{
+								{
+									ProcessClass: ProcessClassStorage,
+									Priority:     1,
+								},
+							},
+						},
+					},
+					pClass:   ProcessClassStorage,
+					expected: false,
+				}),
 		)
 	})
 
+	When("using dedicated coordinators", func() {
+		It("should provision coordinator processes", func() {
+			cluster := &FoundationDBCluster{
+				Spec: FoundationDBClusterSpec{
+					Version:                  Versions.Default.String(),
+					UseDedicatedCoordinators: pointer.Bool(true),
+					DatabaseConfiguration: DatabaseConfiguration{
+						RedundancyMode: RedundancyModeDouble,
+					},
+				},
+			}
+
+			counts, err := cluster.GetProcessCountsWithDefaults()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counts.Coordinator).To(Equal(4))
+
+			cluster.Spec.ProcessCounts.Coordinator = 5
+			counts, err = cluster.GetProcessCountsWithDefaults()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counts.Coordinator).To(Equal(5))
+
+			cluster.Spec.UseDedicatedCoordinators = nil
+			cluster.Spec.ProcessCounts.Coordinator = 0
+			counts, err = cluster.GetProcessCountsWithDefaults()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counts.Coordinator).To(Equal(0))
+		})
+	})
+
 	When("getting the priority of a process class", func() {
 		type testCase struct {
 			cluster  *FoundationDBCluster
@@ -3234,6 +3728,42 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 		})
 	})
 
+	Describe("checking for DNS names in the cluster file", func() {
+		var cluster *FoundationDBCluster
+
+		BeforeEach(func() {
+			cluster = &FoundationDBCluster{
+				Spec: FoundationDBClusterSpec{
+					Version: "7.0.0",
+				},
+			}
+		})
+
+		It("is not used for a default cluster", func() {
+			Expect(cluster.UseDNSInClusterFile()).To(BeFalse())
+			Expect(cluster.NeedsHeadlessService()).To(BeFalse())
+		})
+
+		It("is used with the flag set to true", func() {
+			cluster.Spec.Routing.UseDNSInClusterFile = pointer.Bool(true)
+			Expect(cluster.UseDNSInClusterFile()).To(BeTrue())
+			Expect(cluster.NeedsHeadlessService()).To(BeTrue())
+		})
+
+		It("is not used with a version that doesn't support it", func() {
+			cluster.Spec.Version = "6.3.13"
+			cluster.Spec.Routing.UseDNSInClusterFile = pointer.Bool(true)
+			Expect(cluster.UseDNSInClusterFile()).To(BeFalse())
+			Expect(cluster.NeedsHeadlessService()).To(BeFalse())
+		})
+
+		It("uses the default DNS domain", func() {
+			Expect(cluster.GetDNSDomain()).To(Equal("cluster.local"))
+			cluster.Spec.Routing.DNSDomain = pointer.String("example.com")
+			Expect(cluster.GetDNSDomain()).To(Equal("example.com"))
+		})
+	})
+
 	When("checking whether the process group should be skipped or not", func() {
 		type testCase struct {
 			cluster  *FoundationDBCluster
@@ -3584,4 +4114,190 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 			})
 		})
 	})
+
+	When("choosing the primary data center", func() {
+		var configuration DatabaseConfiguration
+
+		BeforeEach(func() {
+			configuration = DatabaseConfiguration{
+				Regions: []Region{
+					{
+						DataCenters: []DataCenter{
+							{ID: "primary", Priority: 1},
+							{ID: "primary-sat", Priority: 1, Satellite: 1},
+						},
+					},
+					{
+						DataCenters: []DataCenter{
+							{ID: "remote", Priority: 0},
+							{ID: "remote-sat", Priority: 1, Satellite: 1},
+						},
+					},
+				},
+			}
+		})
+
+		It("should return the data center with the highest priority", func() {
+			Expect(configuration.GetPrimaryDataCenter()).To(Equal("primary"))
+			Expect(DatabaseConfiguration{}.GetPrimaryDataCenter()).To(Equal(""))
+		})
+
+		It("should only accept main data centers", func() {
+			Expect(configuration.HasMainDataCenter("remote")).To(BeTrue())
+			Expect(configuration.HasMainDataCenter("remote-sat")).To(BeFalse())
+		})
+
+		It("should swap the priorities to fail over", func() {
+			config := configuration.WithPrimaryDataCenter("remote")
+			Expect(config.GetPrimaryDataCenter()).To(Equal("remote"))
+			Expect(config.Regions[0].DataCenters).To(Equal([]DataCenter{
+				{ID: "primary", Priority: 0},
+				{ID: "primary-sat", Priority: 1, Satellite: 1},
+			}))
+			Expect(config.Regions[1].DataCenters).To(Equal([]DataCenter{
+				{ID: "remote", Priority: 1},
+				{ID: "remote-sat", Priority: 1, Satellite: 1},
+			}))
+			// The original configuration should not be changed
+			Expect(configuration.GetPrimaryDataCenter()).To(Equal("primary"))
+		})
+
+		It("should raise the priority if both data centers have the same priority", func() {
+			configuration.Regions[1].DataCenters[0].Priority = 1
+			config := configuration.WithPrimaryDataCenter("remote")
+			Expect(config.GetPrimaryDataCenter()).To(Equal("remote"))
+			Expect(config.Regions[1].DataCenters[0].Priority).To(Equal(2))
+		})
+
+		It("should not change the configuration for the current primary or unknown data centers", func() {
+			Expect(configuration.WithPrimaryDataCenter("primary")).To(Equal(configuration))
+			Expect(configuration.WithPrimaryDataCenter("remote-sat")).To(Equal(configuration))
+			Expect(configuration.WithPrimaryDataCenter("")).To(Equal(configuration))
+		})
+	})
+
+	When("applying parameter overrides for process groups", func() {
+		var cluster *FoundationDBCluster
+
+		BeforeEach(func() {
+			cluster = &FoundationDBCluster{
+				Spec: FoundationDBClusterSpec{
+					Processes: map[ProcessClass]ProcessSettings{
+						ProcessClassGeneral: {CustomParameters: FoundationDBCustomParameters{"knob_disable_posix_kernel_aio=1", "knob_test = 1"}},
+					},
+					ProcessGroupParameterOverrides: map[string]ProcessGroupParameterOverride{
+						"storage-1": {CustomParameters: FoundationDBCustomParameters{"knob_test=2", "trace_format=json"}},
+						"storage-2": {
+							CustomParameters: FoundationDBCustomParameters{"knob_test=3"},
+							ExpirationTime:   &metav1.Time{Time: time.Now().Add(-1 * time.Minute)},
+						},
+						"storage-3": {
+							CustomParameters: FoundationDBCustomParameters{"knob_test=4"},
+							ExpirationTime:   &metav1.Time{Time: time.Now().Add(1 * time.Hour)},
+						},
+					},
+				},
+			}
+		})
+
+		It("should replace the parameters with the same name", func() {
+			Expect(cluster.GetProcessGroupCustomParameters(ProcessClassStorage, "storage-1")).To(Equal(FoundationDBCustomParameters{
+				"knob_disable_posix_kernel_aio=1",
+				"knob_test=2",
+				"trace_format=json",
+			}))
+		})
+
+		It("should ignore expired overrides", func() {
+			Expect(cluster.GetActiveParameterOverride("storage-2")).To(BeNil())
+			Expect(cluster.HasParameterOverride("storage-2")).To(BeTrue())
+			Expect(cluster.HasParameterOverride("storage-4")).To(BeFalse())
+			Expect(cluster.GetProcessGroupCustomParameters(ProcessClassStorage, "storage-2")).To(Equal(FoundationDBCustomParameters{
+				"knob_disable_posix_kernel_aio=1",
+				"knob_test = 1",
+			}))
+		})
+
+		It("should use the process settings without a process group", func() {
+			Expect(cluster.GetProcessGroupCustomParameters(ProcessClassStorage, "")).To(Equal(cluster.Spec.Processes[ProcessClassGeneral].CustomParameters))
+		})
+
+		It("should return the active overrides", func() {
+			Expect(cluster.GetActiveParameterOverrides(time.Now())).To(HaveLen(2))
+			Expect(cluster.GetActiveParameterOverrides(time.Now())).To(HaveKey("storage-1"))
+			Expect(cluster.GetActiveParameterOverrides(time.Now())).To(HaveKey("storage-3"))
+			Expect(cluster.GetNextParameterOverrideExpiration(time.Now())).To(Equal(&cluster.Spec.ProcessGroupParameterOverrides["storage-3"].ExpirationTime.Time))
+		})
+	})
+
+	When("getting the runtime knobs", func() {
+		var cluster *FoundationDBCluster
+
+		BeforeEach(func() {
+			cluster = &FoundationDBCluster{
+				Spec: FoundationDBClusterSpec{
+					Version: Versions.WithRuntimeKnobs.String(),
+					AutomationOptions: FoundationDBClusterAutomationOptions{
+						RuntimeKnobs: []string{"max_trace_lines", "min_trace_severity", "test"},
+					},
+					Processes: map[ProcessClass]ProcessSettings{
+						ProcessClassGeneral: {CustomParameters: FoundationDBCustomParameters{"knob_max_trace_lines=1000", "knob_min_trace_severity=10", "knob_disable_posix_kernel_aio=1"}},
+					},
+				},
+			}
+		})
+
+		It("should return the eligible knobs", func() {
+			Expect(cluster.GetRuntimeKnobs()).To(Equal(map[string]string{"max_trace_lines": "1000", "min_trace_severity": "10"}))
+		})
+
+		When("the version does not support runtime knobs", func() {
+			BeforeEach(func() {
+				cluster.Spec.Version = Versions.WithoutRuntimeKnobs.String()
+			})
+
+			It("should return no knobs", func() {
+				Expect(cluster.GetRuntimeKnobs()).To(BeNil())
+			})
+		})
+
+		When("a process class uses a different value", func() {
+			BeforeEach(func() {
+				cluster.Spec.Processes[ProcessClassStorage] = ProcessSettings{
+					CustomParameters: FoundationDBCustomParameters{"knob_max_trace_lines=2000", "knob_min_trace_severity=10"},
+				}
+			})
+
+			It("should only return the consistent knobs", func() {
+				Expect(cluster.GetRuntimeKnobs()).To(Equal(map[string]string{"min_trace_severity": "10"}))
+			})
+		})
+
+		When("a process group overrides a knob", func() {
+			BeforeEach(func() {
+				cluster.Spec.ProcessGroupParameterOverrides = map[string]ProcessGroupParameterOverride{
+					"storage-1": {CustomParameters: FoundationDBCustomParameters{"knob_min_trace_severity=20"}},
+				}
+			})
+
+			It("should not return the overridden knob", func() {
+				Expect(cluster.GetRuntimeKnobs()).To(Equal(map[string]string{"max_trace_lines": "1000"}))
+			})
+		})
+
+		When("an expired override is still in the spec", func() {
+			BeforeEach(func() {
+				cluster.Spec.ProcessGroupParameterOverrides = map[string]ProcessGroupParameterOverride{
+					"storage-1": {
+						CustomParameters: FoundationDBCustomParameters{"knob_min_trace_severity=20"},
+						ExpirationTime:   &metav1.Time{Time: time.Now().Add(-1 * time.Hour)},
+					},
+				}
+			})
+
+			It("should not return the overridden knob", func() {
+				Expect(cluster.GetRuntimeKnobs()).To(Equal(map[string]string{"max_trace_lines": "1000"}))
+			})
+		})
+	})
 })
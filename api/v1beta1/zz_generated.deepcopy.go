@@ -58,6 +58,25 @@ func (in *AutomaticReplacementOptions) DeepCopy() *AutomaticReplacementOptions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutomationFreeze) DeepCopyInto(out *AutomationFreeze) {
+	*out = *in
+	if in.Until != nil {
+		in, out := &in.Until, &out.Until
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutomationFreeze.
+func (in *AutomationFreeze) DeepCopy() *AutomationFreeze {
+	if in == nil {
+		return nil
+	}
+	out := new(AutomationFreeze)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackupGenerationStatus) DeepCopyInto(out *BackupGenerationStatus) {
 	*out = *in
@@ -118,6 +137,46 @@ func (in *BuggifyConfig) DeepCopy() *BuggifyConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientConfigTarget) DeepCopyInto(out *ClientConfigTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientConfigTarget.
+func (in *ClientConfigTarget) DeepCopy() *ClientConfigTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientConfigTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientLibraryConfig) DeepCopyInto(out *ClientLibraryConfig) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AdditionalVersions != nil {
+		in, out := &in.AdditionalVersions, &out.AdditionalVersions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientLibraryConfig.
+func (in *ClientLibraryConfig) DeepCopy() *ClientLibraryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientLibraryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterGenerationStatus) DeepCopyInto(out *ClusterGenerationStatus) {
 	*out = *in
@@ -247,9 +306,41 @@ func (in *DataCenter) DeepCopy() *DataCenter {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataCenterStatus) DeepCopyInto(out *DataCenterStatus) {
+	*out = *in
+	out.ProcessCounts = in.ProcessCounts
+	out.RoleCounts = in.RoleCounts
+	if in.OperatorInstances != nil {
+		in, out := &in.OperatorInstances, &out.OperatorInstances
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataCenterStatus.
+func (in *DataCenterStatus) DeepCopy() *DataCenterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DataCenterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatabaseConfiguration) DeepCopyInto(out *DatabaseConfiguration) {
 	*out = *in
+	if in.PerpetualStorageWiggle != nil {
+		in, out := &in.PerpetualStorageWiggle, &out.PerpetualStorageWiggle
+		*out = new(int)
+		**out = **in
+	}
+	if in.StorageMigrationType != nil {
+		in, out := &in.StorageMigrationType, &out.StorageMigrationType
+		*out = new(StorageMigrationType)
+		**out = **in
+	}
 	if in.Regions != nil {
 		in, out := &in.Regions, &out.Regions
 		*out = make([]Region, len(*in))
@@ -271,6 +362,40 @@ func (in *DatabaseConfiguration) DeepCopy() *DatabaseConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatacenterLag) DeepCopyInto(out *DatacenterLag) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatacenterLag.
+func (in *DatacenterLag) DeepCopy() *DatacenterLag {
+	if in == nil {
+		return nil
+	}
+	out := new(DatacenterLag)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailOverStatus) DeepCopyInto(out *FailOverStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailOverStatus.
+func (in *FailOverStatus) DeepCopy() *FailOverStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FailOverStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FaultTolerance) DeepCopyInto(out *FaultTolerance) {
 	*out = *in
@@ -507,6 +632,29 @@ func (in *FoundationDBClusterAutomationOptions) DeepCopyInto(out *FoundationDBCl
 		*out = new(int)
 		**out = **in
 	}
+	if in.ManagePodDisruptionBudgets != nil {
+		in, out := &in.ManagePodDisruptionBudgets, &out.ManagePodDisruptionBudgets
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Freezes != nil {
+		in, out := &in.Freezes, &out.Freezes
+		*out = make([]AutomationFreeze, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RuntimeKnobs != nil {
+		in, out := &in.RuntimeKnobs, &out.RuntimeKnobs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.StorageWiggle.DeepCopyInto(&out.StorageWiggle)
+	if in.ReplaceOnStorageEngineChange != nil {
+		in, out := &in.ReplaceOnStorageEngineChange, &out.ReplaceOnStorageEngineChange
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBClusterAutomationOptions.
@@ -522,6 +670,11 @@ func (in *FoundationDBClusterAutomationOptions) DeepCopy() *FoundationDBClusterA
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FoundationDBClusterFaultDomain) DeepCopyInto(out *FoundationDBClusterFaultDomain) {
 	*out = *in
+	if in.UseTopologySpreadConstraints != nil {
+		in, out := &in.UseTopologySpreadConstraints, &out.UseTopologySpreadConstraints
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBClusterFaultDomain.
@@ -584,9 +737,16 @@ func (in *FoundationDBClusterSpec) DeepCopyInto(out *FoundationDBClusterSpec) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.ProcessGroupParameterOverrides != nil {
+		in, out := &in.ProcessGroupParameterOverrides, &out.ProcessGroupParameterOverrides
+		*out = make(map[string]ProcessGroupParameterOverride, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 	out.ProcessCounts = in.ProcessCounts
 	in.PartialConnectionString.DeepCopyInto(&out.PartialConnectionString)
-	out.FaultDomain = in.FaultDomain
+	in.FaultDomain.DeepCopyInto(&out.FaultDomain)
 	if in.InstancesToRemove != nil {
 		in, out := &in.InstancesToRemove, &out.InstancesToRemove
 		*out = make([]string, len(*in))
@@ -619,15 +779,41 @@ func (in *FoundationDBClusterSpec) DeepCopyInto(out *FoundationDBClusterSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.TLSCertificates != nil {
+		in, out := &in.TLSCertificates, &out.TLSCertificates
+		*out = new(TLSCertificateConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SidecarAuthentication != nil {
+		in, out := &in.SidecarAuthentication, &out.SidecarAuthentication
+		*out = new(SidecarAuthenticationConfig)
+		**out = **in
+	}
 	if in.SidecarVariables != nil {
 		in, out := &in.SidecarVariables, &out.SidecarVariables
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.SubstitutionVariables != nil {
+		in, out := &in.SubstitutionVariables, &out.SubstitutionVariables
+		*out = make([]SubstitutionVariable, len(*in))
+		copy(*out, *in)
+	}
 	in.AutomationOptions.DeepCopyInto(&out.AutomationOptions)
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 	in.LockOptions.DeepCopyInto(&out.LockOptions)
 	in.Services.DeepCopyInto(&out.Services)
 	in.Routing.DeepCopyInto(&out.Routing)
+	in.ClientLibraries.DeepCopyInto(&out.ClientLibraries)
+	if in.ClientConfigTargets != nil {
+		in, out := &in.ClientConfigTargets, &out.ClientConfigTargets
+		*out = make([]ClientConfigTarget, len(*in))
+		copy(*out, *in)
+	}
 	in.Buggify.DeepCopyInto(&out.Buggify)
 	if in.PodLabels != nil {
 		in, out := &in.PodLabels, &out.PodLabels
@@ -709,6 +895,11 @@ func (in *FoundationDBClusterSpec) DeepCopyInto(out *FoundationDBClusterSpec) {
 		*out = make([]CoordinatorSelectionSetting, len(*in))
 		copy(*out, *in)
 	}
+	if in.UseDedicatedCoordinators != nil {
+		in, out := &in.UseDedicatedCoordinators, &out.UseDedicatedCoordinators
+		*out = new(bool)
+		**out = **in
+	}
 	in.LabelConfig.DeepCopyInto(&out.LabelConfig)
 	if in.UseExplicitListenAddress != nil {
 		in, out := &in.UseExplicitListenAddress, &out.UseExplicitListenAddress
@@ -776,6 +967,11 @@ func (in *FoundationDBClusterStatus) DeepCopyInto(out *FoundationDBClusterStatus
 		*out = make([]int, len(*in))
 		copy(*out, *in)
 	}
+	if in.LogServersPerDisk != nil {
+		in, out := &in.LogServersPerDisk, &out.LogServersPerDisk
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
 	if in.ImageTypes != nil {
 		in, out := &in.ImageTypes, &out.ImageTypes
 		*out = make([]ImageType, len(*in))
@@ -793,6 +989,71 @@ func (in *FoundationDBClusterStatus) DeepCopyInto(out *FoundationDBClusterStatus
 		}
 	}
 	in.Locks.DeepCopyInto(&out.Locks)
+	if in.ReconciliationHistory != nil {
+		in, out := &in.ReconciliationHistory, &out.ReconciliationHistory
+		*out = make([]ReconciliationHistoryEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ActiveFreezes != nil {
+		in, out := &in.ActiveFreezes, &out.ActiveFreezes
+		*out = make([]AutomationFreeze, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DataCenters != nil {
+		in, out := &in.DataCenters, &out.DataCenters
+		*out = make([]DataCenterStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.FailOver != nil {
+		in, out := &in.FailOver, &out.FailOver
+		*out = new(FailOverStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpgradeCheck != nil {
+		in, out := &in.UpgradeCheck, &out.UpgradeCheck
+		*out = new(UpgradeCheckStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpgradeProgress != nil {
+		in, out := &in.UpgradeProgress, &out.UpgradeProgress
+		*out = new(UpgradeProgress)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClientConfigTargets != nil {
+		in, out := &in.ClientConfigTargets, &out.ClientConfigTargets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TLSCertificate != nil {
+		in, out := &in.TLSCertificate, &out.TLSCertificate
+		*out = new(TLSCertificateStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLSMigration != nil {
+		in, out := &in.TLSMigration, &out.TLSMigration
+		*out = new(TLSMigrationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProcessGroupParameterOverrides != nil {
+		in, out := &in.ProcessGroupParameterOverrides, &out.ProcessGroupParameterOverrides
+		*out = make(map[string]ProcessGroupParameterOverride, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.RuntimeKnobs != nil {
+		in, out := &in.RuntimeKnobs, &out.RuntimeKnobs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBClusterStatus.
@@ -1080,6 +1341,13 @@ func (in *FoundationDBStatusClusterInfo) DeepCopyInto(out *FoundationDBStatusClu
 	in.Clients.DeepCopyInto(&out.Clients)
 	in.Layers.DeepCopyInto(&out.Layers)
 	out.FaultTolerance = in.FaultTolerance
+	out.RecoveryState = in.RecoveryState
+	if in.DatacenterLag != nil {
+		in, out := &in.DatacenterLag, &out.DatacenterLag
+		*out = new(DatacenterLag)
+		**out = **in
+	}
+	in.StorageWiggler.DeepCopyInto(&out.StorageWiggler)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatusClusterInfo.
@@ -1239,7 +1507,9 @@ func (in *FoundationDBStatusProcessInfo) DeepCopyInto(out *FoundationDBStatusPro
 	if in.Roles != nil {
 		in, out := &in.Roles, &out.Roles
 		*out = make([]FoundationDBStatusProcessRoleInfo, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -1256,6 +1526,11 @@ func (in *FoundationDBStatusProcessInfo) DeepCopy() *FoundationDBStatusProcessIn
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FoundationDBStatusProcessRoleInfo) DeepCopyInto(out *FoundationDBStatusProcessRoleInfo) {
 	*out = *in
+	if in.StorageMetadata != nil {
+		in, out := &in.StorageMetadata, &out.StorageMetadata
+		*out = new(FoundationDBStatusStorageMetadata)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatusProcessRoleInfo.
@@ -1268,6 +1543,43 @@ func (in *FoundationDBStatusProcessRoleInfo) DeepCopy() *FoundationDBStatusProce
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBStatusStorageMetadata) DeepCopyInto(out *FoundationDBStatusStorageMetadata) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatusStorageMetadata.
+func (in *FoundationDBStatusStorageMetadata) DeepCopy() *FoundationDBStatusStorageMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBStatusStorageMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBStatusStorageWiggler) DeepCopyInto(out *FoundationDBStatusStorageWiggler) {
+	*out = *in
+	if in.WiggleServerAddresses != nil {
+		in, out := &in.WiggleServerAddresses, &out.WiggleServerAddresses
+		*out = make([]ProcessAddress, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatusStorageWiggler.
+func (in *FoundationDBStatusStorageWiggler) DeepCopy() *FoundationDBStatusStorageWiggler {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBStatusStorageWiggler)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FoundationDBStatusSupportedVersion) DeepCopyInto(out *FoundationDBStatusSupportedVersion) {
 	*out = *in
@@ -1417,6 +1729,22 @@ func (in *LockSystemStatus) DeepCopy() *LockSystemStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *None) DeepCopyInto(out *None) {
 	*out = *in
@@ -1432,6 +1760,21 @@ func (in *None) DeepCopy() *None {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorInstance) DeepCopyInto(out *OperatorInstance) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorInstance.
+func (in *OperatorInstance) DeepCopy() *OperatorInstance {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorInstance)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PendingRemovalState) DeepCopyInto(out *PendingRemovalState) {
 	*out = *in
@@ -1504,6 +1847,30 @@ func (in *ProcessGroupCondition) DeepCopy() *ProcessGroupCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProcessGroupParameterOverride) DeepCopyInto(out *ProcessGroupParameterOverride) {
+	*out = *in
+	if in.CustomParameters != nil {
+		in, out := &in.CustomParameters, &out.CustomParameters
+		*out = make(FoundationDBCustomParameters, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExpirationTime != nil {
+		in, out := &in.ExpirationTime, &out.ExpirationTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessGroupParameterOverride.
+func (in *ProcessGroupParameterOverride) DeepCopy() *ProcessGroupParameterOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ProcessGroupParameterOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProcessGroupStatus) DeepCopyInto(out *ProcessGroupStatus) {
 	*out = *in
@@ -1531,6 +1898,23 @@ func (in *ProcessGroupStatus) DeepCopyInto(out *ProcessGroupStatus) {
 			}
 		}
 	}
+	if in.TLSCertificate != nil {
+		in, out := &in.TLSCertificate, &out.TLSCertificate
+		*out = new(TLSCertificateStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ActiveKnobs != nil {
+		in, out := &in.ActiveKnobs, &out.ActiveKnobs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.StorageWiggle != nil {
+		in, out := &in.StorageWiggle, &out.StorageWiggle
+		*out = new(StorageWiggleStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessGroupStatus.
@@ -1583,6 +1967,37 @@ func (in *ProcessSettings) DeepCopy() *ProcessSettings {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReconciliationHistoryEntry) DeepCopyInto(out *ReconciliationHistoryEntry) {
+	*out = *in
+	in.Timestamp.DeepCopyInto(&out.Timestamp)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconciliationHistoryEntry.
+func (in *ReconciliationHistoryEntry) DeepCopy() *ReconciliationHistoryEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(ReconciliationHistoryEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecoveryState) DeepCopyInto(out *RecoveryState) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecoveryState.
+func (in *RecoveryState) DeepCopy() *RecoveryState {
+	if in == nil {
+		return nil
+	}
+	out := new(RecoveryState)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Region) DeepCopyInto(out *Region) {
 	*out = *in
@@ -1651,6 +2066,16 @@ func (in *RoutingConfig) DeepCopyInto(out *RoutingConfig) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.UseDNSInClusterFile != nil {
+		in, out := &in.UseDNSInClusterFile, &out.UseDNSInClusterFile
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DNSDomain != nil {
+		in, out := &in.DNSDomain, &out.DNSDomain
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoutingConfig.
@@ -1688,6 +2113,215 @@ func (in *ServiceConfig) DeepCopy() *ServiceConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarAuthenticationConfig) DeepCopyInto(out *SidecarAuthenticationConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarAuthenticationConfig.
+func (in *SidecarAuthenticationConfig) DeepCopy() *SidecarAuthenticationConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarAuthenticationConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageWiggleOptions) DeepCopyInto(out *StorageWiggleOptions) {
+	*out = *in
+	if in.PauseOutsideMaintenanceWindows != nil {
+		in, out := &in.PauseOutsideMaintenanceWindows, &out.PauseOutsideMaintenanceWindows
+		*out = new(bool)
+		**out = **in
+	}
+	if in.UseForStorageEngineChanges != nil {
+		in, out := &in.UseForStorageEngineChanges, &out.UseForStorageEngineChanges
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageWiggleOptions.
+func (in *StorageWiggleOptions) DeepCopy() *StorageWiggleOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageWiggleOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageWiggleStatus) DeepCopyInto(out *StorageWiggleStatus) {
+	*out = *in
+	if in.LastRefreshed != nil {
+		in, out := &in.LastRefreshed, &out.LastRefreshed
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageWiggleStatus.
+func (in *StorageWiggleStatus) DeepCopy() *StorageWiggleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageWiggleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubstitutionVariable) DeepCopyInto(out *SubstitutionVariable) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubstitutionVariable.
+func (in *SubstitutionVariable) DeepCopy() *SubstitutionVariable {
+	if in == nil {
+		return nil
+	}
+	out := new(SubstitutionVariable)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSCertificateConfig) DeepCopyInto(out *TLSCertificateConfig) {
+	*out = *in
+	if in.RenewBeforeSeconds != nil {
+		in, out := &in.RenewBeforeSeconds, &out.RenewBeforeSeconds
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSCertificateConfig.
+func (in *TLSCertificateConfig) DeepCopy() *TLSCertificateConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSCertificateConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSCertificateStatus) DeepCopyInto(out *TLSCertificateStatus) {
+	*out = *in
+	if in.NotAfter != nil {
+		in, out := &in.NotAfter, &out.NotAfter
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSCertificateStatus.
+func (in *TLSCertificateStatus) DeepCopy() *TLSCertificateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSCertificateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSMigrationStatus) DeepCopyInto(out *TLSMigrationStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSMigrationStatus.
+func (in *TLSMigrationStatus) DeepCopy() *TLSMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeCheckClient) DeepCopyInto(out *UpgradeCheckClient) {
+	*out = *in
+	out.FoundationDBStatusConnectedClient = in.FoundationDBStatusConnectedClient
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeCheckClient.
+func (in *UpgradeCheckClient) DeepCopy() *UpgradeCheckClient {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeCheckClient)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeCheckStatus) DeepCopyInto(out *UpgradeCheckStatus) {
+	*out = *in
+	if in.Timestamp != nil {
+		in, out := &in.Timestamp, &out.Timestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.UnsupportedClients != nil {
+		in, out := &in.UnsupportedClients, &out.UnsupportedClients
+		*out = make([]UpgradeCheckClient, len(*in))
+		copy(*out, *in)
+	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeCheckStatus.
+func (in *UpgradeCheckStatus) DeepCopy() *UpgradeCheckStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeCheckStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeProgress) DeepCopyInto(out *UpgradeProgress) {
+	*out = *in
+	if in.ProcessVersions != nil {
+		in, out := &in.ProcessVersions, &out.ProcessVersions
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PendingUpgrades != nil {
+		in, out := &in.PendingUpgrades, &out.PendingUpgrades
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeProgress.
+func (in *UpgradeProgress) DeepCopy() *UpgradeProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VersionFlags) DeepCopyInto(out *VersionFlags) {
 	*out = *in
@@ -77,14 +77,14 @@ func (a addPods) reconcile(ctx context.Context, r *FoundationDBClusterReconciler
 				return &requeue{curError: err}
 			}
 
-			serverPerPod, err := internal.GetStorageServersPerPodForPod(pod)
+			serverPerPod, err := internal.GetServersPerPodForPod(pod, processGroup.ProcessClass)
 			if err != nil {
 				return &requeue{curError: err}
 			}
 
 			imageType := internal.GetImageType(pod)
 
-			configMapHash, err := internal.GetDynamicConfHash(configMap, processGroup.ProcessClass, imageType, serverPerPod)
+			configMapHash, err := internal.GetDynamicConfHash(configMap, processGroup.ProcessClass, processGroup.ProcessGroupID, imageType, serverPerPod)
 			if err != nil {
 				return &requeue{curError: err}
 			}
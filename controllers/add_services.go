@@ -111,6 +111,7 @@ func updateService(ctx context.Context, r *FoundationDBClusterReconciler, cluste
 	originalSpec := currentService.Spec.DeepCopy()
 
 	currentService.Spec.Selector = newService.Spec.Selector
+	currentService.Spec.PublishNotReadyAddresses = newService.Spec.PublishNotReadyAddresses
 
 	needsUpdate := !equality.Semantic.DeepEqual(currentService.Spec, *originalSpec)
 	metadata := currentService.ObjectMeta
@@ -167,6 +167,28 @@ var _ = Describe("add_services", func() {
 		})
 	})
 
+	Context("with a headless service that does not publish not ready addresses", func() {
+		BeforeEach(func() {
+			service := &corev1.Service{}
+			err = k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}, service)
+			Expect(err).NotTo(HaveOccurred())
+			service.Spec.PublishNotReadyAddresses = false
+			err = k8sClient.Update(context.TODO(), service)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should not requeue", func() {
+			Expect(requeue).To(BeNil())
+		})
+
+		It("should update the headless service", func() {
+			service := &corev1.Service{}
+			err = k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}, service)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(service.Spec.PublishNotReadyAddresses).To(BeTrue())
+		})
+	})
+
 	Context("with no headless service", func() {
 		BeforeEach(func() {
 			service := &corev1.Service{}
@@ -188,6 +210,7 @@ var _ = Describe("add_services", func() {
 			Expect(firstService.Name).To(Equal("operator-test-1"))
 			Expect(firstService.Labels[fdbtypes.FDBProcessGroupIDLabel]).To(Equal(""))
 			Expect(firstService.Spec.ClusterIP).To(Equal("None"))
+			Expect(firstService.Spec.PublishNotReadyAddresses).To(BeTrue())
 		})
 
 		Context("with the headless service disabled", func() {
@@ -54,6 +54,12 @@ type mockAdminClient struct {
 	maxZoneFailuresWithoutLosingData         *int
 	maxZoneFailuresWithoutLosingAvailability *int
 	knobs                                    []string
+	RuntimeKnobs                             map[string]string
+	ignoreRuntimeKnobChanges                 bool
+	dataCenterLagSeconds                     float64
+	missingDataCenterLag                     bool
+	storageEngines                           map[string]fdbtypes.StorageEngine
+	wigglingProcessGroups                    map[string]bool
 }
 
 // adminClientCache provides a cache of mock admin clients.
@@ -132,10 +138,20 @@ func (client *mockAdminClient) GetStatus() (*fdbtypes.FoundationDBStatus, error)
 		exclusionMap[address] = true
 	}
 
+	runningVersion, err := fdbtypes.ParseFdbVersion(client.Cluster.GetRunningVersion())
+	if err != nil {
+		return nil, err
+	}
+
 	for _, pod := range pods.Items {
 		podClient, _ := internal.NewMockFdbPodClient(client.Cluster, &pod)
 
-		processCount, err := internal.GetStorageServersPerPodForPod(&pod)
+		pClass, err := podmanager.GetProcessClass(client.Cluster, &pod)
+		if err != nil {
+			return nil, err
+		}
+
+		processCount, err := internal.GetServersPerPodForPod(&pod, pClass)
 		if err != nil {
 			return nil, err
 		}
@@ -163,18 +179,41 @@ func (client *mockAdminClient) GetStatus() (*fdbtypes.FoundationDBStatus, error)
 			_, ipExcluded := exclusionMap[processIP]
 			_, addressExcluded := exclusionMap[fullAddress.String()]
 			excluded := ipExcluded || addressExcluded
-			_, isCoordinator := coordinators[fullAddress.String()]
+			coordinatorAddress := fullAddress.String()
+			dnsName := subs["FDB_DNS_NAME"]
+			if dnsName != "" {
+				dnsAddress := getDNSAddress(fullAddress, dnsName).String()
+				if _, isDNSCoordinator := coordinators[dnsAddress]; isDNSCoordinator {
+					coordinatorAddress = dnsAddress
+				}
+			}
+
+			_, isCoordinator := coordinators[coordinatorAddress]
 			if isCoordinator && !excluded {
-				coordinators[fullAddress.String()] = true
+				coordinators[coordinatorAddress] = true
 				fdbRoles = append(fdbRoles, fdbtypes.FoundationDBStatusProcessRoleInfo{Role: string(fdbtypes.ProcessRoleCoordinator)})
 			}
 
-			pClass, err := podmanager.GetProcessClass(client.Cluster, &pod)
-			if err != nil {
-				return nil, err
+			if pClass == fdbtypes.ProcessClassStorage && runningVersion.HasPerpetualStorageWiggle() {
+				storageEngine, present := client.storageEngines[processGroupID]
+				if !present && client.DatabaseConfiguration != nil {
+					storageEngine = client.DatabaseConfiguration.StorageEngine
+				}
+
+				fdbRoles = append(fdbRoles, fdbtypes.FoundationDBStatusProcessRoleInfo{
+					Role: string(fdbtypes.ProcessRoleStorage),
+					StorageMetadata: &fdbtypes.FoundationDBStatusStorageMetadata{
+						CreatedTimestamp: float64(pod.CreationTimestamp.Unix()),
+						StorageEngine:    storageEngine,
+					},
+				})
+
+				if client.wigglingProcessGroups[processGroupID] {
+					status.Cluster.StorageWiggler.WiggleServerAddresses = append(status.Cluster.StorageWiggler.WiggleServerAddresses, fullAddress)
+				}
 			}
 
-			command, err := internal.GetStartCommand(client.Cluster, pClass, podClient, processIndex, processCount)
+			command, err := internal.GetStartCommand(client.Cluster, pClass, processGroupID, podClient, processIndex, processCount)
 			if err != nil {
 				return nil, err
 			}
@@ -188,6 +227,10 @@ func (client *mockAdminClient) GetStatus() (*fdbtypes.FoundationDBStatus, error)
 				fdbtypes.FDBLocalityDCIDKey:       client.Cluster.Spec.DataCenter,
 			}
 
+			if dnsName != "" {
+				locality[fdbtypes.FDBLocalityDNSNameKey] = dnsName
+			}
+
 			for key, value := range client.localityInfo[processGroupID] {
 				locality[key] = value
 			}
@@ -214,7 +257,7 @@ func (client *mockAdminClient) GetStatus() (*fdbtypes.FoundationDBStatus, error)
 				fdbtypes.FDBLocalityZoneIDKey:     processGroup.ProcessGroupID,
 			}
 
-			for key, value := range client.localityInfo[processGroupID] {
+			for key, value := range client.localityInfo[processGroup.ProcessGroupID] {
 				locality[key] = value
 			}
 
@@ -282,6 +325,10 @@ func (client *mockAdminClient) GetStatus() (*fdbtypes.FoundationDBStatus, error)
 	status.Cluster.FullReplication = true
 	status.Cluster.Data.State.Healthy = true
 	status.Cluster.Data.State.Name = "healthy"
+	status.Cluster.RecoveryState.Name = fdbtypes.RecoveryStateFullyRecovered
+	if status.Cluster.DatabaseConfiguration.UsableRegions > 1 && !client.missingDataCenterLag {
+		status.Cluster.DatacenterLag = &fdbtypes.DatacenterLag{Seconds: client.dataCenterLagSeconds}
+	}
 
 	if len(client.Backups) > 0 {
 		status.Cluster.Layers.Backup.Tags = make(map[string]fdbtypes.FoundationDBStatusBackupTag, len(client.Backups))
@@ -623,6 +670,17 @@ func (client *mockAdminClient) MockLocalityInfo(processGroupID string, locality
 	client.localityInfo[processGroupID] = locality
 }
 
+// MockDataCenterLag sets the mock lag of the remote data center.
+func (client *mockAdminClient) MockDataCenterLag(seconds float64) {
+	client.dataCenterLagSeconds = seconds
+}
+
+// MockMissingDataCenterLag updates the mock for whether the status should
+// omit the lag of the remote data center.
+func (client *mockAdminClient) MockMissingDataCenterLag(missing bool) {
+	client.missingDataCenterLag = missing
+}
+
 // MockIncorrectCommandLine updates the mock for whether a process group should
 // be have an incorrect command-line.
 func (client *mockAdminClient) MockIncorrectCommandLine(processGroupID string, incorrect bool) {
@@ -677,3 +735,42 @@ func (client *mockAdminClient) GetCoordinatorSet() (map[string]struct{}, error)
 func (client *mockAdminClient) SetKnobs(knobs []string) {
 	client.knobs = knobs
 }
+
+// SetRuntimeKnobs sets knobs in the configuration database.
+func (client *mockAdminClient) SetRuntimeKnobs(knobs map[string]string) error {
+	adminClientMutex.Lock()
+	defer adminClientMutex.Unlock()
+
+	if client.ignoreRuntimeKnobChanges {
+		return nil
+	}
+
+	if client.RuntimeKnobs == nil {
+		client.RuntimeKnobs = make(map[string]string)
+	}
+
+	for name, value := range knobs {
+		if value == "" {
+			delete(client.RuntimeKnobs, name)
+		} else {
+			client.RuntimeKnobs[name] = value
+		}
+	}
+
+	return nil
+}
+
+// GetRuntimeKnobs reads knobs from the configuration database.
+func (client *mockAdminClient) GetRuntimeKnobs(names []string) (map[string]string, error) {
+	adminClientMutex.Lock()
+	defer adminClientMutex.Unlock()
+
+	knobs := make(map[string]string, len(names))
+	for _, name := range names {
+		if value, present := client.RuntimeKnobs[name]; present {
+			knobs[name] = value
+		}
+	}
+
+	return knobs, nil
+}
@@ -24,6 +24,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"sort"
 	"time"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
@@ -57,8 +58,10 @@ func (bounceProcesses) reconcile(ctx context.Context, r *FoundationDBClusterReco
 
 	minimumUptime := math.Inf(1)
 	addressMap := make(map[string][]fdbtypes.ProcessAddress, len(status.Cluster.Processes))
+	processGroupsByAddress := make(map[string]string, len(status.Cluster.Processes))
 	for _, process := range status.Cluster.Processes {
 		addressMap[process.Locality["instance_id"]] = append(addressMap[process.Locality["instance_id"]], process.Address)
+		processGroupsByAddress[process.Address.String()] = podmanager.GetProcessGroupIDFromProcessID(process.Locality["instance_id"])
 
 		if process.UptimeSeconds < minimumUptime {
 			minimumUptime = process.UptimeSeconds
@@ -70,6 +73,22 @@ func (bounceProcesses) reconcile(ctx context.Context, r *FoundationDBClusterReco
 		fdbtypes.IncorrectPodSpec:     false,
 	}, true)
 
+	if len(processesToBounce) == 0 {
+		var req *requeue
+		processesToBounce, req = getProcessGroupsForCertificateRotation(cluster, adminClient, status)
+		if req != nil {
+			return req
+		}
+	}
+
+	var tlsCertificateData map[string][]byte
+	if len(processesToBounce) > 0 {
+		tlsCertificateData, err = r.getTLSCertificateData(ctx, cluster)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+	}
+
 	addresses := make([]fdbtypes.ProcessAddress, 0, len(processesToBounce))
 	allSynced := true
 	var missingAddress []string
@@ -95,7 +114,7 @@ func (bounceProcesses) reconcile(ctx context.Context, r *FoundationDBClusterReco
 			return &requeue{message: fmt.Sprintf("No pod defined for process group ID: \"%s\"", processGroupID), delay: podSchedulingDelayDuration}
 		}
 
-		synced, err := r.updatePodDynamicConf(cluster, pod[0])
+		synced, err := r.updatePodDynamicConf(cluster, pod[0], tlsCertificateData)
 		if !synced {
 			allSynced = false
 			logger.Info("Update dynamic Pod config", "processGroupID", processGroupID, "synced", synced, "error", err)
@@ -125,6 +144,11 @@ func (bounceProcesses) reconcile(ctx context.Context, r *FoundationDBClusterReco
 			return &requeue{message: "Kills are disabled"}
 		}
 
+		req := checkMaintenanceWindow(cluster, "bounce")
+		if req != nil {
+			return req
+		}
+
 		if minimumUptime < float64(cluster.Spec.MinimumUptimeSecondsForBounce) {
 			r.Recorder.Event(cluster, corev1.EventTypeNormal, "NeedsBounce",
 				fmt.Sprintf("Spec require a bounce of some processes, but the cluster has only been up for %f seconds", minimumUptime))
@@ -187,9 +211,29 @@ func (bounceProcesses) reconcile(ctx context.Context, r *FoundationDBClusterReco
 		if err != nil {
 			return &requeue{curError: err}
 		}
+
+		if cluster.Status.TLSCertificate != nil {
+			bouncedProcessGroups := make(map[string]bool, len(addresses))
+			for _, address := range addresses {
+				bouncedProcessGroups[processGroupsByAddress[address.String()]] = true
+			}
+
+			for _, processGroup := range cluster.Status.ProcessGroups {
+				if bouncedProcessGroups[processGroup.ProcessGroupID] {
+					processGroup.TLSCertificate = cluster.Status.TLSCertificate.DeepCopy()
+					processGroup.UpdateCondition(fdbtypes.OutdatedTLSCertificate, false, cluster.Status.ProcessGroups, processGroup.ProcessGroupID)
+				}
+			}
+
+			err = r.Status().Update(ctx, cluster)
+			if err != nil {
+				return &requeue{curError: err}
+			}
+		}
 	}
 
 	if upgrading {
+		cluster.Status.PreviousVersion = cluster.Status.RunningVersion
 		cluster.Status.RunningVersion = cluster.Spec.Version
 		err = r.Status().Update(ctx, cluster)
 		if err != nil {
@@ -247,3 +291,48 @@ func getAddressesForUpgrade(r *FoundationDBClusterReconciler, adminClient fdbadm
 
 	return addresses, nil
 }
+
+// getProcessGroupsForCertificateRotation returns the process groups that must
+// be bounced to load the current TLS certificate. This only returns process
+// groups from a single fault domain, and only when the cluster has the desired
+// fault tolerance, so a rotation never takes out more processes than the
+// cluster can tolerate.
+func getProcessGroupsForCertificateRotation(cluster *fdbtypes.FoundationDBCluster, adminClient fdbadminclient.AdminClient, status *fdbtypes.FoundationDBStatus) ([]string, *requeue) {
+	outdated := fdbtypes.FilterByConditions(cluster.Status.ProcessGroups, map[fdbtypes.ProcessGroupConditionType]bool{
+		fdbtypes.OutdatedTLSCertificate: true,
+		fdbtypes.IncorrectPodSpec:       false,
+	}, true)
+
+	if len(outdated) == 0 {
+		return nil, nil
+	}
+
+	hasDesiredFaultTolerance, err := internal.HasDesiredFaultTolerance(adminClient, cluster)
+	if err != nil {
+		return nil, &requeue{curError: err}
+	}
+
+	if !hasDesiredFaultTolerance {
+		return nil, &requeue{
+			message: "Cluster doesn't have the desired fault tolerance, waiting before rotating TLS certificates",
+			delay:   30 * time.Second,
+		}
+	}
+
+	zones := make(map[string]string, len(status.Cluster.Processes))
+	for _, process := range status.Cluster.Processes {
+		zones[podmanager.GetProcessGroupIDFromProcessID(process.Locality[fdbtypes.FDBLocalityInstanceIDKey])] = process.Locality[fdbtypes.FDBLocalityZoneIDKey]
+	}
+
+	sort.Strings(outdated)
+	zone := zones[outdated[0]]
+
+	processGroups := make([]string, 0, len(outdated))
+	for _, processGroupID := range outdated {
+		if zones[processGroupID] == zone {
+			processGroups = append(processGroups, processGroupID)
+		}
+	}
+
+	return processGroups, nil
+}
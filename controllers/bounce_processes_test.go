@@ -28,6 +28,7 @@ import (
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
 )
@@ -96,6 +97,51 @@ var _ = Describe("bounceProcesses", func() {
 			Expect(len(adminClient.KilledAddresses)).To(Equal(len(addresses)))
 			Expect(adminClient.KilledAddresses).To(ContainElements(addresses))
 		})
+
+		When("the cluster is outside of its maintenance windows", func() {
+			BeforeEach(func() {
+				// The window opens in the next hour and stays open for one minute.
+				nextHour := time.Now().UTC().Add(time.Hour)
+				cluster.Spec.MaintenanceWindows = []fdbtypes.MaintenanceWindow{
+					{
+						Schedule: fmt.Sprintf("0 %d * * *", nextHour.Hour()),
+						Duration: metav1.Duration{Duration: time.Minute},
+					},
+				}
+			})
+
+			It("should delay the requeue until the next window", func() {
+				Expect(requeue).NotTo(BeNil())
+				Expect(requeue.delayedRequeue).To(BeTrue())
+				Expect(requeue.delay).To(BeNumerically(">", 0))
+				Expect(requeue.delay).To(BeNumerically("<=", time.Hour))
+				Expect(requeue.message).To(HavePrefix("Deferring bounce until the next maintenance window at"))
+			})
+
+			It("should not kill any processes", func() {
+				Expect(adminClient.KilledAddresses).To(BeEmpty())
+			})
+		})
+
+		When("the cluster is inside of its maintenance windows", func() {
+			BeforeEach(func() {
+				cluster.Spec.MaintenanceWindows = []fdbtypes.MaintenanceWindow{
+					{
+						Schedule: "* * * * *",
+						Duration: metav1.Duration{Duration: time.Hour},
+						TimeZone: "Europe/Berlin",
+					},
+				}
+			})
+
+			It("should not requeue", func() {
+				Expect(requeue).To(BeNil())
+			})
+
+			It("should kill the targeted processes", func() {
+				Expect(len(adminClient.KilledAddresses)).To(BeNumerically(">", 0))
+			})
+		})
 	})
 
 	Context("with pod in pending state", func() {
@@ -23,6 +23,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -78,6 +79,26 @@ func (c changeCoordinators) reconcile(ctx context.Context, r *FoundationDBCluste
 	}
 
 	if hasValidCoordinators {
+		for _, processGroup := range cluster.Status.ProcessGroups {
+			unreachableTime := processGroup.GetConditionTime(fdbtypes.CoordinatorUnreachable)
+			if unreachableTime == nil {
+				continue
+			}
+
+			// Check again once the coordinator has been unreachable for long
+			// enough to be moved to another process.
+			return &requeue{
+				message:        fmt.Sprintf("Coordinator on process group %s is unreachable", processGroup.ProcessGroupID),
+				delay:          delayUntil(time.Now(), time.Unix(*unreachableTime, 0).Add(cluster.GetCoordinatorUnreachableDuration())),
+				delayedRequeue: true,
+			}
+		}
+
+		return nil
+	}
+
+	if cluster.Status.TLSMigration != nil && cluster.Status.TLSMigration.Phase == fdbtypes.TLSMigrationPhaseEnablingDualListeners {
+		logger.Info("Deferring coordinator change until all processes listen on TLS and non-TLS addresses")
 		return nil
 	}
 
@@ -87,6 +108,20 @@ func (c changeCoordinators) reconcile(ctx context.Context, r *FoundationDBCluste
 		return nil
 	}
 
+	freezeRequeue := checkAutomationFreeze(cluster, r.Recorder, fdbtypes.AutomationOperationCoordinatorChanges)
+	if freezeRequeue != nil {
+		return freezeRequeue
+	}
+
+	// Replacing coordinators that can't be reached restores the fault
+	// tolerance of the cluster, so only voluntary changes are deferred.
+	if !hasUnreachableCoordinators(cluster, status) {
+		windowRequeue := checkMaintenanceWindow(cluster, "coordinator change")
+		if windowRequeue != nil {
+			return windowRequeue
+		}
+	}
+
 	hasLock, err := r.takeLock(cluster, "changing coordinators")
 	if !hasLock {
 		return &requeue{curError: err}
@@ -119,9 +154,33 @@ func (c changeCoordinators) reconcile(ctx context.Context, r *FoundationDBCluste
 	return nil
 }
 
+// hasUnreachableCoordinators checks if any of the current coordinators is
+// unreachable, either from the client's point of view or because the process
+// group has been unreachable for longer than the configured duration.
+func hasUnreachableCoordinators(cluster *fdbtypes.FoundationDBCluster, status *fdbtypes.FoundationDBStatus) bool {
+	for _, coordinator := range status.Client.Coordinators.Coordinators {
+		if !coordinator.Reachable {
+			return true
+		}
+	}
+
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if processGroup.GetConditionTime(fdbtypes.CoordinatorUnreachable) != nil && coordinatorShouldBeMoved(cluster, processGroup) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // selectCandidates is a helper for Reconcile that picks non-excluded, not-being-removed class-matching process groups.
 func selectCandidates(cluster *fdbtypes.FoundationDBCluster, status *fdbtypes.FoundationDBStatus) ([]localityInfo, error) {
 	candidates := make([]localityInfo, 0, len(status.Cluster.Processes))
+	processGroups := make(map[string]*fdbtypes.ProcessGroupStatus, len(cluster.Status.ProcessGroups))
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		processGroups[processGroup.ProcessGroupID] = processGroup
+	}
+
 	for _, process := range status.Cluster.Processes {
 		if process.Excluded {
 			continue
@@ -135,11 +194,19 @@ func selectCandidates(cluster *fdbtypes.FoundationDBCluster, status *fdbtypes.Fo
 			continue
 		}
 
+		if coordinatorShouldBeMoved(cluster, processGroups[process.Locality[fdbtypes.FDBLocalityInstanceIDKey]]) {
+			continue
+		}
+
 		locality, err := localityInfoForProcess(process, cluster.Spec.MainContainer.EnableTLS)
 		if err != nil {
 			return candidates, err
 		}
 
+		if cluster.UseDNSInClusterFile() {
+			locality.Address = getDNSAddress(locality.Address, process.Locality[fdbtypes.FDBLocalityDNSNameKey])
+		}
+
 		candidates = append(candidates, locality)
 	}
 
@@ -157,6 +224,7 @@ func selectCoordinators(cluster *fdbtypes.FoundationDBCluster, status *fdbtypes.
 	}
 
 	coordinators, err := chooseDistributedProcesses(cluster, candidates, coordinatorCount, processSelectionConstraint{
+		Fields:     getCoordinatorFields(cluster),
 		HardLimits: getHardLimits(cluster),
 	})
 
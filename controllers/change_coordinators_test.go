@@ -21,16 +21,21 @@
 package controllers
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"net"
 	"strings"
+	"time"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
 
 	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 )
 
 var _ = Describe("Change coordinators", func() {
@@ -196,6 +201,58 @@ var _ = Describe("Change coordinators", func() {
 		})
 	})
 
+	When("using dedicated coordinators", func() {
+		BeforeEach(func() {
+			cluster.Spec.UseDedicatedCoordinators = pointer.Bool(true)
+			err := k8sClient.Update(context.TODO(), cluster)
+			Expect(err).NotTo(HaveOccurred())
+
+			result, err := reconcileCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeFalse())
+
+			_, err = reloadCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should create the coordinator process groups", func() {
+			pods := &corev1.PodList{}
+			err := k8sClient.List(context.TODO(), pods, internal.GetPodListOptions(cluster, fdbtypes.ProcessClassCoordinator, "")...)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pods.Items).To(HaveLen(cluster.DesiredCoordinatorCount() + cluster.DesiredFaultTolerance()))
+		})
+
+		It("should move the coordinators to the coordinator processes", func() {
+			status, err := adminClient.GetStatus()
+			Expect(err).NotTo(HaveOccurred())
+
+			coordinatorCount := 0
+			for _, process := range status.Cluster.Processes {
+				for _, role := range process.Roles {
+					if role.Role != string(fdbtypes.ProcessRoleCoordinator) {
+						continue
+					}
+
+					coordinatorCount++
+					Expect(process.ProcessClass).To(Equal(fdbtypes.ProcessClassCoordinator))
+				}
+			}
+			Expect(coordinatorCount).To(Equal(cluster.DesiredCoordinatorCount()))
+		})
+
+		It("should only select coordinator processes", func() {
+			status, err := adminClient.GetStatus()
+			Expect(err).NotTo(HaveOccurred())
+
+			candidates, err := selectCoordinators(cluster, status)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(candidates)).To(BeNumerically("==", cluster.DesiredCoordinatorCount()))
+			for _, candidate := range candidates {
+				Expect(strings.HasPrefix(candidate.ID, string(fdbtypes.ProcessClassCoordinator))).To(BeTrue())
+			}
+		})
+	})
+
 	When("Using a HA clusters", func() {
 		var status *fdbtypes.FoundationDBStatus
 		var candidates []localityInfo
@@ -606,6 +663,207 @@ var _ = Describe("Change coordinators", func() {
 
 	// TODO add test case for multi KC
 
+	When("using the three_data_hall redundancy mode", func() {
+		var status *fdbtypes.FoundationDBStatus
+		var candidates []localityInfo
+		var dataHallCount int
+		var selectErr error
+
+		JustBeforeEach(func() {
+			cluster.Spec.DatabaseConfiguration.RedundancyMode = fdbtypes.RedundancyModeThreeDataHall
+
+			var err error
+			status, err = adminClient.GetStatus()
+			Expect(err).NotTo(HaveOccurred())
+
+			status.Cluster.Processes = generateProcessInfo(1, 0, nil)
+			processIndex := 0
+			for _, process := range status.Cluster.Processes {
+				process.Locality[fdbtypes.FDBLocalityDataHallKey] = fmt.Sprintf("hall%d", processIndex%dataHallCount)
+				processIndex++
+			}
+
+			candidates, selectErr = selectCoordinators(cluster, status)
+		})
+
+		When("the processes are spread across three data halls", func() {
+			BeforeEach(func() {
+				dataHallCount = 3
+			})
+
+			It("should select three coordinators in every data hall", func() {
+				Expect(selectErr).NotTo(HaveOccurred())
+				Expect(cluster.DesiredCoordinatorCount()).To(BeNumerically("==", 9))
+				Expect(len(candidates)).To(BeNumerically("==", cluster.DesiredCoordinatorCount()))
+
+				dataHallCnt := map[string]int{}
+				zoneCnt := map[string]int{}
+				for _, candidate := range candidates {
+					dataHallCnt[candidate.LocalityData[fdbtypes.FDBLocalityDataHallKey]]++
+					zoneCnt[candidate.LocalityData[fdbtypes.FDBLocalityZoneIDKey]]++
+				}
+
+				Expect(dataHallCnt).To(Equal(map[string]int{"hall0": 3, "hall1": 3, "hall2": 3}))
+				Expect(len(zoneCnt)).To(BeNumerically("==", 9))
+			})
+		})
+
+		When("the processes are spread across two data halls", func() {
+			BeforeEach(func() {
+				dataHallCount = 2
+			})
+
+			It("should not be able to select the coordinators", func() {
+				Expect(selectErr).To(HaveOccurred())
+			})
+		})
+	})
+
+	When("a coordinator should be moved to another process", func() {
+		var status *fdbtypes.FoundationDBStatus
+		var coordinatorStatus map[string]bool
+		var movedProcessGroup *fdbtypes.ProcessGroupStatus
+
+		BeforeEach(func() {
+			var err error
+			status, err = adminClient.GetStatus()
+			Expect(err).NotTo(HaveOccurred())
+
+			status.Cluster.Processes = generateProcessInfo(1, 0, nil)
+			cluster.Status.ProcessGroups = make([]*fdbtypes.ProcessGroupStatus, 0, len(status.Cluster.Processes))
+			for processGroupID := range status.Cluster.Processes {
+				cluster.Status.ProcessGroups = append(cluster.Status.ProcessGroups, &fdbtypes.ProcessGroupStatus{ProcessGroupID: processGroupID})
+			}
+
+			coordinators, err := selectCoordinators(cluster, status)
+			Expect(err).NotTo(HaveOccurred())
+
+			coordinatorStatus = make(map[string]bool, len(coordinators))
+			for _, coordinator := range coordinators {
+				coordinatorStatus[coordinator.Address.String()] = false
+			}
+
+			for _, processGroup := range cluster.Status.ProcessGroups {
+				if processGroup.ProcessGroupID == coordinators[0].ID {
+					movedProcessGroup = processGroup
+					break
+				}
+			}
+		})
+
+		When("the pod is running on an unschedulable node", func() {
+			BeforeEach(func() {
+				movedProcessGroup.UpdateCondition(fdbtypes.NodeUnschedulable, true, nil, "")
+			})
+
+			It("should consider the coordinators invalid", func() {
+				hasValidCoordinators, _, err := checkCoordinatorValidity(cluster, status, coordinatorStatus)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hasValidCoordinators).To(BeFalse())
+			})
+
+			It("should not select the process as candidate", func() {
+				candidates, err := selectCoordinators(cluster, status)
+				Expect(err).NotTo(HaveOccurred())
+				for _, candidate := range candidates {
+					Expect(candidate.ID).NotTo(Equal(movedProcessGroup.ProcessGroupID))
+				}
+			})
+		})
+
+		When("the coordinator was unreachable for a short time", func() {
+			BeforeEach(func() {
+				movedProcessGroup.ProcessGroupConditions = append(movedProcessGroup.ProcessGroupConditions, &fdbtypes.ProcessGroupCondition{
+					ProcessGroupConditionType: fdbtypes.CoordinatorUnreachable,
+					Timestamp:                 time.Now().Unix(),
+				})
+			})
+
+			It("should keep the coordinators", func() {
+				hasValidCoordinators, _, err := checkCoordinatorValidity(cluster, status, coordinatorStatus)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hasValidCoordinators).To(BeTrue())
+			})
+		})
+
+		When("the coordinator was unreachable for a long time", func() {
+			BeforeEach(func() {
+				movedProcessGroup.ProcessGroupConditions = append(movedProcessGroup.ProcessGroupConditions, &fdbtypes.ProcessGroupCondition{
+					ProcessGroupConditionType: fdbtypes.CoordinatorUnreachable,
+					Timestamp:                 time.Now().Add(-1 * time.Hour).Unix(),
+				})
+			})
+
+			It("should consider the coordinators invalid", func() {
+				hasValidCoordinators, _, err := checkCoordinatorValidity(cluster, status, coordinatorStatus)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hasValidCoordinators).To(BeFalse())
+			})
+		})
+	})
+
+	When("using DNS names in the cluster file", func() {
+		var status *fdbtypes.FoundationDBStatus
+		var candidates []localityInfo
+
+		BeforeEach(func() {
+			cluster.Spec.Version = fdbtypes.Versions.WithDNSInClusterFile.String()
+			enabled := true
+			cluster.Spec.Routing.UseDNSInClusterFile = &enabled
+
+			var err error
+			status, err = adminClient.GetStatus()
+			Expect(err).NotTo(HaveOccurred())
+
+			status.Cluster.Processes = generateProcessInfo(1, 0, nil)
+			for _, process := range status.Cluster.Processes {
+				process.Locality[fdbtypes.FDBLocalityDNSNameKey] = internal.GetPodDNSName(cluster, process.Locality[fdbtypes.FDBLocalityInstanceIDKey])
+			}
+
+			candidates, err = selectCoordinators(cluster, status)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should select the coordinators with their DNS names", func() {
+			Expect(len(candidates)).To(BeNumerically("==", cluster.DesiredCoordinatorCount()))
+
+			for _, candidate := range candidates {
+				Expect(candidate.Address.IsDNSAddress()).To(BeTrue())
+				Expect(candidate.Address.StringAddress).To(Equal(internal.GetPodDNSName(cluster, candidate.ID)))
+				Expect(candidate.Address.Port).To(Equal(4501))
+			}
+		})
+
+		It("should consider coordinators with IP addresses invalid", func() {
+			coordinatorStatus := make(map[string]bool, len(candidates))
+			for _, candidate := range candidates {
+				process := status.Cluster.Processes[candidate.ID]
+				coordinatorStatus[process.Address.String()] = false
+			}
+
+			hasValidCoordinators, allAddressesValid, err := checkCoordinatorValidity(cluster, status, coordinatorStatus)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hasValidCoordinators).To(BeFalse())
+			Expect(allAddressesValid).To(BeTrue())
+		})
+
+		When("DNS names are disabled again", func() {
+			It("should consider coordinators with DNS names invalid", func() {
+				enabled := false
+				cluster.Spec.Routing.UseDNSInClusterFile = &enabled
+
+				coordinatorStatus := make(map[string]bool, len(candidates))
+				for _, candidate := range candidates {
+					coordinatorStatus[candidate.Address.String()] = false
+				}
+
+				hasValidCoordinators, _, err := checkCoordinatorValidity(cluster, status, coordinatorStatus)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hasValidCoordinators).To(BeFalse())
+			})
+		})
+	})
+
 	When("Sorting the localities", func() {
 		var localities []localityInfo
 
@@ -701,6 +959,66 @@ var _ = Describe("Change coordinators", func() {
 			})
 		})
 	})
+
+	Context("when reconciling outside of the maintenance windows", func() {
+		var requeue *requeue
+		var originalConnectionString string
+		var coordinatorID string
+
+		BeforeEach(func() {
+			// The window opens in the next hour and stays open for one minute.
+			nextHour := time.Now().UTC().Add(time.Hour)
+			cluster.Spec.MaintenanceWindows = []fdbtypes.MaintenanceWindow{
+				{
+					Schedule: fmt.Sprintf("0 %d * * *", nextHour.Hour()),
+					Duration: metav1.Duration{Duration: time.Minute},
+				},
+			}
+			originalConnectionString = cluster.Status.ConnectionString
+
+			status, err := adminClient.GetStatus()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status.Client.Coordinators.Coordinators).NotTo(BeEmpty())
+			coordinatorIP := status.Client.Coordinators.Coordinators[0].Address.IPAddress.String()
+
+			for _, processGroup := range cluster.Status.ProcessGroups {
+				for _, address := range processGroup.Addresses {
+					if address == coordinatorIP {
+						coordinatorID = processGroup.ProcessGroupID
+					}
+				}
+			}
+			Expect(coordinatorID).NotTo(BeEmpty())
+		})
+
+		JustBeforeEach(func() {
+			requeue = changeCoordinators{}.reconcile(context.TODO(), clusterReconciler, cluster)
+		})
+
+		When("a coordinator should be moved voluntarily", func() {
+			BeforeEach(func() {
+				fdbtypes.FindProcessGroupByID(cluster.Status.ProcessGroups, coordinatorID).Remove = true
+			})
+
+			It("should defer the coordinator change", func() {
+				Expect(requeue).NotTo(BeNil())
+				Expect(requeue.delayedRequeue).To(BeTrue())
+				Expect(requeue.message).To(HavePrefix("Deferring coordinator change until the next maintenance window at"))
+				Expect(cluster.Status.ConnectionString).To(Equal(originalConnectionString))
+			})
+		})
+
+		When("a coordinator is unreachable", func() {
+			BeforeEach(func() {
+				adminClient.MockMissingProcessGroup(coordinatorID, true)
+			})
+
+			It("should change the coordinators", func() {
+				Expect(requeue).To(BeNil())
+				Expect(cluster.Status.ConnectionString).NotTo(Equal(originalConnectionString))
+			})
+		})
+	})
 })
 
 func generateProcessInfo(dcCount int, satCount int, excludes []string) map[string]fdbtypes.FoundationDBStatusProcessInfo {
@@ -23,10 +23,15 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 
 	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
 )
@@ -58,14 +63,16 @@ func (c checkClientCompatibility) reconcile(_ context.Context, r *FoundationDBCl
 		return &requeue{curError: err}
 	}
 
-	if !version.IsAtLeast(runningVersion) {
-		return &requeue{message: "cluster downgrade operation is not supported"}
-	}
-
+	// Rolling back to a protocol-compatible patch version uses the same
+	// process as an upgrade and doesn't require any client checks.
 	if version.IsProtocolCompatible(runningVersion) {
 		return nil
 	}
 
+	if !version.IsAtLeast(runningVersion) {
+		return &requeue{message: "cluster downgrade operation is only supported for protocol-compatible versions"}
+	}
+
 	status, err := adminClient.GetStatus()
 	if err != nil {
 		return &requeue{curError: err}
@@ -77,43 +84,9 @@ func (c checkClientCompatibility) reconcile(_ context.Context, r *FoundationDBCl
 	}
 
 	if !cluster.Spec.IgnoreUpgradabilityChecks {
-		var unsupportedClients []string
-		if runningVersion.HasMaxProtocolClientsInStatus() {
-			unsupportedClients = make([]string, 0)
-			for _, versionInfo := range status.Cluster.Clients.SupportedVersions {
-				if versionInfo.ProtocolVersion == "Unknown" {
-					continue
-				}
-				match := versionInfo.ProtocolVersion == protocolVersion
-
-				if !match {
-					for _, client := range versionInfo.MaxProtocolClients {
-						unsupportedClients = append(unsupportedClients, client.Description())
-					}
-				}
-			}
-		} else {
-			clientsSupported := make(map[string]bool)
-			for _, versionInfo := range status.Cluster.Clients.SupportedVersions {
-				if versionInfo.ProtocolVersion == "Unknown" {
-					continue
-				}
-				match := versionInfo.ProtocolVersion == protocolVersion
-				for _, client := range versionInfo.ConnectedClients {
-					description := client.Description()
-					if match {
-						clientsSupported[description] = true
-					} else if !clientsSupported[description] {
-						clientsSupported[description] = false
-					}
-				}
-			}
-			unsupportedClients = make([]string, 0, len(clientsSupported))
-			for client, supported := range clientsSupported {
-				if !supported {
-					unsupportedClients = append(unsupportedClients, client)
-				}
-			}
+		unsupportedClients := make([]string, 0)
+		for _, client := range getUnsupportedClients(status, runningVersion, protocolVersion) {
+			unsupportedClients = append(unsupportedClients, client.Description())
 		}
 
 		if len(unsupportedClients) > 0 {
@@ -129,3 +102,151 @@ func (c checkClientCompatibility) reconcile(_ context.Context, r *FoundationDBCl
 
 	return nil
 }
+
+// getUnsupportedClients returns the connected clients that don't support the
+// given protocol version.
+func getUnsupportedClients(status *fdbtypes.FoundationDBStatus, runningVersion fdbtypes.FdbVersion, protocolVersion string) []fdbtypes.UpgradeCheckClient {
+	var unsupportedClients []fdbtypes.UpgradeCheckClient
+	if runningVersion.HasMaxProtocolClientsInStatus() {
+		unsupportedClients = make([]fdbtypes.UpgradeCheckClient, 0)
+		for _, versionInfo := range status.Cluster.Clients.SupportedVersions {
+			if versionInfo.ProtocolVersion == "Unknown" {
+				continue
+			}
+			match := versionInfo.ProtocolVersion == protocolVersion
+
+			if !match {
+				for _, client := range versionInfo.MaxProtocolClients {
+					unsupportedClients = append(unsupportedClients, fdbtypes.UpgradeCheckClient{
+						FoundationDBStatusConnectedClient: client,
+						Version:                           versionInfo.ClientVersion,
+					})
+				}
+			}
+		}
+	} else {
+		clientsSupported := make(map[string]bool)
+		clients := make(map[string]fdbtypes.UpgradeCheckClient)
+		for _, versionInfo := range status.Cluster.Clients.SupportedVersions {
+			if versionInfo.ProtocolVersion == "Unknown" {
+				continue
+			}
+			match := versionInfo.ProtocolVersion == protocolVersion
+			for _, client := range versionInfo.ConnectedClients {
+				description := client.Description()
+				if match {
+					clientsSupported[description] = true
+				} else if !clientsSupported[description] {
+					clientsSupported[description] = false
+					clients[description] = fdbtypes.UpgradeCheckClient{
+						FoundationDBStatusConnectedClient: client,
+						Version:                           versionInfo.ClientVersion,
+					}
+				}
+			}
+		}
+		unsupportedClients = make([]fdbtypes.UpgradeCheckClient, 0, len(clientsSupported))
+		for description, supported := range clientsSupported {
+			if !supported {
+				unsupportedClients = append(unsupportedClients, clients[description])
+			}
+		}
+	}
+
+	sort.Slice(unsupportedClients, func(i, j int) bool {
+		return unsupportedClients[i].Description() < unsupportedClients[j].Description()
+	})
+
+	return unsupportedClients
+}
+
+// getUpgradeCheckStatus runs the pre-flight checks for an upgrade of the
+// cluster to the given version.
+func getUpgradeCheckStatus(cluster *fdbtypes.FoundationDBCluster, adminClient fdbadminclient.AdminClient, status *fdbtypes.FoundationDBStatus, versionString string) *fdbtypes.UpgradeCheckStatus {
+	result := &fdbtypes.UpgradeCheckStatus{
+		Version:   versionString,
+		Timestamp: &metav1.Time{Time: time.Now()},
+	}
+
+	version, err := fdbtypes.ParseFdbVersion(versionString)
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result
+	}
+
+	runningVersion, err := fdbtypes.ParseFdbVersion(cluster.Status.RunningVersion)
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result
+	}
+
+	if !version.IsAtLeast(runningVersion) && !version.IsProtocolCompatible(runningVersion) {
+		result.Errors = append(result.Errors, fmt.Sprintf("downgrade from %s to %s is not supported", runningVersion, version))
+	} else if !version.IsProtocolCompatible(runningVersion) {
+		protocolVersion, err := adminClient.GetProtocolVersion(versionString)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("could not get the protocol version for %s: %v", versionString, err))
+		} else {
+			result.UnsupportedClients = getUnsupportedClients(status, runningVersion, protocolVersion)
+		}
+	}
+
+	// Build the pod specs for the new version to make sure that all images
+	// can be resolved.
+	upgradedCluster := cluster.DeepCopy()
+	upgradedCluster.Spec.Version = versionString
+	upgradedCluster.Status.RunningVersion = versionString
+
+	images := make(map[string]fdbtypes.None)
+	result.ImagesResolved = true
+	for _, processClass := range getProcessClasses(cluster) {
+		spec, err := internal.GetPodSpec(upgradedCluster, processClass, 0)
+		if err != nil {
+			result.ImagesResolved = false
+			result.Errors = append(result.Errors, fmt.Sprintf("could not resolve the images for process class %s: %v", processClass, err))
+			continue
+		}
+
+		for _, container := range append(spec.InitContainers, spec.Containers...) {
+			images[container.Image] = fdbtypes.None{}
+		}
+	}
+
+	result.Images = make([]string, 0, len(images))
+	for image := range images {
+		result.Images = append(result.Images, image)
+	}
+	sort.Strings(result.Images)
+
+	// The sidecar must be able to provide the binaries of the main container
+	// during the upgrade. Clusters with the unified image don't use a sidecar
+	// for the upgrade.
+	result.SidecarsSupported = pointer.BoolDeref(cluster.Spec.UseUnifiedImage, false) || version.SupportsUsingBinariesFromMainContainer()
+	if !result.SidecarsSupported {
+		result.Errors = append(result.Errors, fmt.Sprintf("the sidecar doesn't support version %s", versionString))
+	}
+
+	result.Passed = len(result.Errors) == 0 && len(result.UnsupportedClients) == 0
+
+	return result
+}
+
+// getProcessClasses returns the process classes of the process groups in the
+// cluster.
+func getProcessClasses(cluster *fdbtypes.FoundationDBCluster) []fdbtypes.ProcessClass {
+	classMap := make(map[fdbtypes.ProcessClass]fdbtypes.None)
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		classMap[processGroup.ProcessClass] = fdbtypes.None{}
+	}
+
+	processClasses := make([]fdbtypes.ProcessClass, 0, len(classMap))
+	for processClass := range classMap {
+		processClasses = append(processClasses, processClass)
+	}
+
+	sort.Slice(processClasses, func(i, j int) bool {
+		return processClasses[i] < processClasses[j]
+	})
+
+	return processClasses
+}
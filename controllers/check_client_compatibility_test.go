@@ -0,0 +1,138 @@
+/*
+ * check_client_compatibility_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2021 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("check_client_compatibility", func() {
+	var cluster *fdbtypes.FoundationDBCluster
+	var adminClient *mockAdminClient
+	var result *fdbtypes.UpgradeCheckStatus
+	var version string
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		err := setupClusterForTest(cluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		adminClient, err = newMockAdminClientUncast(cluster, k8sClient)
+		Expect(err).NotTo(HaveOccurred())
+		version = fdbtypes.Versions.NextMajorVersion.String()
+	})
+
+	JustBeforeEach(func() {
+		status, err := adminClient.GetStatus()
+		Expect(err).NotTo(HaveOccurred())
+		result = getUpgradeCheckStatus(cluster, adminClient, status, version)
+	})
+
+	When("all clients support the new version", func() {
+		BeforeEach(func() {
+			adminClient.MockClientVersion(version, []string{"127.0.0.2:3687"})
+		})
+
+		It("should pass the checks", func() {
+			Expect(result.Version).To(Equal(version))
+			Expect(result.Timestamp).NotTo(BeNil())
+			Expect(result.Passed).To(BeTrue())
+			Expect(result.UnsupportedClients).To(BeEmpty())
+			Expect(result.Errors).To(BeEmpty())
+		})
+
+		It("should report the images for the new version", func() {
+			Expect(result.ImagesResolved).To(BeTrue())
+			Expect(result.Images).To(Equal([]string{
+				"foundationdb/foundationdb-kubernetes-sidecar:7.0.0-1",
+				"foundationdb/foundationdb:7.0.0",
+			}))
+		})
+
+		It("should report that the sidecars support the new version", func() {
+			Expect(result.SidecarsSupported).To(BeTrue())
+		})
+	})
+
+	When("a client doesn't support the new version", func() {
+		BeforeEach(func() {
+			adminClient.MockClientVersion(version, []string{"127.0.0.2:3687"})
+			adminClient.MockClientVersion(fdbtypes.Versions.Default.String(), []string{"127.0.0.3:85891"})
+		})
+
+		It("should report the client", func() {
+			Expect(result.Passed).To(BeFalse())
+			Expect(result.UnsupportedClients).To(HaveLen(1))
+			Expect(result.UnsupportedClients[0].Address).To(Equal("127.0.0.3:85891"))
+			Expect(result.UnsupportedClients[0].Version).To(Equal(fdbtypes.Versions.Default.String()))
+		})
+	})
+
+	When("the new version is protocol compatible", func() {
+		BeforeEach(func() {
+			version = fdbtypes.Versions.NextPatchVersion.String()
+			adminClient.MockClientVersion(fdbtypes.Versions.Default.String(), []string{"127.0.0.3:85891"})
+		})
+
+		It("should not check the clients", func() {
+			Expect(result.Passed).To(BeTrue())
+			Expect(result.UnsupportedClients).To(BeEmpty())
+		})
+	})
+
+	When("the new version is a rollback to a previous patch version", func() {
+		BeforeEach(func() {
+			version = "6.2.19"
+			adminClient.MockClientVersion(fdbtypes.Versions.NextMajorVersion.String(), []string{"127.0.0.3:85891"})
+		})
+
+		It("should pass the checks", func() {
+			Expect(result.Passed).To(BeTrue())
+			Expect(result.Errors).To(BeEmpty())
+		})
+	})
+
+	When("the new version is a downgrade", func() {
+		BeforeEach(func() {
+			version = "6.1.0"
+		})
+
+		It("should report an error", func() {
+			Expect(result.Passed).To(BeFalse())
+			Expect(result.Errors).To(ContainElement("downgrade from 6.2.20 to 6.1.0 is not supported"))
+		})
+	})
+
+	When("the version is invalid", func() {
+		BeforeEach(func() {
+			version = "invalid"
+		})
+
+		It("should report an error", func() {
+			Expect(result.Passed).To(BeFalse())
+			Expect(result.Errors).To(HaveLen(1))
+		})
+	})
+})
@@ -0,0 +1,97 @@
+/*
+ * client_library_injector.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2021 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:path=/mutate-v1-pod,mutating=true,failurePolicy=ignore,sideEffects=None,groups="",resources=pods,verbs=create,versions=v1,name=client-libraries.foundationdb.org,admissionReviewVersions=v1
+
+// ClientLibraryInjector provides an admission webhook that injects the client
+// libraries of a cluster into pods with the
+// foundationdb.org/client-libraries-for label.
+type ClientLibraryInjector struct {
+	// Reader reads the published client libraries. This should read directly
+	// from the API server, since the config maps can be in any namespace that
+	// the operator doesn't cache.
+	Reader  client.Reader
+	decoder *admission.Decoder
+}
+
+// Handle injects the client libraries into the pod from the request.
+func (injector *ClientLibraryInjector) Handle(ctx context.Context, req admission.Request) admission.Response {
+	pod := &corev1.Pod{}
+	err := injector.decoder.Decode(req, pod)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	clusterName := pod.Labels[fdbtypes.ClientLibrariesLabel]
+	if clusterName == "" {
+		return admission.Allowed("no client libraries requested")
+	}
+
+	namespace := req.Namespace
+	if namespace == "" {
+		namespace = pod.Namespace
+	}
+
+	logger := log.WithValues("namespace", namespace, "cluster", clusterName, "webhook", "ClientLibraryInjector")
+
+	configMap := &corev1.ConfigMap{}
+	err = injector.Reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: internal.GetClientLibraryConfigMapName(clusterName)}, configMap)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			logger.Info("No client libraries published for cluster")
+			return admission.Allowed(fmt.Sprintf("no client libraries published for cluster %s", clusterName))
+		}
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	if !internal.InjectClientLibraries(pod, configMap) {
+		return admission.Allowed("client libraries already injected")
+	}
+
+	marshaledPod, err := json.Marshal(pod)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaledPod)
+}
+
+// InjectDecoder injects the decoder for the admission requests.
+func (injector *ClientLibraryInjector) InjectDecoder(decoder *admission.Decoder) error {
+	injector.decoder = decoder
+	return nil
+}
@@ -0,0 +1,135 @@
+/*
+ * client_library_injector_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2021 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var _ = Describe("client_library_injector", func() {
+	var cluster *fdbtypes.FoundationDBCluster
+	var injector *ClientLibraryInjector
+	var pod *corev1.Pod
+	var response admission.Response
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		cluster.Spec.ClientLibraries.Enabled = pointer.Bool(true)
+		cluster.Spec.ClientLibraries.AdditionalVersions = []string{fdbtypes.Versions.NextMajorVersion.String()}
+		err := setupClusterForTest(cluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		decoder, err := admission.NewDecoder(scheme.Scheme)
+		Expect(err).NotTo(HaveOccurred())
+		injector = &ClientLibraryInjector{Reader: k8sClient}
+		Expect(injector.InjectDecoder(decoder)).NotTo(HaveOccurred())
+
+		pod = &corev1.Pod{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Pod",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "app",
+				Namespace: cluster.Namespace,
+				Labels: map[string]string{
+					fdbtypes.ClientLibrariesLabel: cluster.Name,
+				},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "app"}},
+			},
+		}
+	})
+
+	It("should publish the client libraries", func() {
+		configMap := &corev1.ConfigMap{}
+		err := k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: cluster.Namespace, Name: internal.GetClientLibraryConfigMapName(cluster.Name)}, configMap)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(configMap.Data).To(Equal(map[string]string{
+			"6.2": "foundationdb/foundationdb-kubernetes-sidecar:6.2.20-1",
+			"7.0": "foundationdb/foundationdb-kubernetes-sidecar:7.0.0-1",
+		}))
+	})
+
+	JustBeforeEach(func() {
+		rawPod, err := json.Marshal(pod)
+		Expect(err).NotTo(HaveOccurred())
+
+		response = injector.Handle(context.TODO(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Namespace: cluster.Namespace,
+				Operation: admissionv1.Create,
+				Object:    runtime.RawExtension{Raw: rawPod},
+			},
+		})
+	})
+
+	When("the pod requests the client libraries", func() {
+		It("should inject the client libraries", func() {
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Patches).NotTo(BeEmpty())
+
+			paths := make([]string, 0, len(response.Patches))
+			for _, patch := range response.Patches {
+				paths = append(paths, patch.Path)
+			}
+			Expect(paths).To(ContainElements("/spec/initContainers", "/spec/volumes", "/spec/containers/0/env", "/spec/containers/0/volumeMounts"))
+		})
+	})
+
+	When("the pod doesn't request the client libraries", func() {
+		BeforeEach(func() {
+			pod.Labels = nil
+		})
+
+		It("should not change the pod", func() {
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Patches).To(BeEmpty())
+		})
+	})
+
+	When("the cluster doesn't publish client libraries", func() {
+		BeforeEach(func() {
+			pod.Labels[fdbtypes.ClientLibrariesLabel] = "missing"
+		})
+
+		It("should not change the pod", func() {
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Patches).To(BeEmpty())
+		})
+	})
+})
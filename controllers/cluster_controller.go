@@ -28,6 +28,8 @@ import (
 	"math"
 	"regexp"
 	"sort"
+	"strings"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -47,6 +49,7 @@ import (
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 // FoundationDBClusterReconciler reconciles a FoundationDBCluster object
@@ -59,6 +62,26 @@ type FoundationDBClusterReconciler struct {
 	PodClientProvider      func(*fdbtypes.FoundationDBCluster, *corev1.Pod) (podclient.FdbPodClient, error)
 	DatabaseClientProvider DatabaseClientProvider
 	DeprecationOptions     internal.DeprecationOptions
+	EnableNodeChecks       bool
+	// NodeReader is used to read the nodes of the pods when EnableNodeChecks
+	// is set and to read the node labels for substitution variables. This should be an uncached reader, so that the operator doesn't
+	// start a cluster-wide watch for all nodes.
+	NodeReader         client.Reader
+	CertificateIssuers map[string]CertificateIssuer
+	// ClientConfigTargetNamespaces defines the namespaces other than the
+	// namespace of the cluster where the operator may publish client config
+	// targets.
+	ClientConfigTargetNamespaces []string
+	// ClientConfigTargetReader is used to read the client config targets. This
+	// should be an uncached reader, because the cache of the operator may be
+	// limited to the namespace it watches.
+	ClientConfigTargetReader client.Reader
+	// UsePolicyV1PodDisruptionBudgets defines whether the PodDisruptionBudgets
+	// are managed through policy/v1 instead of policy/v1beta1.
+	UsePolicyV1PodDisruptionBudgets bool
+	// clock provides the current time for decisions that depend on the
+	// maintenance windows. This is only set in tests.
+	clock func() time.Time
 }
 
 // NewFoundationDBClusterReconciler creates a new FoundationDBClusterReconciler with defaults.
@@ -72,6 +95,8 @@ func NewFoundationDBClusterReconciler(podLifecycleManager podmanager.PodLifecycl
 // +kubebuilder:rbac:groups=apps.foundationdb.org,resources=foundationdbclusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps.foundationdb.org,resources=foundationdbclusters/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups="",resources=pods;configmaps;persistentvolumeclaims;events;secrets;services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get
 
 // Reconcile runs the reconciliation logic.
 func (r *FoundationDBClusterReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
@@ -95,6 +120,10 @@ func (r *FoundationDBClusterReconciler) Reconcile(ctx context.Context, request c
 		return ctrl.Result{}, nil
 	}
 
+	if cluster.DeletionTimestamp != nil && controllerutil.ContainsFinalizer(cluster, fdbtypes.ClientConfigTargetFinalizer) {
+		return ctrl.Result{}, finalizeClientConfigTargets(ctx, r, cluster)
+	}
+
 	err = internal.NormalizeClusterSpec(cluster, r.DeprecationOptions)
 	if err != nil {
 		return ctrl.Result{}, err
@@ -118,6 +147,9 @@ func (r *FoundationDBClusterReconciler) Reconcile(ctx context.Context, request c
 		updateStatus{},
 		updateLockConfiguration{},
 		updateConfigMap{},
+		updateClientLibraries{},
+		updateClientConfigTargets{},
+		updateTLSCertificates{},
 		checkClientCompatibility{},
 		replaceMisconfiguredProcessGroups{},
 		replaceFailedProcessGroups{},
@@ -126,14 +158,18 @@ func (r *FoundationDBClusterReconciler) Reconcile(ctx context.Context, request c
 		addServices{},
 		addPVCs{},
 		addPods{},
+		updateNodeLabelAnnotations{},
+		updatePodDisruptionBudgets{},
 		generateInitialClusterFile{},
 		updateSidecarVersions{},
 		updatePodConfig{},
 		updateLabels{},
 		updateDatabaseConfiguration{},
+		updateRuntimeKnobs{},
 		chooseRemovals{},
 		excludeProcesses{},
 		changeCoordinators{},
+		updateClientConfigTargets{},
 		bounceProcesses{},
 		updatePods{},
 		removeServices{},
@@ -144,6 +180,8 @@ func (r *FoundationDBClusterReconciler) Reconcile(ctx context.Context, request c
 	originalGeneration := cluster.ObjectMeta.Generation
 	normalizedSpec := cluster.Spec.DeepCopy()
 	delayedRequeue := false
+	var delayedRequeueDuration time.Duration
+	var scheduledRequeueDuration time.Duration
 
 	for _, subReconciler := range subReconcilers {
 		// We have to set the normalized spec here again otherwise any call to Update() for the status of the cluster
@@ -156,10 +194,25 @@ func (r *FoundationDBClusterReconciler) Reconcile(ctx context.Context, request c
 			continue
 		}
 
+		if requeue.scheduled {
+			clusterLog.Info("Scheduling requeue for sub-reconciler",
+				"subReconciler", fmt.Sprintf("%T", subReconciler),
+				"message", requeue.message)
+			if scheduledRequeueDuration == 0 || requeue.delay < scheduledRequeueDuration {
+				scheduledRequeueDuration = requeue.delay
+			}
+			continue
+		}
+
+		r.recordReconciliationAttempt(ctx, cluster, subReconciler, requeue, clusterLog)
+
 		if requeue.delayedRequeue {
 			clusterLog.Info("Delaying requeue for sub-reconciler",
 				"subReconciler", fmt.Sprintf("%T", subReconciler),
 				"message", requeue.message)
+			if !delayedRequeue || requeue.delay < delayedRequeueDuration {
+				delayedRequeueDuration = requeue.delay
+			}
 			delayedRequeue = true
 			continue
 		}
@@ -170,13 +223,17 @@ func (r *FoundationDBClusterReconciler) Reconcile(ctx context.Context, request c
 	if cluster.Status.Generations.Reconciled < originalGeneration || delayedRequeue {
 		clusterLog.Info("Cluster was not fully reconciled by reconciliation process", "status", cluster.Status.Generations)
 
-		return ctrl.Result{Requeue: true}, nil
+		if scheduledRequeueDuration > 0 && scheduledRequeueDuration < delayedRequeueDuration {
+			delayedRequeueDuration = scheduledRequeueDuration
+		}
+
+		return ctrl.Result{Requeue: true, RequeueAfter: delayedRequeueDuration}, nil
 	}
 
 	clusterLog.Info("Reconciliation complete", "generation", cluster.Status.Generations.Reconciled)
 	r.Recorder.Event(cluster, corev1.EventTypeNormal, "ReconciliationComplete", fmt.Sprintf("Reconciled generation %d", cluster.Status.Generations.Reconciled))
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: scheduledRequeueDuration}, nil
 }
 
 // SetupWithManager prepares a reconciler for use.
@@ -232,8 +289,28 @@ func (r *FoundationDBClusterReconciler) SetupWithManager(mgr ctrl.Manager, maxCo
 	return builder.Complete(r)
 }
 
-func (r *FoundationDBClusterReconciler) updatePodDynamicConf(cluster *fdbtypes.FoundationDBCluster, pod *corev1.Pod) (bool, error) {
-	if cluster.ProcessGroupIsBeingRemoved(podmanager.GetProcessGroupID(cluster, pod)) {
+// getTLSCertificateData fetches the TLS certificate that the operator copies
+// into the pods. This returns nil if the cluster doesn't manage its TLS
+// certificates through the operator.
+func (r *FoundationDBClusterReconciler) getTLSCertificateData(ctx context.Context, cluster *fdbtypes.FoundationDBCluster) (map[string][]byte, error) {
+	if cluster.Spec.TLSCertificates == nil {
+		return nil, nil
+	}
+
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Spec.TLSCertificates.GetSecretName(cluster.Name)}, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return secret.Data, nil
+}
+
+// updatePodDynamicConf updates the dynamic configuration files in a pod. The
+// TLS certificate data must be fetched through getTLSCertificateData.
+func (r *FoundationDBClusterReconciler) updatePodDynamicConf(cluster *fdbtypes.FoundationDBCluster, pod *corev1.Pod, tlsCertificateData map[string][]byte) (bool, error) {
+	processGroupID := podmanager.GetProcessGroupID(cluster, pod)
+	if cluster.ProcessGroupIsBeingRemoved(processGroupID) {
 		return true, nil
 	}
 	podClient, message := r.getPodClient(cluster, pod)
@@ -242,25 +319,21 @@ func (r *FoundationDBClusterReconciler) updatePodDynamicConf(cluster *fdbtypes.F
 		return false, nil
 	}
 
-	serversPerPod := 1
-
 	processClass, err := podmanager.GetProcessClass(cluster, pod)
 	if err != nil {
 		return false, err
 	}
 
-	if processClass == fdbtypes.ProcessClassStorage {
-		serversPerPod, err = internal.GetStorageServersPerPodForPod(pod)
-		if err != nil {
-			return false, err
-		}
+	serversPerPod, err := internal.GetServersPerPodForPod(pod, processClass)
+	if err != nil {
+		return false, err
 	}
 
 	var expectedConf string
 
 	imageType := internal.GetImageType(pod)
 	if imageType == internal.FDBImageTypeUnified {
-		config, err := internal.GetMonitorProcessConfiguration(cluster, processClass, serversPerPod, imageType, nil)
+		config, err := internal.GetMonitorProcessConfiguration(cluster, processClass, processGroupID, serversPerPod, imageType, nil)
 		if err != nil {
 			return false, err
 		}
@@ -270,7 +343,17 @@ func (r *FoundationDBClusterReconciler) updatePodDynamicConf(cluster *fdbtypes.F
 		}
 		expectedConf = string(configData)
 	} else {
-		expectedConf, err = internal.GetMonitorConf(cluster, processClass, podClient, serversPerPod)
+		substitutions, err := podClient.GetVariableSubstitutions()
+		if err != nil {
+			return false, err
+		}
+
+		missing := internal.GetMissingSubstitutionVariables(cluster, substitutions)
+		if len(missing) > 0 {
+			return false, missingSubstitutionVariablesError{pod: pod, names: missing}
+		}
+
+		expectedConf, err = internal.GetMonitorConf(cluster, processClass, processGroupID, podClient, serversPerPod)
 		if err != nil {
 			return false, err
 		}
@@ -286,6 +369,15 @@ func (r *FoundationDBClusterReconciler) updatePodDynamicConf(cluster *fdbtypes.F
 		return false, err
 	}
 
+	if cluster.Spec.TLSCertificates != nil {
+		for _, file := range internal.TLSCertificateFiles {
+			synced, err := podClient.UpdateFile(file, string(tlsCertificateData[file]))
+			if !synced {
+				return false, err
+			}
+		}
+	}
+
 	version, err := fdbtypes.ParseFdbVersion(cluster.Spec.Version)
 	if err != nil {
 		return false, err
@@ -298,6 +390,62 @@ func (r *FoundationDBClusterReconciler) updatePodDynamicConf(cluster *fdbtypes.F
 	return true, nil
 }
 
+// missingSubstitutionVariablesError indicates that a pod has no values for
+// some substitution variables. This happens when the values come from
+// annotations that were set after the containers started, until the pod is
+// recreated by the updatePods reconciler.
+type missingSubstitutionVariablesError struct {
+	// pod is the pod that is missing the values.
+	pod *corev1.Pod
+
+	// names provides the names of the variables without a value.
+	names []string
+}
+
+// Error provides the error message.
+func (err missingSubstitutionVariablesError) Error() string {
+	return fmt.Sprintf("pod %s/%s is missing values for substitution variables: %s", err.pod.Namespace, err.pod.Name, strings.Join(err.names, ", "))
+}
+
+// recordReconciliationAttempt stores the outcome of a sub-reconciler that
+// stopped or delayed the reconciliation in the reconciliation history of the
+// cluster status. The status is written when the outcome differs from the
+// newest entry. Repeated outcomes update the newest entry, which is written
+// when the count reaches a power of two or when the stored entry is older
+// than reconciliationHistoryUpdateInterval, so a stuck reconciliation stays
+// visible without writing the status on every attempt.
+func (r *FoundationDBClusterReconciler) recordReconciliationAttempt(ctx context.Context, cluster *fdbtypes.FoundationDBCluster, subReconciler clusterSubReconciler, requeue *requeue, logger logr.Logger) {
+	entry := fdbtypes.ReconciliationHistoryEntry{
+		Timestamp:     metav1.Now(),
+		SubReconciler: fmt.Sprintf("%T", subReconciler),
+		Message:       requeue.message,
+		Generation:    cluster.ObjectMeta.Generation,
+		Delayed:       requeue.delayedRequeue,
+	}
+
+	if requeue.curError != nil {
+		entry.Error = requeue.curError.Error()
+	}
+
+	var lastWritten time.Time
+	if historyLength := len(cluster.Status.ReconciliationHistory); historyLength > 0 {
+		lastWritten = cluster.Status.ReconciliationHistory[historyLength-1].Timestamp.Time
+	}
+
+	if !cluster.Status.AddReconciliationHistoryEntry(entry, maxReconciliationHistoryEntries) {
+		latest := cluster.Status.ReconciliationHistory[len(cluster.Status.ReconciliationHistory)-1]
+		isPowerOfTwo := latest.Count&(latest.Count-1) == 0
+		if !isPowerOfTwo && entry.Timestamp.Sub(lastWritten) < reconciliationHistoryUpdateInterval {
+			return
+		}
+	}
+
+	err := r.Status().Update(ctx, cluster)
+	if err != nil {
+		logger.Error(err, "Error updating reconciliation history")
+	}
+}
+
 func (r *FoundationDBClusterReconciler) getPodClient(cluster *fdbtypes.FoundationDBCluster, pod *corev1.Pod) (podclient.FdbPodClient, string) {
 	if pod == nil {
 		return nil, fmt.Sprintf("Process group in cluster %s/%s does not have pod defined", cluster.Namespace, cluster.Name)
@@ -320,6 +468,15 @@ func (r *FoundationDBClusterReconciler) getDatabaseClientProvider() DatabaseClie
 	panic("Cluster reconciler does not have a DatabaseClientProvider defined")
 }
 
+// now returns the current time from the reconciler's clock.
+func (r *FoundationDBClusterReconciler) now() time.Time {
+	if r.clock != nil {
+		return r.clock()
+	}
+
+	return time.Now()
+}
+
 func (r *FoundationDBClusterReconciler) getLockClient(cluster *fdbtypes.FoundationDBCluster) (fdbadminclient.LockClient, error) {
 	return r.getDatabaseClientProvider().GetLockClient(cluster)
 }
@@ -446,15 +603,45 @@ func localityInfoFromSidecar(cluster *fdbtypes.FoundationDBCluster, client podcl
 	// So it should be good to only use the first process address here.
 	// This has the implication that in the initial cluster file only the first processes will be used.
 	address := cluster.GetFullAddress(substitutions["FDB_PUBLIC_IP"], 1)
+	if cluster.UseDNSInClusterFile() {
+		address = getDNSAddress(address, substitutions["FDB_DNS_NAME"])
+	}
+
 	return localityInfo{
 		ID:      substitutions["FDB_INSTANCE_ID"],
 		Address: address,
 		LocalityData: map[string]string{
-			fdbtypes.FDBLocalityZoneIDKey: substitutions["FDB_ZONE_ID"],
+			fdbtypes.FDBLocalityZoneIDKey:   substitutions["FDB_ZONE_ID"],
+			fdbtypes.FDBLocalityDataHallKey: getDataHall(cluster, substitutions),
 		},
 	}, nil
 }
 
+// getDNSAddress replaces the IP address of a process address with the DNS
+// name of the process. If the DNS name is empty the address is returned
+// unchanged.
+func getDNSAddress(address fdbtypes.ProcessAddress, dnsName string) fdbtypes.ProcessAddress {
+	if dnsName == "" {
+		return address
+	}
+
+	return fdbtypes.ProcessAddress{
+		StringAddress: dnsName,
+		Port:          address.Port,
+		Flags:         address.Flags,
+	}
+}
+
+// getDataHall returns the data hall of a process, reading it from the
+// sidecar's substitutions if the data hall references a variable.
+func getDataHall(cluster *fdbtypes.FoundationDBCluster, substitutions map[string]string) string {
+	if strings.HasPrefix(cluster.Spec.DataHall, "$") {
+		return substitutions[cluster.Spec.DataHall[1:]]
+	}
+
+	return cluster.Spec.DataHall
+}
+
 // notEnoughProcessesError is returned when we cannot recruit enough processes.
 type notEnoughProcessesError struct {
 	// desired defines the number of processes we wanted to recruit.
@@ -571,7 +758,21 @@ func chooseDistributedProcesses(cluster *fdbtypes.FoundationDBCluster, processes
 	return chosen, nil
 }
 
+// getCoordinatorFields returns the locality fields that the coordinators
+// should be spread across.
+func getCoordinatorFields(cluster *fdbtypes.FoundationDBCluster) []string {
+	if cluster.Spec.DatabaseConfiguration.RedundancyMode == fdbtypes.RedundancyModeThreeDataHall {
+		return []string{fdbtypes.FDBLocalityZoneIDKey, fdbtypes.FDBLocalityDataHallKey}
+	}
+
+	return nil
+}
+
 func getHardLimits(cluster *fdbtypes.FoundationDBCluster) map[string]int {
+	if cluster.Spec.DatabaseConfiguration.RedundancyMode == fdbtypes.RedundancyModeThreeDataHall {
+		return map[string]int{fdbtypes.FDBLocalityZoneIDKey: 1, fdbtypes.FDBLocalityDataHallKey: maxCoordinatorsPerDataHall}
+	}
+
 	if cluster.Spec.DatabaseConfiguration.UsableRegions <= 1 {
 		return map[string]int{fdbtypes.FDBLocalityZoneIDKey: 1}
 	}
@@ -599,9 +800,11 @@ func checkCoordinatorValidity(cluster *fdbtypes.FoundationDBCluster, status *fdb
 
 	allAddressesValid := true
 	allEligible := true
+	allUsingDesiredAddressType := true
 
 	coordinatorZones := make(map[string]int, len(coordinatorStatus))
 	coordinatorDCs := make(map[string]int, len(coordinatorStatus))
+	coordinatorDataHalls := make(map[string]int, len(coordinatorStatus))
 	processGroups := make(map[string]*fdbtypes.ProcessGroupStatus)
 	for _, processGroup := range cluster.Status.ProcessGroups {
 		processGroups[processGroup.ProcessGroupID] = processGroup
@@ -620,7 +823,7 @@ func checkCoordinatorValidity(cluster *fdbtypes.FoundationDBCluster, status *fdb
 		}
 
 		processGroupStatus := processGroups[process.Locality["instance_id"]]
-		pendingRemoval := processGroupStatus != nil && processGroupStatus.Remove
+		shouldBeMoved := coordinatorShouldBeMoved(cluster, processGroupStatus)
 		if processGroupStatus != nil && cluster.SkipProcessGroup(processGroupStatus) {
 			log.Info("Skipping process group with pending Pod",
 				"namespace", cluster.Namespace,
@@ -641,21 +844,48 @@ func checkCoordinatorValidity(cluster *fdbtypes.FoundationDBCluster, status *fdb
 		}
 
 		var address string
+		var dnsAddress string
 		for _, addr := range addresses {
 			if addr.Flags["tls"] == cluster.Spec.MainContainer.EnableTLS {
 				address = addr.String()
+				if process.Locality[fdbtypes.FDBLocalityDNSNameKey] != "" {
+					dnsAddress = getDNSAddress(addr, process.Locality[fdbtypes.FDBLocalityDNSNameKey]).String()
+				}
 				break
 			}
 		}
 
-		_, isCoordinator := coordinatorStatus[address]
-		if isCoordinator && !process.Excluded && !pendingRemoval {
-			coordinatorStatus[address] = true
+		coordinatorAddress := ""
+		_, isCoordinatorWithIP := coordinatorStatus[address]
+		if isCoordinatorWithIP {
+			coordinatorAddress = address
+		}
+
+		_, isCoordinatorWithDNS := coordinatorStatus[dnsAddress]
+		if dnsAddress != "" && isCoordinatorWithDNS {
+			coordinatorAddress = dnsAddress
+		}
+
+		isCoordinator := coordinatorAddress != ""
+		if isCoordinator && !process.Excluded && !shouldBeMoved {
+			coordinatorStatus[coordinatorAddress] = true
+		}
+
+		if isCoordinator && shouldBeMoved {
+			pLogger.Info("Coordinator should be moved to another process", "address", coordinatorAddress)
 		}
 
 		if isCoordinator {
+			// If the process has a DNS name the coordinator must use the
+			// address type that is desired for the cluster file.
+			if dnsAddress != "" && isCoordinatorWithDNS != cluster.UseDNSInClusterFile() {
+				pLogger.Info("Coordinator is not using the desired address type", "address", coordinatorAddress, "useDNSInClusterFile", cluster.UseDNSInClusterFile())
+				allUsingDesiredAddressType = false
+			}
+
 			coordinatorZones[process.Locality[fdbtypes.FDBLocalityZoneIDKey]]++
 			coordinatorDCs[process.Locality[fdbtypes.FDBLocalityDCIDKey]]++
+			coordinatorDataHalls[process.Locality[fdbtypes.FDBLocalityDataHallKey]]++
 
 			if !cluster.IsEligibleAsCandidate(process.ProcessClass) {
 				pLogger.Info("Process class of process is not eligible as coordinator", "class", process.ProcessClass, "address", address)
@@ -688,6 +918,21 @@ func checkCoordinatorValidity(cluster *fdbtypes.FoundationDBCluster, status *fdb
 		}
 	}
 
+	hasEnoughDataHalls := true
+	if cluster.Spec.DatabaseConfiguration.RedundancyMode == fdbtypes.RedundancyModeThreeDataHall {
+		if len(coordinatorDataHalls) < minimumDataHalls {
+			curLog.Info("Cluster does not have coordinators in enough data halls", "coordinatorDataHalls", coordinatorDataHalls, "min", minimumDataHalls)
+			hasEnoughDataHalls = false
+		}
+
+		for dataHall, count := range coordinatorDataHalls {
+			if count > maxCoordinatorsPerDataHall {
+				curLog.Info("Cluster has too many coordinators in a single data hall", "dataHall", dataHall, "count", count, "max", maxCoordinatorsPerDataHall)
+				hasEnoughDataHalls = false
+			}
+		}
+	}
+
 	allHealthy := true
 	for address, healthy := range coordinatorStatus {
 		allHealthy = allHealthy && healthy
@@ -697,7 +942,33 @@ func checkCoordinatorValidity(cluster *fdbtypes.FoundationDBCluster, status *fdb
 		}
 	}
 
-	return hasEnoughDCs && hasEnoughZones && allHealthy && allEligible, allAddressesValid, nil
+	return hasEnoughDCs && hasEnoughZones && hasEnoughDataHalls && allHealthy && allEligible && allUsingDesiredAddressType, allAddressesValid, nil
+}
+
+// coordinatorShouldBeMoved determines whether a coordinator should be moved
+// off the process group before the process group becomes unavailable. This is
+// the case if the process group is marked for removal, the pod is pending or
+// running on an unschedulable node, or the coordinator has been unreachable
+// for longer than the configured duration.
+func coordinatorShouldBeMoved(cluster *fdbtypes.FoundationDBCluster, processGroup *fdbtypes.ProcessGroupStatus) bool {
+	if processGroup == nil {
+		return false
+	}
+
+	if processGroup.Remove {
+		return true
+	}
+
+	if processGroup.GetConditionTime(fdbtypes.PodPending) != nil || processGroup.GetConditionTime(fdbtypes.NodeUnschedulable) != nil {
+		return true
+	}
+
+	unreachableTime := processGroup.GetConditionTime(fdbtypes.CoordinatorUnreachable)
+	if unreachableTime == nil {
+		return false
+	}
+
+	return time.Unix(*unreachableTime, 0).Add(cluster.GetCoordinatorUnreachableDuration()).Before(time.Now())
 }
 
 // newFdbPodClient builds a client for working with an FDB Pod
@@ -1182,6 +1182,25 @@ var _ = Describe("cluster_controller", func() {
 					Expect(adminClient.KilledAddresses).To(BeNil())
 				})
 
+				It("should record the reconciliation history", func() {
+					history := cluster.Status.ReconciliationHistory
+					Expect(history).NotTo(BeEmpty())
+					latest := history[len(history)-1]
+					Expect(latest.SubReconciler).To(Equal("controllers.bounceProcesses"))
+					Expect(latest.Message).To(Equal("Kills are disabled"))
+					Expect(latest.Generation).To(Equal(originalVersion + 1))
+					Expect(latest.Count).To(BeNumerically(">", 1))
+
+					// Repeated attempts with the same outcome share one entry.
+					matching := 0
+					for _, entry := range history {
+						if entry.SubReconciler == latest.SubReconciler && entry.Generation == latest.Generation {
+							matching++
+						}
+					}
+					Expect(matching).To(Equal(1))
+				})
+
 				It("should update the config map", func() {
 					configMap := &corev1.ConfigMap{}
 					configMapName := types.NamespacedName{Namespace: "my-ns", Name: fmt.Sprintf("%s-config", cluster.Name)}
@@ -1238,6 +1257,88 @@ var _ = Describe("cluster_controller", func() {
 			})
 		})
 
+		Context("with a parameter override for a process group", func() {
+			BeforeEach(func() {
+				cluster.Spec.ProcessGroupParameterOverrides = map[string]fdbtypes.ProcessGroupParameterOverride{
+					"storage-1": {CustomParameters: fdbtypes.FoundationDBCustomParameters{"knob_trace_level=1"}},
+				}
+				err = k8sClient.Update(context.TODO(), cluster)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should report the override in the status", func() {
+				Expect(cluster.Status.ProcessGroupParameterOverrides).To(Equal(cluster.Spec.ProcessGroupParameterOverrides))
+			})
+
+			It("should update the config map", func() {
+				configMap := &corev1.ConfigMap{}
+				configMapName := types.NamespacedName{Namespace: "my-ns", Name: fmt.Sprintf("%s-config", cluster.Name)}
+				err = k8sClient.Get(context.TODO(), configMapName, configMap)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(configMap.Data).To(HaveKey("fdbmonitor-conf-storage-process-group-storage-1"))
+				Expect(configMap.Data["fdbmonitor-conf-storage-process-group-storage-1"]).To(ContainSubstring("knob_trace_level = 1"))
+			})
+
+			It("should only apply the override to the process group", func() {
+				adminClient, err := newMockAdminClientUncast(cluster, k8sClient)
+				Expect(err).NotTo(HaveOccurred())
+				status, err := adminClient.GetStatus()
+				Expect(err).NotTo(HaveOccurred())
+
+				for _, process := range status.Cluster.Processes {
+					if process.Locality["instance_id"] == "storage-1" {
+						Expect(process.CommandLine).To(ContainSubstring("--knob_trace_level=1"))
+					} else {
+						Expect(process.CommandLine).NotTo(ContainSubstring("--knob_trace_level"))
+					}
+				}
+			})
+
+			When("the override expires", func() {
+				var overridePodUID types.UID
+
+				BeforeEach(func() {
+					_, err = reconcileCluster(cluster)
+					Expect(err).NotTo(HaveOccurred())
+					_, err = reloadCluster(cluster)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(cluster.Status.ProcessGroupParameterOverrides).To(HaveKey("storage-1"))
+
+					pods, err := clusterReconciler.PodLifecycleManager.GetPods(context.TODO(), clusterReconciler, cluster, internal.GetSinglePodListOptions(cluster, "storage-1")...)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(pods).To(HaveLen(1))
+					overridePodUID = pods[0].UID
+
+					override := cluster.Spec.ProcessGroupParameterOverrides["storage-1"]
+					override.ExpirationTime = &metav1.Time{Time: time.Now().Add(-1 * time.Minute)}
+					cluster.Spec.ProcessGroupParameterOverrides["storage-1"] = override
+					err = k8sClient.Update(context.TODO(), cluster)
+					Expect(err).NotTo(HaveOccurred())
+					generationGap = 2
+				})
+
+				It("should remove the override from the status", func() {
+					Expect(cluster.Status.ProcessGroupParameterOverrides).To(BeNil())
+				})
+
+				It("should remove the override from the config map", func() {
+					configMap := &corev1.ConfigMap{}
+					configMapName := types.NamespacedName{Namespace: "my-ns", Name: fmt.Sprintf("%s-config", cluster.Name)}
+					err = k8sClient.Get(context.TODO(), configMapName, configMap)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(configMap.Data).To(HaveKey("fdbmonitor-conf-storage-process-group-storage-1"))
+					Expect(configMap.Data["fdbmonitor-conf-storage-process-group-storage-1"]).To(Equal(configMap.Data["fdbmonitor-conf-storage"]))
+				})
+
+				It("should not recreate the pod for the process group", func() {
+					pods, err := clusterReconciler.PodLifecycleManager.GetPods(context.TODO(), clusterReconciler, cluster, internal.GetSinglePodListOptions(cluster, "storage-1")...)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(pods).To(HaveLen(1))
+					Expect(pods[0].UID).To(Equal(overridePodUID))
+				})
+			})
+		})
+
 		Context("with a configuration change", func() {
 			var adminClient *mockAdminClient
 			BeforeEach(func() {
@@ -1993,6 +2094,54 @@ var _ = Describe("cluster_controller", func() {
 			})
 		})
 
+		Context("with a change to the log servers per Pod", func() {
+			BeforeEach(func() {
+				cluster.Spec.LogServersPerPod = 2
+				err = k8sClient.Update(context.TODO(), cluster)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should replace the log pods", func() {
+				pods := &corev1.PodList{}
+				err = k8sClient.List(context.TODO(), pods, getListOptions(cluster)...)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pods.Items).To(HaveLen(len(originalPods.Items)))
+
+				currentNames := make([]string, 0, len(pods.Items))
+				for _, pod := range pods.Items {
+					currentNames = append(currentNames, pod.Name)
+				}
+
+				for _, pod := range originalPods.Items {
+					if internal.ProcessClassFromLabels(cluster, pod.Labels) == fdbtypes.ProcessClassLog {
+						Expect(currentNames).NotTo(ContainElement(pod.Name))
+					} else {
+						Expect(currentNames).To(ContainElement(pod.Name))
+					}
+				}
+			})
+
+			It("should run two log processes in each log pod", func() {
+				adminClient, err := newMockAdminClientUncast(cluster, k8sClient)
+				Expect(err).NotTo(HaveOccurred())
+				status, err := adminClient.GetStatus()
+				Expect(err).NotTo(HaveOccurred())
+
+				commandLines := make([]string, 0)
+				for _, process := range status.Cluster.Processes {
+					if process.ProcessClass == fdbtypes.ProcessClassLog {
+						commandLines = append(commandLines, process.CommandLine)
+					}
+				}
+				Expect(commandLines).To(HaveLen(4 * 2))
+				Expect(commandLines).To(ContainElement(ContainSubstring("--locality_process_id=log-5-2")))
+			})
+
+			It("should update the log servers per disk in the status", func() {
+				Expect(cluster.Status.LogServersPerDisk).To(Equal([]int{2}))
+			})
+		})
+
 		Context("with a change to TLS settings", func() {
 			BeforeEach(func() {
 				cluster.Spec.MainContainer.EnableTLS = true
@@ -2024,6 +2173,11 @@ var _ = Describe("cluster_controller", func() {
 					Expect(coordinator).To(HaveSuffix("tls"))
 				}
 			})
+
+			It("should complete the TLS migration", func() {
+				Expect(cluster.Status.TLSMigration).To(BeNil())
+				Expect(cluster.Status.RequiredAddresses).To(Equal(fdbtypes.RequiredAddressSet{TLS: true}))
+			})
 		})
 
 		Context("with a conversion to IPv6", func() {
@@ -2142,7 +2296,7 @@ var _ = Describe("cluster_controller", func() {
 			BeforeEach(func() {
 				shouldCompleteReconciliation = false
 				IncompatibleVersion := fdbtypes.Versions.Default
-				IncompatibleVersion.Patch--
+				IncompatibleVersion.Minor--
 				cluster.Spec.Version = IncompatibleVersion.String()
 				err := k8sClient.Update(context.TODO(), cluster)
 				Expect(err).NotTo(HaveOccurred())
@@ -2154,6 +2308,35 @@ var _ = Describe("cluster_controller", func() {
 			})
 		})
 
+		Context("rollback to a previous patch version", func() {
+			var adminClient *mockAdminClient
+			var previousVersion fdbtypes.FdbVersion
+
+			BeforeEach(func() {
+				previousVersion = fdbtypes.Versions.Default
+				previousVersion.Patch--
+				cluster.Spec.Version = previousVersion.String()
+
+				adminClient, err = newMockAdminClientUncast(cluster, k8sClient)
+				Expect(err).NotTo(HaveOccurred())
+
+				err := k8sClient.Update(context.TODO(), cluster)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should bounce the processes", func() {
+				Expect(adminClient.KilledAddresses).To(HaveLen(len(originalPods.Items)))
+			})
+
+			It("should update the running version", func() {
+				Expect(cluster.Status.RunningVersion).To(Equal(previousVersion.String()))
+			})
+
+			It("should record the previous version", func() {
+				Expect(cluster.Status.PreviousVersion).To(Equal(fdbtypes.Versions.Default.String()))
+			})
+		})
+
 		Context("with an upgrade", func() {
 			var adminClient *mockAdminClient
 
@@ -2641,7 +2824,7 @@ var _ = Describe("cluster_controller", func() {
 
 		Context("with a basic storage process group", func() {
 			BeforeEach(func() {
-				conf, err = internal.GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+				conf, err = internal.GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, "", nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -2669,7 +2852,7 @@ var _ = Describe("cluster_controller", func() {
 		Context("with a basic storage process group with multiple storage servers per Pod", func() {
 			BeforeEach(func() {
 				cluster.Spec.StorageServersPerPod = 2
-				conf, err = internal.GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+				conf, err = internal.GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, "", nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -2712,7 +2895,7 @@ var _ = Describe("cluster_controller", func() {
 			BeforeEach(func() {
 				source := fdbtypes.PublicIPSourcePod
 				cluster.Spec.Routing.PublicIPSource = &source
-				conf, err = internal.GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, nil, 1)
+				conf, err = internal.GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, "", nil, 1)
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -2742,7 +2925,7 @@ var _ = Describe("cluster_controller", func() {
 				source := fdbtypes.PublicIPSourceService
 				cluster.Spec.Routing.PublicIPSource = &source
 				cluster.Status.HasListenIPsForAllPods = true
-				conf, err = internal.GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, nil, 1)
+				conf, err = internal.GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, "", nil, 1)
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -2770,7 +2953,7 @@ var _ = Describe("cluster_controller", func() {
 			Context("with pods without the listen IP environment variable", func() {
 				BeforeEach(func() {
 					cluster.Status.HasListenIPsForAllPods = false
-					conf, err = internal.GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, nil, 1)
+					conf, err = internal.GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, "", nil, 1)
 					Expect(err).NotTo(HaveOccurred())
 				})
 
@@ -2801,7 +2984,7 @@ var _ = Describe("cluster_controller", func() {
 				cluster.Spec.MainContainer.EnableTLS = true
 				cluster.Status.RequiredAddresses.NonTLS = false
 				cluster.Status.RequiredAddresses.TLS = true
-				conf, err = internal.GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+				conf, err = internal.GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, "", nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -2832,7 +3015,7 @@ var _ = Describe("cluster_controller", func() {
 				cluster.Status.RequiredAddresses.NonTLS = true
 				cluster.Status.RequiredAddresses.TLS = true
 
-				conf, err = internal.GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+				conf, err = internal.GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, "", nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -2863,7 +3046,7 @@ var _ = Describe("cluster_controller", func() {
 				cluster.Status.RequiredAddresses.NonTLS = true
 				cluster.Status.RequiredAddresses.TLS = true
 
-				conf, err = internal.GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+				conf, err = internal.GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, "", nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -2894,7 +3077,7 @@ var _ = Describe("cluster_controller", func() {
 					cluster.Spec.Processes = map[fdbtypes.ProcessClass]fdbtypes.ProcessSettings{fdbtypes.ProcessClassGeneral: {CustomParameters: fdbtypes.FoundationDBCustomParameters{
 						"knob_disable_posix_kernel_aio = 1",
 					}}}
-					conf, err = internal.GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+					conf, err = internal.GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, "", nil, cluster.GetStorageServersPerPod())
 					Expect(err).NotTo(HaveOccurred())
 				})
 
@@ -2933,7 +3116,7 @@ var _ = Describe("cluster_controller", func() {
 							"knob_test = test2",
 						}},
 					}
-					conf, err = internal.GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+					conf, err = internal.GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, "", nil, cluster.GetStorageServersPerPod())
 					Expect(err).NotTo(HaveOccurred())
 				})
 
@@ -2966,7 +3149,7 @@ var _ = Describe("cluster_controller", func() {
 					Key:       "rack",
 					ValueFrom: "$RACK",
 				}
-				conf, err = internal.GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+				conf, err = internal.GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, "", nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -2995,7 +3178,7 @@ var _ = Describe("cluster_controller", func() {
 			BeforeEach(func() {
 				cluster.Spec.Version = fdbtypes.Versions.WithBinariesFromMainContainer.String()
 				cluster.Status.RunningVersion = fdbtypes.Versions.WithBinariesFromMainContainer.String()
-				conf, err = internal.GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+				conf, err = internal.GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, "", nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 
 			})
@@ -3025,7 +3208,7 @@ var _ = Describe("cluster_controller", func() {
 			BeforeEach(func() {
 				cluster.Spec.Version = fdbtypes.Versions.WithoutBinariesFromMainContainer.String()
 				cluster.Status.RunningVersion = fdbtypes.Versions.WithoutBinariesFromMainContainer.String()
-				conf, err = internal.GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+				conf, err = internal.GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, "", nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -3053,7 +3236,7 @@ var _ = Describe("cluster_controller", func() {
 		Context("with peer verification rules", func() {
 			BeforeEach(func() {
 				cluster.Spec.MainContainer.PeerVerificationRules = "S.CN=foundationdb.org"
-				conf, err = internal.GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+				conf, err = internal.GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, "", nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -3082,7 +3265,7 @@ var _ = Describe("cluster_controller", func() {
 		Context("with a custom log group", func() {
 			BeforeEach(func() {
 				cluster.Spec.LogGroup = "test-fdb-cluster"
-				conf, err = internal.GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+				conf, err = internal.GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, "", nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -3110,7 +3293,7 @@ var _ = Describe("cluster_controller", func() {
 		Context("with a data center", func() {
 			BeforeEach(func() {
 				cluster.Spec.DataCenter = "dc01"
-				conf, err = internal.GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, nil, cluster.GetStorageServersPerPod())
+				conf, err = internal.GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, "", nil, cluster.GetStorageServersPerPod())
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -3835,12 +4018,12 @@ func getConfigMapHash(cluster *fdbtypes.FoundationDBCluster, pClass fdbtypes.Pro
 		return "", err
 	}
 
-	serversPerPod, err := internal.GetStorageServersPerPodForPod(pod)
+	serversPerPod, err := internal.GetServersPerPodForPod(pod, pClass)
 	if err != nil {
 		return "", err
 	}
 
 	imageType := internal.GetImageType(pod)
 
-	return internal.GetDynamicConfHash(configMap, pClass, imageType, serversPerPod)
+	return internal.GetDynamicConfHash(configMap, pClass, internal.GetProcessGroupIDFromMeta(cluster, pod.ObjectMeta), imageType, serversPerPod)
 }
@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"time"
 
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
@@ -40,6 +41,39 @@ const (
 	// podSchedulingDelayDuration determines how long we should delay a requeue
 	// of reconciliation when a pod is not ready.
 	podSchedulingDelayDuration = 15 * time.Second
+
+	// maxReconciliationHistoryEntries determines how many reconciliation
+	// attempts we keep in the cluster status.
+	maxReconciliationHistoryEntries = 10
+
+	// reconciliationHistoryUpdateInterval determines how often we write a
+	// repeated reconciliation outcome to the cluster status.
+	reconciliationHistoryUpdateInterval = 1 * time.Minute
+
+	// minimumRequeueDelay determines the shortest delay for a requeue that
+	// waits for a point in time.
+	minimumRequeueDelay = 1 * time.Second
+
+	// indefiniteFreezeRequeueDelay determines how long we should delay a
+	// requeue when an operation is frozen without an expiration time.
+	indefiniteFreezeRequeueDelay = 5 * time.Minute
+
+	// closedMaintenanceWindowRequeueDelay determines how long we should delay
+	// a requeue when the cluster is outside of its maintenance windows and
+	// none of them will open in the foreseeable future.
+	closedMaintenanceWindowRequeueDelay = 1 * time.Hour
+
+	// maxFailOverDataCenterLag determines how far the new primary data center
+	// can lag behind the current primary before we fail over to it.
+	maxFailOverDataCenterLag = 5 * time.Second
+
+	// minimumDataHalls determines how many data halls the coordinators must
+	// be spread across in the three_data_hall redundancy mode.
+	minimumDataHalls = 3
+
+	// maxCoordinatorsPerDataHall determines how many coordinators we can
+	// place in a single data hall in the three_data_hall redundancy mode.
+	maxCoordinatorsPerDataHall = 3
 )
 
 // metadataMatches determines if the current metadata on an object matches the
@@ -91,6 +125,11 @@ type requeue struct {
 
 	// delayedRequeue defines that the reconciliation was not completed but the requeue should be delayed to the end.
 	delayedRequeue bool
+
+	// scheduled defines that the reconciliation was completed, but has to run
+	// again after the delay because the desired state changes at that point in
+	// time. These requeues are not recorded as reconciliation attempts.
+	scheduled bool
 }
 
 // processRequeue interprets a requeue result from a subreconciler.
@@ -118,3 +157,71 @@ func processRequeue(requeue *requeue, subReconciler interface{}, object runtime.
 
 	return ctrl.Result{Requeue: true, RequeueAfter: requeue.delay}, nil
 }
+
+// checkAutomationFreeze checks if an operation is frozen. If the operation is
+// frozen this will emit an event and return a delayed requeue that expires
+// together with the freeze. Otherwise this will return nil.
+func checkAutomationFreeze(cluster *fdbtypes.FoundationDBCluster, recorder record.EventRecorder, operation fdbtypes.AutomationOperation) *requeue {
+	now := time.Now()
+	freeze := cluster.GetActiveFreeze(operation, now)
+	if freeze == nil {
+		return nil
+	}
+
+	message := fmt.Sprintf("%s are frozen", operation)
+	delay := indefiniteFreezeRequeueDelay
+	if freeze.Until != nil {
+		message = fmt.Sprintf("%s until %s", message, freeze.Until.UTC().Format(time.RFC3339))
+		delay = delayUntil(now, freeze.Until.Time)
+	}
+
+	if freeze.Reason != "" {
+		message = fmt.Sprintf("%s: %s", message, freeze.Reason)
+	}
+
+	recorder.Event(cluster, corev1.EventTypeNormal, "AutomationFrozen", message)
+
+	return &requeue{message: message, delay: delay, delayedRequeue: true}
+}
+
+// delayUntil returns the delay for a requeue at the given point in time. The
+// delay is at least minimumRequeueDelay, since a point in time that already
+// passed would result in a zero or negative delay.
+func delayUntil(now time.Time, target time.Time) time.Duration {
+	delay := target.Sub(now)
+	if delay < minimumRequeueDelay {
+		return minimumRequeueDelay
+	}
+
+	return delay
+}
+
+// checkMaintenanceWindow checks if disruptive operations are allowed right
+// now. If the cluster is outside of its maintenance windows this will return a
+// delayed requeue that expires when the next window opens. Otherwise this will
+// return nil.
+func checkMaintenanceWindow(cluster *fdbtypes.FoundationDBCluster, action string) *requeue {
+	now := time.Now()
+	open, nextStart, err := cluster.IsInMaintenanceWindow(now)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	if open {
+		return nil
+	}
+
+	if nextStart.IsZero() {
+		return &requeue{
+			message:        fmt.Sprintf("Deferring %s, no maintenance window is scheduled", action),
+			delay:          closedMaintenanceWindowRequeueDelay,
+			delayedRequeue: true,
+		}
+	}
+
+	return &requeue{
+		message:        fmt.Sprintf("Deferring %s until the next maintenance window at %s", action, nextStart.UTC().Format(time.RFC3339)),
+		delay:          delayUntil(now, nextStart),
+		delayedRequeue: true,
+	}
+}
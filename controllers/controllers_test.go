@@ -0,0 +1,50 @@
+/*
+ * controllers_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2021 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("controllers", func() {
+	When("calculating the delay until a point in time", func() {
+		now := time.Now()
+
+		DescribeTable("should return the delay",
+			func(target time.Time, expected time.Duration) {
+				Expect(delayUntil(now, target)).To(Equal(expected))
+			},
+			Entry("with a point in the future",
+				now.Add(5*time.Minute),
+				5*time.Minute),
+			Entry("with the current time",
+				now,
+				minimumRequeueDelay),
+			Entry("with a point in the past",
+				now.Add(-5*time.Minute),
+				minimumRequeueDelay),
+		)
+	})
+})
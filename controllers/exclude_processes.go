@@ -79,6 +79,11 @@ func (e excludeProcesses) reconcile(_ context.Context, r *FoundationDBClusterRec
 	}
 
 	if len(addresses) > 0 {
+		freezeRequeue := checkAutomationFreeze(cluster, r.Recorder, fdbtypes.AutomationOperationExclusions)
+		if freezeRequeue != nil {
+			return freezeRequeue
+		}
+
 		for processClass := range processClassesToExclude {
 			canExclude, missingProcesses := canExcludeNewProcesses(cluster, processClass)
 			if !canExclude {
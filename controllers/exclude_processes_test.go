@@ -22,6 +22,9 @@ package controllers
 
 import (
 	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
 
@@ -134,6 +137,68 @@ var _ = Describe("exclude_processes", func() {
 			})
 		})
 	})
+
+	Describe("reconcile", func() {
+		var adminClient *mockAdminClient
+		var result *requeue
+
+		BeforeEach(func() {
+			adminClient, err = newMockAdminClientUncast(cluster, k8sClient)
+			Expect(err).NotTo(HaveOccurred())
+
+			processGroup := fdbtypes.FindProcessGroupByID(cluster.Status.ProcessGroups, "storage-1")
+			Expect(processGroup).NotTo(BeNil())
+			processGroup.MarkForRemoval()
+		})
+
+		JustBeforeEach(func() {
+			result = excludeProcesses{}.reconcile(context.TODO(), clusterReconciler, cluster)
+		})
+
+		When("exclusions are not frozen", func() {
+			It("should exclude the process group", func() {
+				Expect(result).To(BeNil())
+				Expect(adminClient.ExcludedAddresses).To(HaveLen(1))
+			})
+		})
+
+		When("exclusions are frozen", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.Freezes = []fdbtypes.AutomationFreeze{
+					{
+						Operation: fdbtypes.AutomationOperationExclusions,
+						Until:     &metav1.Time{Time: time.Now().Add(1 * time.Hour)},
+						Reason:    "investigating an incident",
+					},
+				}
+			})
+
+			It("should not exclude the process group", func() {
+				Expect(result).NotTo(BeNil())
+				Expect(result.delayedRequeue).To(BeTrue())
+				Expect(result.delay).To(BeNumerically(">", 59*time.Minute))
+				Expect(result.message).To(HavePrefix("Exclusions are frozen until"))
+				Expect(result.message).To(HaveSuffix("investigating an incident"))
+				Expect(adminClient.ExcludedAddresses).To(BeEmpty())
+			})
+		})
+
+		When("the freeze on exclusions has expired", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.Freezes = []fdbtypes.AutomationFreeze{
+					{
+						Operation: fdbtypes.AutomationOperationExclusions,
+						Until:     &metav1.Time{Time: time.Now().Add(-1 * time.Hour)},
+					},
+				}
+			})
+
+			It("should exclude the process group", func() {
+				Expect(result).To(BeNil())
+				Expect(adminClient.ExcludedAddresses).To(HaveLen(1))
+			})
+		})
+	})
 })
 
 func createMissingProcesses(cluster *fdbtypes.FoundationDBCluster, count int, processClass fdbtypes.ProcessClass) {
@@ -44,7 +44,12 @@ func (g generateInitialClusterFile) reconcile(ctx context.Context, r *Foundation
 
 	logger.Info("Generating initial cluster file")
 	r.Recorder.Event(cluster, corev1.EventTypeNormal, "ChangingCoordinators", "Choosing initial coordinators")
-	pods, err := r.PodLifecycleManager.GetPods(ctx, r, cluster, internal.GetPodListOptions(cluster, fdbtypes.ProcessClassStorage, "")...)
+	coordinatorClass := fdbtypes.ProcessClassStorage
+	if cluster.UseDedicatedCoordinators() {
+		coordinatorClass = fdbtypes.ProcessClassCoordinator
+	}
+
+	pods, err := r.PodLifecycleManager.GetPods(ctx, r, cluster, internal.GetPodListOptions(cluster, coordinatorClass, "")...)
 	if err != nil {
 		return &requeue{curError: err}
 	}
@@ -92,7 +97,9 @@ func (g generateInitialClusterFile) reconcile(ctx context.Context, r *Foundation
 		processLocality = append(processLocality, locality)
 	}
 
-	coordinators, err := chooseDistributedProcesses(cluster, processLocality, count, processSelectionConstraint{})
+	coordinators, err := chooseDistributedProcesses(cluster, processLocality, count, processSelectionConstraint{
+		Fields: getCoordinatorFields(cluster),
+	})
 	if err != nil {
 		return &requeue{curError: err}
 	}
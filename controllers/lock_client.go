@@ -40,10 +40,24 @@ type mockLockClient struct {
 	// pendingUpgrades stores data about process groups that have a pending
 	// upgrade.
 	pendingUpgrades map[fdbtypes.FdbVersion]map[string]bool
+
+	// operatorInstances stores the registered instances of the operator.
+	operatorInstances map[string]fdbtypes.OperatorInstance
 }
 
-// TakeLock attempts to acquire a lock.
+// TakeLock attempts to acquire a lock. Like the real lock client this will
+// refuse the lock if the cluster's lock ID is on the deny list.
 func (client *mockLockClient) TakeLock() (bool, error) {
+	if client.Disabled() {
+		return true, nil
+	}
+
+	for _, id := range client.denyList {
+		if id == client.cluster.GetLockID() {
+			return false, nil
+		}
+	}
+
 	return true, nil
 }
 
@@ -107,6 +121,27 @@ func (client *mockLockClient) UpdateDenyList(locks []fdbtypes.LockDenyListEntry)
 	return nil
 }
 
+// UpdateOperatorInstance registers an instance of the operator in the
+// database.
+func (client *mockLockClient) UpdateOperatorInstance(instance fdbtypes.OperatorInstance) error {
+	client.operatorInstances[instance.ID] = instance
+	return nil
+}
+
+// GetOperatorInstances returns the instances of the operator that are
+// registered in the database.
+func (client *mockLockClient) GetOperatorInstances() ([]fdbtypes.OperatorInstance, error) {
+	instances := make([]fdbtypes.OperatorInstance, 0, len(client.operatorInstances))
+	for _, instance := range client.operatorInstances {
+		instances = append(instances, instance)
+	}
+
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].ID < instances[j].ID
+	})
+	return instances, nil
+}
+
 // lockClientCache provides a cache of mock lock clients.
 var lockClientCache = make(map[string]*mockLockClient)
 var lockClientMutex sync.Mutex
@@ -123,7 +158,11 @@ func newMockLockClientUncast(cluster *fdbtypes.FoundationDBCluster) *mockLockCli
 
 	client := lockClientCache[cluster.Name]
 	if client == nil {
-		client = &mockLockClient{cluster: cluster, pendingUpgrades: make(map[fdbtypes.FdbVersion]map[string]bool)}
+		client = &mockLockClient{
+			cluster:           cluster,
+			pendingUpgrades:   make(map[fdbtypes.FdbVersion]map[string]bool),
+			operatorInstances: make(map[string]fdbtypes.OperatorInstance),
+		}
 		lockClientCache[cluster.Name] = client
 	}
 	return client
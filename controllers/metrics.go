@@ -107,6 +107,20 @@ var (
 		append(descClusterDefaultLabels, "process_class"),
 		nil,
 	)
+
+	descTLSCertificateExpiry = prometheus.NewDesc(
+		"fdb_operator_tls_certificate_expiry_seconds",
+		"the expiry of the TLS certificate in the secret of the cluster as Unix timestamp.",
+		descClusterDefaultLabels,
+		nil,
+	)
+
+	descProcessGroupTLSCertificateExpiry = prometheus.NewDesc(
+		"fdb_operator_process_group_tls_certificate_expiry_seconds",
+		"the expiry of the TLS certificate that the processes of a process group have loaded as Unix timestamp.",
+		append(descClusterDefaultLabels, "process_class", "process_group_id"),
+		nil,
+	)
 )
 
 type fdbClusterCollector struct {
@@ -167,6 +181,18 @@ func collectMetrics(ch chan<- prometheus.Metric, cluster *fdbtypes.FoundationDBC
 		addGauge(descProcessGroupMarkedRemoval, float64(removals[pclass]), string(pclass))
 		addGauge(descProcessGroupMarkedExcluded, float64(exclusions[pclass]), string(pclass))
 	}
+
+	if cluster.Status.TLSCertificate != nil && cluster.Status.TLSCertificate.NotAfter != nil {
+		addGauge(descTLSCertificateExpiry, float64(cluster.Status.TLSCertificate.NotAfter.Unix()))
+	}
+
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if processGroup.TLSCertificate == nil || processGroup.TLSCertificate.NotAfter == nil {
+			continue
+		}
+
+		addGauge(descProcessGroupTLSCertificateExpiry, float64(processGroup.TLSCertificate.NotAfter.Unix()), string(processGroup.ProcessClass), processGroup.ProcessGroupID)
+	}
 }
 
 func getProcessGroupMetrics(cluster *fdbtypes.FoundationDBCluster) (map[fdbtypes.ProcessClass]map[fdbtypes.ProcessGroupConditionType]int, map[fdbtypes.ProcessClass]int, map[fdbtypes.ProcessClass]int) {
@@ -21,9 +21,14 @@
 package controllers
 
 import (
+	"time"
+
 	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var _ = Describe("metrics", func() {
@@ -72,4 +77,35 @@ var _ = Describe("metrics", func() {
 			Expect(exclusions[fdbtypes.ProcessClassStateless]).To(BeNumerically("==", 1))
 		})
 	})
+
+	Context("Collecting the TLS certificate metrics", func() {
+		var notAfter time.Time
+
+		BeforeEach(func() {
+			notAfter = time.Unix(1700000000, 0)
+			cluster.Status.TLSCertificate = &fdbtypes.TLSCertificateStatus{Hash: "abc", NotAfter: &metav1.Time{Time: notAfter}}
+			cluster.Status.ProcessGroups[0].ProcessGroupID = "storage-1"
+			cluster.Status.ProcessGroups[0].TLSCertificate = cluster.Status.TLSCertificate
+		})
+
+		It("reports the expiry of the certificates", func() {
+			ch := make(chan prometheus.Metric, 100)
+			collectMetrics(ch, cluster)
+			close(ch)
+
+			expiries := map[*prometheus.Desc][]float64{}
+			for metric := range ch {
+				if metric.Desc() != descTLSCertificateExpiry && metric.Desc() != descProcessGroupTLSCertificateExpiry {
+					continue
+				}
+
+				data := &dto.Metric{}
+				Expect(metric.Write(data)).NotTo(HaveOccurred())
+				expiries[metric.Desc()] = append(expiries[metric.Desc()], data.GetGauge().GetValue())
+			}
+
+			Expect(expiries[descTLSCertificateExpiry]).To(Equal([]float64{1700000000}))
+			Expect(expiries[descProcessGroupTLSCertificateExpiry]).To(Equal([]float64{1700000000}))
+		})
+	})
 })
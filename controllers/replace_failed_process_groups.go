@@ -22,6 +22,7 @@ package controllers
 
 import (
 	"context"
+	"time"
 
 	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal/replacements"
@@ -39,6 +40,15 @@ func (c replaceFailedProcessGroups) reconcile(ctx context.Context, r *Foundation
 	}
 	defer adminClient.Close()
 
+	if cluster.GetActiveFreeze(fdbtypes.AutomationOperationReplacements, time.Now()) != nil {
+		// Only report the freeze if it prevents a replacement.
+		if replacements.ReplaceFailedProcessGroups(log, cluster.DeepCopy(), adminClient) {
+			return checkAutomationFreeze(cluster, r.Recorder, fdbtypes.AutomationOperationReplacements)
+		}
+
+		return nil
+	}
+
 	if replacements.ReplaceFailedProcessGroups(log, cluster, adminClient) {
 		err := r.Status().Update(ctx, cluster)
 		if err != nil {
@@ -296,6 +296,61 @@ var _ = Describe("replace_failed_process_groups", func() {
 	})
 })
 
+var _ = Describe("replace_failed_process_groups reconciler", func() {
+	var cluster *fdbtypes.FoundationDBCluster
+	var result *requeue
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		err := k8sClient.Create(context.TODO(), cluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		result, err := reconcileCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Requeue).To(BeFalse())
+
+		generation, err := reloadCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(generation).To(Equal(int64(1)))
+
+		cluster.Spec.AutomationOptions.Freezes = []fdbtypes.AutomationFreeze{
+			{
+				Operation: fdbtypes.AutomationOperationReplacements,
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		result = replaceFailedProcessGroups{}.reconcile(context.TODO(), clusterReconciler, cluster)
+	})
+
+	When("replacements are frozen and no process group has failed", func() {
+		It("should not requeue", func() {
+			Expect(result).To(BeNil())
+		})
+	})
+
+	When("replacements are frozen and a process group has failed", func() {
+		BeforeEach(func() {
+			processGroup := fdbtypes.FindProcessGroupByID(cluster.Status.ProcessGroups, "storage-2")
+			processGroup.ProcessGroupConditions = append(processGroup.ProcessGroupConditions, &fdbtypes.ProcessGroupCondition{
+				ProcessGroupConditionType: fdbtypes.MissingProcesses,
+				Timestamp:                 time.Now().Add(-1 * time.Hour).Unix(),
+			})
+		})
+
+		It("should delay the requeue", func() {
+			Expect(result).NotTo(BeNil())
+			Expect(result.delayedRequeue).To(BeTrue())
+			Expect(result.message).To(Equal("Replacements are frozen"))
+		})
+
+		It("should not mark the process group for removal", func() {
+			Expect(getRemovedProcessGroupIDs(cluster)).To(BeEmpty())
+		})
+	})
+})
+
 // getRemovedProcessGroupIDs returns a list of ids for the process groups that
 // are marked for removal.
 func getRemovedProcessGroupIDs(cluster *fdbtypes.FoundationDBCluster) []string {
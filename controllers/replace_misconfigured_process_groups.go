@@ -22,6 +22,7 @@ package controllers
 
 import (
 	"context"
+	"time"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal/replacements"
 
@@ -50,6 +51,20 @@ func (c replaceMisconfiguredProcessGroups) reconcile(ctx context.Context, r *Fou
 		return &requeue{curError: err}
 	}
 
+	if cluster.GetActiveFreeze(fdbtypes.AutomationOperationReplacements, time.Now()) != nil {
+		// Only report the freeze if it prevents a replacement.
+		hasReplacements, err := replacements.ReplaceMisconfiguredProcessGroups(logger, cluster.DeepCopy(), internal.CreatePVCMap(cluster, pvcs), internal.CreatePodMap(cluster, pods))
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		if hasReplacements {
+			return checkAutomationFreeze(cluster, r.Recorder, fdbtypes.AutomationOperationReplacements)
+		}
+
+		return nil
+	}
+
 	hasReplacements, err := replacements.ReplaceMisconfiguredProcessGroups(logger, cluster, internal.CreatePVCMap(cluster, pvcs), internal.CreatePodMap(cluster, pods))
 	if err != nil {
 		return &requeue{curError: err}
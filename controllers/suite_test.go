@@ -183,12 +183,14 @@ func setupClusterForTest(cluster *fdbtypes.FoundationDBCluster) error {
 
 func createTestClusterReconciler() *FoundationDBClusterReconciler {
 	return &FoundationDBClusterReconciler{
-		Client:                 k8sClient,
-		Log:                    ctrl.Log.WithName("controllers").WithName("FoundationDBCluster"),
-		Recorder:               k8sClient,
-		InSimulation:           true,
-		PodLifecycleManager:    podmanager.StandardPodLifecycleManager{},
-		PodClientProvider:      internal.NewMockFdbPodClient,
-		DatabaseClientProvider: mockDatabaseClientProvider{},
+		Client:                          k8sClient,
+		Log:                             ctrl.Log.WithName("controllers").WithName("FoundationDBCluster"),
+		Recorder:                        k8sClient,
+		InSimulation:                    true,
+		PodLifecycleManager:             podmanager.StandardPodLifecycleManager{},
+		PodClientProvider:               internal.NewMockFdbPodClient,
+		DatabaseClientProvider:          mockDatabaseClientProvider{},
+		NodeReader:                      k8sClient,
+		UsePolicyV1PodDisruptionBudgets: true,
 	}
 }
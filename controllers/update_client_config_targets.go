@@ -0,0 +1,380 @@
+/*
+ * update_client_config_targets.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2021 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// updateClientConfigTargets provides a reconciliation step for publishing the
+// connection string to config maps and secrets in other namespaces.
+type updateClientConfigTargets struct{}
+
+// reconcile runs the reconciler's work.
+func (updateClientConfigTargets) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbtypes.FoundationDBCluster) *requeue {
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "updateClientConfigTargets")
+
+	if cluster.Status.ConnectionString == "" {
+		return nil
+	}
+
+	err := validateClientConfigTargets(r, cluster)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	// The finalizer has to be in place before any target is created in
+	// another namespace, so that the target is deleted with the cluster.
+	if len(getClientConfigTargetNamespaces(cluster)) > 0 {
+		err = updateClientConfigTargetFinalizer(ctx, r, cluster, true)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+	}
+
+	desiredTargets := make(map[string]bool, len(cluster.Spec.ClientConfigTargets))
+	for _, target := range cluster.Spec.ClientConfigTargets {
+		desiredTargets[target.Key(cluster.Name)] = true
+
+		var req *requeue
+		if target.GetKind() == fdbtypes.ClientConfigTargetSecret {
+			req = createOrUpdateClientConfigSecret(ctx, r, cluster, target, logger)
+		} else {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: internal.GetClientConfigMetadata(cluster, target),
+				Data:       internal.GetClientConfigData(cluster, target),
+			}
+			req = createOrUpdateConfigMap(ctx, r, cluster, configMap, logger.WithValues("target", target.Key(cluster.Name)))
+		}
+
+		if req != nil {
+			return req
+		}
+	}
+
+	if cluster.ShouldPublishClientLibraries() {
+		for _, namespace := range getClientConfigTargetNamespaces(cluster) {
+			configMap, err := internal.GetPublishedClientLibraryConfigMap(cluster, namespace)
+			if err != nil {
+				return &requeue{curError: err}
+			}
+
+			key := fdbtypes.ClientConfigTarget{Namespace: namespace, Name: configMap.Name}.Key(cluster.Name)
+			desiredTargets[key] = true
+
+			req := createOrUpdateConfigMap(ctx, r, cluster, configMap, logger.WithValues("target", key))
+			if req != nil {
+				return req
+			}
+		}
+	}
+
+	for _, key := range cluster.Status.ClientConfigTargets {
+		if desiredTargets[key] {
+			continue
+		}
+
+		err := deleteClientConfigTarget(ctx, r, cluster, key, logger)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+	}
+
+	if len(getClientConfigTargetNamespaces(cluster)) == 0 {
+		err = updateClientConfigTargetFinalizer(ctx, r, cluster, false)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+	}
+
+	publishedTargets := make([]string, 0, len(desiredTargets))
+	for key := range desiredTargets {
+		publishedTargets = append(publishedTargets, key)
+	}
+	sort.Strings(publishedTargets)
+
+	if !equality.Semantic.DeepEqual(publishedTargets, cluster.Status.ClientConfigTargets) {
+		if len(publishedTargets) == 0 {
+			publishedTargets = nil
+		}
+		cluster.Status.ClientConfigTargets = publishedTargets
+		err := r.Status().Update(ctx, cluster)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+	}
+
+	return nil
+}
+
+// validateClientConfigTargets checks that the client config targets are in
+// namespaces the operator is allowed to publish to, and that they don't collide
+// with the config maps the operator manages for the cluster.
+func validateClientConfigTargets(r *FoundationDBClusterReconciler, cluster *fdbtypes.FoundationDBCluster) error {
+	allowedNamespaces := make(map[string]bool, len(r.ClientConfigTargetNamespaces)+1)
+	allowedNamespaces[cluster.Namespace] = true
+	for _, namespace := range r.ClientConfigTargetNamespaces {
+		allowedNamespaces[namespace] = true
+	}
+
+	reservedNames := map[string]bool{
+		internal.GetClientLibraryConfigMapName(cluster.Name): true,
+	}
+
+	for _, target := range cluster.Spec.ClientConfigTargets {
+		key := target.Key(cluster.Name)
+		if !allowedNamespaces[target.Namespace] {
+			return fmt.Errorf("client config target %s is in namespace %s, which is not in the allowed namespaces of the operator", key, target.Namespace)
+		}
+
+		if target.GetKind() != fdbtypes.ClientConfigTargetConfigMap {
+			continue
+		}
+
+		name := target.GetName(cluster.Name)
+		if reservedNames[name] || (target.Namespace == cluster.Namespace && name == internal.GetConfigMapName(cluster)) {
+			return fmt.Errorf("client config target %s collides with a config map that is managed by the operator", key)
+		}
+	}
+
+	return nil
+}
+
+// checkClientConfigSource returns an error if the desired object is published
+// for a cluster and the existing object was not published for the same
+// cluster. This prevents the operator from overwriting objects that it
+// doesn't manage.
+func checkClientConfigSource(existing metav1.ObjectMeta, desired metav1.ObjectMeta) error {
+	source, published := desired.Annotations[fdbtypes.ClientConfigSourceAnnotation]
+	if !published {
+		return nil
+	}
+
+	if existing.Annotations[fdbtypes.ClientConfigSourceAnnotation] != source {
+		return fmt.Errorf("refusing to update %s/%s, which was not published for cluster %s", existing.Namespace, existing.Name, source)
+	}
+
+	return nil
+}
+
+// getClientConfigTargetNamespaces returns the namespaces of the client config
+// targets other than the namespace of the cluster.
+func getClientConfigTargetNamespaces(cluster *fdbtypes.FoundationDBCluster) []string {
+	namespaces := make(map[string]bool, len(cluster.Spec.ClientConfigTargets))
+	for _, target := range cluster.Spec.ClientConfigTargets {
+		if target.Namespace == cluster.Namespace {
+			continue
+		}
+		namespaces[target.Namespace] = true
+	}
+
+	result := make([]string, 0, len(namespaces))
+	for namespace := range namespaces {
+		result = append(result, namespace)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// createOrUpdateClientConfigSecret publishes the connection string to a secret.
+func createOrUpdateClientConfigSecret(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbtypes.FoundationDBCluster, target fdbtypes.ClientConfigTarget, logger logr.Logger) *requeue {
+	data := make(map[string][]byte)
+	for key, value := range internal.GetClientConfigData(cluster, target) {
+		data[key] = []byte(value)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: internal.GetClientConfigMetadata(cluster, target),
+		Data:       data,
+	}
+
+	existing := &corev1.Secret{}
+	err := r.getClientConfigTargetReader(cluster, secret.Namespace).Get(ctx, client.ObjectKey{Namespace: secret.Namespace, Name: secret.Name}, existing)
+	if err != nil && k8serrors.IsNotFound(err) {
+		logger.Info("Creating client config secret", "target", target.Key(cluster.Name))
+		err = r.Create(ctx, secret)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+		return nil
+	} else if err != nil {
+		return &requeue{curError: err}
+	}
+
+	err = checkClientConfigSource(existing.ObjectMeta, secret.ObjectMeta)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	metadataCorrect := true
+	if !reflect.DeepEqual(existing.ObjectMeta.Labels, secret.ObjectMeta.Labels) {
+		existing.ObjectMeta.Labels = secret.ObjectMeta.Labels
+		metadataCorrect = false
+	}
+
+	if mergeAnnotations(&existing.ObjectMeta, secret.ObjectMeta) {
+		metadataCorrect = false
+	}
+
+	if len(secret.ObjectMeta.OwnerReferences) > 0 && !reflect.DeepEqual(existing.ObjectMeta.OwnerReferences, secret.ObjectMeta.OwnerReferences) {
+		existing.ObjectMeta.OwnerReferences = secret.ObjectMeta.OwnerReferences
+		metadataCorrect = false
+	}
+
+	if !equality.Semantic.DeepEqual(existing.Data, secret.Data) || !metadataCorrect {
+		logger.Info("Updating client config secret", "target", target.Key(cluster.Name))
+		existing.Data = secret.Data
+		err = r.Update(ctx, existing)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+	}
+
+	return nil
+}
+
+// deleteClientConfigTarget deletes a config map or secret that is no longer a
+// client config target. Objects that were not published for this cluster are
+// left untouched.
+func deleteClientConfigTarget(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbtypes.FoundationDBCluster, key string, logger logr.Logger) error {
+	components := strings.Split(key, "/")
+	if len(components) != 3 {
+		return fmt.Errorf("invalid client config target %s", key)
+	}
+
+	var object client.Object
+	switch fdbtypes.ClientConfigTargetKind(components[0]) {
+	case fdbtypes.ClientConfigTargetConfigMap:
+		object = &corev1.ConfigMap{}
+	case fdbtypes.ClientConfigTargetSecret:
+		object = &corev1.Secret{}
+	default:
+		return fmt.Errorf("invalid client config target %s", key)
+	}
+
+	err := r.getClientConfigTargetReader(cluster, components[1]).Get(ctx, client.ObjectKey{Namespace: components[1], Name: components[2]}, object)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if object.GetAnnotations()[fdbtypes.ClientConfigSourceAnnotation] != fmt.Sprintf("%s/%s", cluster.Namespace, cluster.Name) {
+		logger.Info("Skipping deletion of client config target from another source", "target", key)
+		return nil
+	}
+
+	logger.Info("Deleting client config target", "target", key)
+	err = r.Delete(ctx, object)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// getClientConfigTargetReader provides the reader for client config targets in
+// a namespace. Targets in other namespaces are read with the uncached reader,
+// because the cache of the operator may not include those namespaces.
+func (r *FoundationDBClusterReconciler) getClientConfigTargetReader(cluster *fdbtypes.FoundationDBCluster, namespace string) client.Reader {
+	if namespace == cluster.Namespace || r.ClientConfigTargetReader == nil {
+		return r
+	}
+
+	return r.ClientConfigTargetReader
+}
+
+// updateClientConfigTargetFinalizer adds or removes the finalizer for the
+// client config targets in other namespaces.
+func updateClientConfigTargetFinalizer(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbtypes.FoundationDBCluster, needed bool) error {
+	if controllerutil.ContainsFinalizer(cluster, fdbtypes.ClientConfigTargetFinalizer) == needed {
+		return nil
+	}
+
+	// Only the finalizers are updated, so the defaults from normalizing the
+	// spec don't end up in the stored cluster.
+	current := &fdbtypes.FoundationDBCluster{}
+	err := r.Get(ctx, client.ObjectKeyFromObject(cluster), current)
+	if err != nil {
+		return err
+	}
+
+	if needed {
+		controllerutil.AddFinalizer(current, fdbtypes.ClientConfigTargetFinalizer)
+	} else {
+		controllerutil.RemoveFinalizer(current, fdbtypes.ClientConfigTargetFinalizer)
+	}
+
+	err = r.Update(ctx, current)
+	if err != nil {
+		return err
+	}
+
+	cluster.ObjectMeta.Finalizers = current.ObjectMeta.Finalizers
+	cluster.ObjectMeta.ResourceVersion = current.ObjectMeta.ResourceVersion
+
+	return nil
+}
+
+// finalizeClientConfigTargets deletes the client config targets of a cluster
+// that is being deleted and removes the finalizer.
+func finalizeClientConfigTargets(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbtypes.FoundationDBCluster) error {
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "updateClientConfigTargets")
+
+	// The status may not include targets that were created right before the
+	// deletion, so the targets from the spec are deleted as well.
+	keys := make(map[string]bool, len(cluster.Status.ClientConfigTargets)+len(cluster.Spec.ClientConfigTargets))
+	for _, key := range cluster.Status.ClientConfigTargets {
+		keys[key] = true
+	}
+	for _, target := range cluster.Spec.ClientConfigTargets {
+		keys[target.Key(cluster.Name)] = true
+	}
+	for _, namespace := range getClientConfigTargetNamespaces(cluster) {
+		keys[fdbtypes.ClientConfigTarget{Namespace: namespace, Name: internal.GetClientLibraryConfigMapName(cluster.Name)}.Key(cluster.Name)] = true
+	}
+
+	for key := range keys {
+		err := deleteClientConfigTarget(ctx, r, cluster, key, logger)
+		if err != nil {
+			return err
+		}
+	}
+
+	return updateClientConfigTargetFinalizer(ctx, r, cluster, false)
+}
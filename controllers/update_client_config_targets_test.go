@@ -0,0 +1,322 @@
+/*
+ * update_client_config_targets_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2021 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("update_client_config_targets", func() {
+	var cluster *fdbtypes.FoundationDBCluster
+	var requeue *requeue
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		cluster.Spec.TrustedCAs = []string{"---CERT1----"}
+		cluster.Spec.ClientConfigTargets = []fdbtypes.ClientConfigTarget{
+			{Namespace: "app-1"},
+			{Namespace: "app-2", Name: "fdb-config", Kind: fdbtypes.ClientConfigTargetSecret, IncludeTrustedCAs: true},
+		}
+		clusterReconciler.ClientConfigTargetNamespaces = []string{"app-1", "app-2"}
+		err := setupClusterForTest(cluster)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		clusterReconciler.ClientConfigTargetNamespaces = nil
+	})
+
+	JustBeforeEach(func() {
+		requeue = updateClientConfigTargets{}.reconcile(context.TODO(), clusterReconciler, cluster)
+	})
+
+	It("should publish the connection string to a config map", func() {
+		Expect(requeue).To(BeNil())
+		configMap := &corev1.ConfigMap{}
+		err := k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: "app-1", Name: "operator-test-1-client-config"}, configMap)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(configMap.Data).To(Equal(map[string]string{
+			internal.ClusterFileKey: cluster.Status.ConnectionString,
+		}))
+		Expect(configMap.Annotations[fdbtypes.ClientConfigSourceAnnotation]).To(Equal("my-ns/operator-test-1"))
+	})
+
+	It("should publish the connection string and the CAs to a secret", func() {
+		Expect(requeue).To(BeNil())
+		secret := &corev1.Secret{}
+		err := k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: "app-2", Name: "fdb-config"}, secret)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secret.Data).To(Equal(map[string][]byte{
+			internal.ClusterFileKey: []byte(cluster.Status.ConnectionString),
+			"ca-file":               []byte("---CERT1----"),
+		}))
+	})
+
+	It("should record the targets in the status", func() {
+		Expect(requeue).To(BeNil())
+		Expect(cluster.Status.ClientConfigTargets).To(Equal([]string{
+			"ConfigMap/app-1/operator-test-1-client-config",
+			"Secret/app-2/fdb-config",
+		}))
+	})
+
+	It("should add the finalizer for the targets in other namespaces", func() {
+		Expect(requeue).To(BeNil())
+		_, err := reloadCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cluster.Finalizers).To(ContainElement(fdbtypes.ClientConfigTargetFinalizer))
+	})
+
+	It("should not set owner references on the targets in other namespaces", func() {
+		Expect(requeue).To(BeNil())
+		configMap := &corev1.ConfigMap{}
+		err := k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: "app-1", Name: "operator-test-1-client-config"}, configMap)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(configMap.OwnerReferences).To(BeEmpty())
+	})
+
+	When("the cluster is deleted", func() {
+		JustBeforeEach(func() {
+			Expect(requeue).To(BeNil())
+			cluster.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			Expect(finalizeClientConfigTargets(context.TODO(), clusterReconciler, cluster)).NotTo(HaveOccurred())
+		})
+
+		It("should delete the targets", func() {
+			configMap := &corev1.ConfigMap{}
+			err := k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: "app-1", Name: "operator-test-1-client-config"}, configMap)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			secret := &corev1.Secret{}
+			err = k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: "app-2", Name: "fdb-config"}, secret)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should remove the finalizer", func() {
+			Expect(cluster.Finalizers).NotTo(ContainElement(fdbtypes.ClientConfigTargetFinalizer))
+			_, err := reloadCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cluster.Finalizers).NotTo(ContainElement(fdbtypes.ClientConfigTargetFinalizer))
+		})
+	})
+
+	When("the connection string changes", func() {
+		BeforeEach(func() {
+			cluster.Status.ConnectionString = "operator-test:changed@127.0.0.1:4501"
+		})
+
+		It("should update the targets", func() {
+			Expect(requeue).To(BeNil())
+			configMap := &corev1.ConfigMap{}
+			err := k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: "app-1", Name: "operator-test-1-client-config"}, configMap)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(configMap.Data[internal.ClusterFileKey]).To(Equal("operator-test:changed@127.0.0.1:4501"))
+
+			secret := &corev1.Secret{}
+			err = k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: "app-2", Name: "fdb-config"}, secret)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(secret.Data[internal.ClusterFileKey])).To(Equal("operator-test:changed@127.0.0.1:4501"))
+		})
+	})
+
+	When("a target is removed", func() {
+		BeforeEach(func() {
+			cluster.Spec.ClientConfigTargets = cluster.Spec.ClientConfigTargets[:1]
+		})
+
+		It("should delete the removed target", func() {
+			Expect(requeue).To(BeNil())
+			secret := &corev1.Secret{}
+			err := k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: "app-2", Name: "fdb-config"}, secret)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+			Expect(cluster.Status.ClientConfigTargets).To(Equal([]string{
+				"ConfigMap/app-1/operator-test-1-client-config",
+			}))
+		})
+	})
+
+	When("the client libraries are published", func() {
+		BeforeEach(func() {
+			enabled := true
+			cluster.Spec.ClientLibraries.Enabled = &enabled
+		})
+
+		It("should publish the client libraries in the namespaces of the targets", func() {
+			Expect(requeue).To(BeNil())
+			for _, namespace := range []string{"app-1", "app-2"} {
+				configMap := &corev1.ConfigMap{}
+				err := k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: namespace, Name: "operator-test-1-client-libraries"}, configMap)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(configMap.Data).To(HaveKey("6.2"))
+				Expect(configMap.OwnerReferences).To(BeEmpty())
+				Expect(configMap.Annotations[fdbtypes.ClientConfigSourceAnnotation]).To(Equal("my-ns/operator-test-1"))
+			}
+		})
+
+		It("should record the client libraries in the status", func() {
+			Expect(requeue).To(BeNil())
+			Expect(cluster.Status.ClientConfigTargets).To(Equal([]string{
+				"ConfigMap/app-1/operator-test-1-client-config",
+				"ConfigMap/app-1/operator-test-1-client-libraries",
+				"ConfigMap/app-2/operator-test-1-client-libraries",
+				"Secret/app-2/fdb-config",
+			}))
+		})
+
+		When("the client libraries are disabled again", func() {
+			JustBeforeEach(func() {
+				enabled := false
+				cluster.Spec.ClientLibraries.Enabled = &enabled
+				requeue = updateClientConfigTargets{}.reconcile(context.TODO(), clusterReconciler, cluster)
+			})
+
+			It("should delete the published client libraries", func() {
+				Expect(requeue).To(BeNil())
+				configMap := &corev1.ConfigMap{}
+				err := k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: "app-1", Name: "operator-test-1-client-libraries"}, configMap)
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+				Expect(cluster.Status.ClientConfigTargets).To(Equal([]string{
+					"ConfigMap/app-1/operator-test-1-client-config",
+					"Secret/app-2/fdb-config",
+				}))
+			})
+		})
+	})
+
+	When("a target is in a namespace that is not allowed", func() {
+		BeforeEach(func() {
+			cluster.Spec.ClientConfigTargets = append(cluster.Spec.ClientConfigTargets, fdbtypes.ClientConfigTarget{Namespace: "app-3"})
+		})
+
+		It("should not publish the targets", func() {
+			Expect(requeue).NotTo(BeNil())
+			Expect(requeue.curError).To(MatchError("client config target ConfigMap/app-3/operator-test-1-client-config is in namespace app-3, which is not in the allowed namespaces of the operator"))
+			configMap := &corev1.ConfigMap{}
+			err := k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: "app-3", Name: "operator-test-1-client-config"}, configMap)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	When("a target is in the namespace of the cluster", func() {
+		BeforeEach(func() {
+			cluster.Spec.ClientConfigTargets = []fdbtypes.ClientConfigTarget{{Namespace: cluster.Namespace}}
+		})
+
+		It("should publish the target", func() {
+			Expect(requeue).To(BeNil())
+			Expect(cluster.Status.ClientConfigTargets).To(Equal([]string{
+				"ConfigMap/my-ns/operator-test-1-client-config",
+			}))
+		})
+
+		It("should set the cluster as the owner of the target", func() {
+			Expect(requeue).To(BeNil())
+			configMap := &corev1.ConfigMap{}
+			err := k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: cluster.Namespace, Name: "operator-test-1-client-config"}, configMap)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(configMap.OwnerReferences).To(Equal(internal.BuildOwnerReference(cluster.TypeMeta, cluster.ObjectMeta)))
+		})
+
+		It("should remove the finalizer", func() {
+			Expect(requeue).To(BeNil())
+			_, err := reloadCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cluster.Finalizers).NotTo(ContainElement(fdbtypes.ClientConfigTargetFinalizer))
+		})
+	})
+
+	When("a target collides with the config map of the cluster", func() {
+		BeforeEach(func() {
+			cluster.Spec.ClientConfigTargets = []fdbtypes.ClientConfigTarget{{Namespace: cluster.Namespace, Name: "operator-test-1-config"}}
+		})
+
+		It("should not publish the target", func() {
+			Expect(requeue).NotTo(BeNil())
+			Expect(requeue.curError).To(MatchError("client config target ConfigMap/my-ns/operator-test-1-config collides with a config map that is managed by the operator"))
+		})
+	})
+
+	When("a target collides with the client library config map", func() {
+		BeforeEach(func() {
+			cluster.Spec.ClientConfigTargets = []fdbtypes.ClientConfigTarget{{Namespace: "app-1", Name: "operator-test-1-client-libraries"}}
+		})
+
+		It("should not publish the target", func() {
+			Expect(requeue).NotTo(BeNil())
+			Expect(requeue.curError).To(MatchError("client config target ConfigMap/app-1/operator-test-1-client-libraries collides with a config map that is managed by the operator"))
+		})
+	})
+
+	When("the target already exists without the source annotation", func() {
+		BeforeEach(func() {
+			err := k8sClient.Create(context.TODO(), &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app-2", Name: "app-credentials"},
+				Data:       map[string][]byte{"password": []byte("secret")},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			cluster.Spec.ClientConfigTargets = append(cluster.Spec.ClientConfigTargets, fdbtypes.ClientConfigTarget{Namespace: "app-2", Name: "app-credentials", Kind: fdbtypes.ClientConfigTargetSecret})
+		})
+
+		It("should not update the existing secret", func() {
+			Expect(requeue).NotTo(BeNil())
+			Expect(requeue.curError).To(MatchError("refusing to update app-2/app-credentials, which was not published for cluster my-ns/operator-test-1"))
+			secret := &corev1.Secret{}
+			err := k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: "app-2", Name: "app-credentials"}, secret)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(secret.Data).To(Equal(map[string][]byte{"password": []byte("secret")}))
+		})
+	})
+
+	When("the config map target already exists for another cluster", func() {
+		BeforeEach(func() {
+			err := k8sClient.Create(context.TODO(), &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "app-1",
+					Name:        "shared-config",
+					Annotations: map[string]string{fdbtypes.ClientConfigSourceAnnotation: "other-ns/operator-test-1"},
+				},
+				Data: map[string]string{internal.ClusterFileKey: "other:cluster@127.0.0.2:4501"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			cluster.Spec.ClientConfigTargets = append(cluster.Spec.ClientConfigTargets, fdbtypes.ClientConfigTarget{Namespace: "app-1", Name: "shared-config"})
+		})
+
+		It("should not update the existing config map", func() {
+			Expect(requeue).NotTo(BeNil())
+			Expect(requeue.curError).To(MatchError("refusing to update app-1/shared-config, which was not published for cluster my-ns/operator-test-1"))
+			configMap := &corev1.ConfigMap{}
+			err := k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: "app-1", Name: "shared-config"}, configMap)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(configMap.Data[internal.ClusterFileKey]).To(Equal("other:cluster@127.0.0.2:4501"))
+		})
+	})
+})
@@ -0,0 +1,48 @@
+/*
+ * update_client_libraries.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2021 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+)
+
+// updateClientLibraries provides a reconciliation step for publishing the
+// client libraries for application pods.
+type updateClientLibraries struct{}
+
+// reconcile runs the reconciler's work.
+func (updateClientLibraries) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbtypes.FoundationDBCluster) *requeue {
+	if !cluster.ShouldPublishClientLibraries() {
+		return nil
+	}
+
+	configMap, err := internal.GetClientLibraryConfigMap(cluster)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+	logger := log.WithValues("namespace", configMap.Namespace, "cluster", cluster.Name, "name", configMap.Name, "reconciler", "updateClientLibraries")
+
+	return createOrUpdateConfigMap(ctx, r, cluster, configMap, logger)
+}
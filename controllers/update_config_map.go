@@ -23,8 +23,10 @@ package controllers
 import (
 	"context"
 	"reflect"
+	"time"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/go-logr/logr"
 
 	"k8s.io/apimachinery/pkg/api/equality"
 
@@ -45,8 +47,28 @@ func (u updateConfigMap) reconcile(ctx context.Context, r *FoundationDBClusterRe
 		return &requeue{curError: err}
 	}
 	logger := log.WithValues("namespace", configMap.Namespace, "cluster", cluster.Name, "name", configMap.Name, "reconciler", "UpdateConfigMap")
+
+	configMapRequeue := createOrUpdateConfigMap(ctx, r, cluster, configMap, logger)
+	if configMapRequeue != nil {
+		return configMapRequeue
+	}
+
+	// Make sure we regenerate the config map when a parameter override
+	// expires.
+	now := time.Now()
+	nextExpiration := cluster.GetNextParameterOverrideExpiration(now)
+	if nextExpiration != nil {
+		return &requeue{message: "Waiting for parameter override to expire", delay: delayUntil(now, *nextExpiration), delayedRequeue: true}
+	}
+
+	return nil
+}
+
+// createOrUpdateConfigMap creates the config map if it doesn't exist or
+// updates it if its data or metadata don't match.
+func createOrUpdateConfigMap(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbtypes.FoundationDBCluster, configMap *corev1.ConfigMap, logger logr.Logger) *requeue {
 	existing := &corev1.ConfigMap{}
-	err = r.Get(ctx, types.NamespacedName{Namespace: configMap.Namespace, Name: configMap.Name}, existing)
+	err := r.getClientConfigTargetReader(cluster, configMap.Namespace).Get(ctx, types.NamespacedName{Namespace: configMap.Namespace, Name: configMap.Name}, existing)
 	if err != nil && k8serrors.IsNotFound(err) {
 		logger.Info("Creating config map")
 		err = r.Create(ctx, configMap)
@@ -58,6 +80,11 @@ func (u updateConfigMap) reconcile(ctx context.Context, r *FoundationDBClusterRe
 		return &requeue{curError: err}
 	}
 
+	err = checkClientConfigSource(existing.ObjectMeta, configMap.ObjectMeta)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
 	metadataCorrect := true
 	if !reflect.DeepEqual(existing.ObjectMeta.Labels, configMap.ObjectMeta.Labels) {
 		existing.ObjectMeta.Labels = configMap.ObjectMeta.Labels
@@ -68,6 +95,11 @@ func (u updateConfigMap) reconcile(ctx context.Context, r *FoundationDBClusterRe
 		metadataCorrect = false
 	}
 
+	if len(configMap.ObjectMeta.OwnerReferences) > 0 && !reflect.DeepEqual(existing.ObjectMeta.OwnerReferences, configMap.ObjectMeta.OwnerReferences) {
+		existing.ObjectMeta.OwnerReferences = configMap.ObjectMeta.OwnerReferences
+		metadataCorrect = false
+	}
+
 	if !equality.Semantic.DeepEqual(existing.Data, configMap.Data) || !metadataCorrect {
 		logger.Info("Updating config map")
 		r.Recorder.Event(cluster, corev1.EventTypeNormal, "UpdatingConfigMap", "")
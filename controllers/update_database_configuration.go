@@ -24,8 +24,12 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 
 	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
 )
@@ -44,8 +48,12 @@ func (u updateDatabaseConfiguration) reconcile(ctx context.Context, r *Foundatio
 	}
 	defer adminClient.Close()
 
+	now := r.now()
 	desiredConfiguration := cluster.DesiredDatabaseConfiguration()
 	desiredConfiguration.RoleCounts.Storage = 0
+	if cluster.CanRunStorageWiggle() && cluster.IsStorageWigglePaused(now) {
+		desiredConfiguration.PerpetualStorageWiggle = pointer.Int(0)
+	}
 	needsChange := false
 	var currentConfiguration fdbtypes.DatabaseConfiguration
 
@@ -67,6 +75,8 @@ func (u updateDatabaseConfiguration) reconcile(ctx context.Context, r *Foundatio
 
 	currentConfiguration = status.Cluster.DatabaseConfiguration.NormalizeConfiguration()
 	cluster.ClearMissingVersionFlags(&currentConfiguration)
+	cluster.ClearUnusedProxyCounts(&currentConfiguration)
+	cluster.ClearMissingStorageWiggleOptions(&currentConfiguration)
 	needsChange = initialConfig || !reflect.DeepEqual(desiredConfiguration, currentConfiguration)
 
 	if needsChange {
@@ -97,7 +107,29 @@ func (u updateDatabaseConfiguration) reconcile(ctx context.Context, r *Foundatio
 			return &requeue{message: "Database configuration changes are disabled"}
 		}
 
+		failOver := !initialConfig && currentConfiguration.GetPrimaryDataCenter() != nextConfiguration.GetPrimaryDataCenter()
+		if failOver {
+			failOverRequeue := waitForFailOverCatchUp(ctx, r, cluster, status, currentConfiguration.GetPrimaryDataCenter(), nextConfiguration.GetPrimaryDataCenter())
+			if failOverRequeue != nil {
+				return failOverRequeue
+			}
+		}
+
 		if !initialConfig {
+			// The instances of the operator in the other Kubernetes clusters
+			// would revert the change if they want a different configuration.
+			disagreeing, err := getDisagreeingOperatorInstances(r, cluster)
+			if err != nil {
+				return &requeue{curError: err}
+			}
+
+			if len(disagreeing) > 0 {
+				logger.Info("Waiting for all operator instances to agree on the database configuration", "instances", disagreeing)
+				r.Recorder.Event(cluster, corev1.EventTypeNormal, "NeedsConfigurationChange",
+					fmt.Sprintf("Spec require configuration change to `%s`, but the operator instances %s want a different configuration", configurationString, strings.Join(disagreeing, ", ")))
+				return &requeue{message: "Waiting for all operator instances to agree on the database configuration", delay: podSchedulingDelayDuration, delayedRequeue: true}
+			}
+
 			hasLock, err := r.takeLock(cluster,
 				fmt.Sprintf("reconfiguring the database to `%s`", configurationString))
 			if !hasLock {
@@ -123,11 +155,121 @@ func (u updateDatabaseConfiguration) reconcile(ctx context.Context, r *Foundatio
 		}
 		logger.Info("Configured database")
 
+		if failOver {
+			cluster.Status.FailOver.Phase = fdbtypes.FailOverPhaseRecovering
+			err = r.Status().Update(ctx, cluster)
+			if err != nil {
+				return &requeue{curError: err}
+			}
+		}
+
 		if !reflect.DeepEqual(nextConfiguration, desiredConfiguration) {
 			logger.Info("Requeuing for next stage of database configuration change")
 			return &requeue{message: "Requeuing for next stage of database configuration change"}
 		}
 	}
 
+	return checkStorageWiggleSchedule(cluster, now)
+}
+
+// checkStorageWiggleSchedule returns a scheduled requeue for the next time the
+// operator has to pause or resume the perpetual storage wiggle because a
+// maintenance window opens or closes. If the wiggle is not paused outside of
+// maintenance windows, or no window opens or closes in the foreseeable
+// future, this will return nil.
+func checkStorageWiggleSchedule(cluster *fdbtypes.FoundationDBCluster, now time.Time) *requeue {
+	if !cluster.GetPauseStorageWiggleOutsideMaintenanceWindows() || !cluster.CanRunStorageWiggle() || len(cluster.Spec.MaintenanceWindows) == 0 {
+		return nil
+	}
+
+	open, nextStart, err := cluster.IsInMaintenanceWindow(now)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	if !open {
+		if nextStart.IsZero() {
+			return nil
+		}
+
+		return &requeue{
+			message:   fmt.Sprintf("Resuming storage wiggle when the next maintenance window opens at %s", nextStart.UTC().Format(time.RFC3339)),
+			delay:     delayUntil(now, nextStart),
+			scheduled: true,
+		}
+	}
+
+	end, err := cluster.GetMaintenanceWindowEnd(now)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	if end.IsZero() {
+		return nil
+	}
+
+	return &requeue{
+		message:   fmt.Sprintf("Pausing storage wiggle when the maintenance window closes at %s", end.UTC().Format(time.RFC3339)),
+		delay:     delayUntil(now, end),
+		scheduled: true,
+	}
+}
+
+// waitForFailOverCatchUp records the fail over to a new primary data center
+// in the cluster status and checks that the new primary has caught up with
+// the current primary. If the new primary lags behind, this will return a
+// delayed requeue. Otherwise this will return nil.
+func waitForFailOverCatchUp(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbtypes.FoundationDBCluster, status *fdbtypes.FoundationDBStatus, from string, to string) *requeue {
+	if cluster.Status.FailOver == nil || cluster.Status.FailOver.To != to {
+		r.Recorder.Event(cluster, corev1.EventTypeNormal, "StartingFailOver", fmt.Sprintf("Failing over from %s to %s", from, to))
+		cluster.Status.FailOver = &fdbtypes.FailOverStatus{
+			From:      from,
+			To:        to,
+			Phase:     fdbtypes.FailOverPhaseWaitingForCatchUp,
+			StartTime: &metav1.Time{Time: time.Now()},
+		}
+
+		err := r.Status().Update(ctx, cluster)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+	}
+
+	waitingMessage := ""
+	if status.Cluster.DatabaseConfiguration.UsableRegions < 2 {
+		waitingMessage = fmt.Sprintf("Waiting for data center %s to hold a replica of the data before failing over, usable_regions is %d", to, status.Cluster.DatabaseConfiguration.UsableRegions)
+	} else if !status.Client.DatabaseStatus.Available || !status.Client.DatabaseStatus.Healthy {
+		waitingMessage = fmt.Sprintf("Waiting for the database to be available and healthy before failing over to data center %s", to)
+	} else if !hasProcessesInDataCenter(status, to) {
+		waitingMessage = fmt.Sprintf("Waiting for processes in data center %s to report to the database before failing over", to)
+	} else if status.Cluster.DatacenterLag == nil {
+		waitingMessage = fmt.Sprintf("Waiting for the database to report the lag of data center %s before failing over", to)
+	} else {
+		lag := time.Duration(status.Cluster.DatacenterLag.Seconds * float64(time.Second))
+		if lag > maxFailOverDataCenterLag {
+			waitingMessage = fmt.Sprintf("Waiting for data center %s to catch up before failing over, current lag: %s", to, lag)
+		}
+	}
+
+	if waitingMessage != "" {
+		return &requeue{
+			message:        waitingMessage,
+			delay:          podSchedulingDelayDuration,
+			delayedRequeue: true,
+		}
+	}
+
 	return nil
 }
+
+// hasProcessesInDataCenter determines if any process that is not excluded
+// reports the data center in its locality.
+func hasProcessesInDataCenter(status *fdbtypes.FoundationDBStatus, dataCenter string) bool {
+	for _, process := range status.Cluster.Processes {
+		if !process.Excluded && process.Locality[fdbtypes.FDBLocalityDCIDKey] == dataCenter {
+			return true
+		}
+	}
+
+	return false
+}
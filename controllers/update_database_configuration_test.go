@@ -0,0 +1,366 @@
+/*
+ * update_database_configuration_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2021 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("updateDatabaseConfiguration", func() {
+	var cluster *fdbtypes.FoundationDBCluster
+	var adminClient *mockAdminClient
+	var result *requeue
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		err := setupClusterForTest(cluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		adminClient, err = newMockAdminClientUncast(cluster, k8sClient)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	When("failing over to another data center", func() {
+		BeforeEach(func() {
+			cluster.Spec.DataCenter = "dc1"
+			cluster.Spec.DatabaseConfiguration.Regions = []fdbtypes.Region{
+				{
+					DataCenters: []fdbtypes.DataCenter{
+						{ID: "dc1", Priority: 1},
+					},
+				},
+				{
+					DataCenters: []fdbtypes.DataCenter{
+						{ID: "dc2", Priority: 0},
+					},
+				},
+			}
+
+			cluster.Spec.DatabaseConfiguration.UsableRegions = 2
+
+			configuration := cluster.DesiredDatabaseConfiguration()
+			adminClient.DatabaseConfiguration = &configuration
+
+			adminClient.MockAdditionalProcesses([]fdbtypes.ProcessGroupStatus{{
+				ProcessGroupID: "dc2-log-1",
+				ProcessClass:   fdbtypes.ProcessClassLog,
+				Addresses:      []string{"1.1.2.1"},
+			}})
+			adminClient.MockLocalityInfo("dc2-log-1", map[string]string{
+				fdbtypes.FDBLocalityDCIDKey: "dc2",
+			})
+
+			cluster.Spec.PrimaryDataCenter = "dc2"
+		})
+
+		JustBeforeEach(func() {
+			// The configuration change can require multiple stages.
+			for attempt := 0; attempt < 5; attempt++ {
+				result = updateDatabaseConfiguration{}.reconcile(context.TODO(), clusterReconciler, cluster)
+				if result == nil || result.delayedRequeue {
+					break
+				}
+			}
+		})
+
+		When("the data center lags behind", func() {
+			BeforeEach(func() {
+				adminClient.MockDataCenterLag(30)
+			})
+
+			AfterEach(func() {
+				adminClient.MockDataCenterLag(0)
+			})
+
+			It("should wait for the data center to catch up", func() {
+				Expect(result).NotTo(BeNil())
+				Expect(result.delayedRequeue).To(BeTrue())
+				Expect(adminClient.DatabaseConfiguration.GetPrimaryDataCenter()).To(Equal("dc1"))
+
+				Expect(cluster.Status.FailOver).NotTo(BeNil())
+				Expect(cluster.Status.FailOver.From).To(Equal("dc1"))
+				Expect(cluster.Status.FailOver.To).To(Equal("dc2"))
+				Expect(cluster.Status.FailOver.Phase).To(Equal(fdbtypes.FailOverPhaseWaitingForCatchUp))
+			})
+		})
+
+		When("the database doesn't report the data center lag", func() {
+			BeforeEach(func() {
+				adminClient.MockMissingDataCenterLag(true)
+			})
+
+			AfterEach(func() {
+				adminClient.MockMissingDataCenterLag(false)
+			})
+
+			It("should not fail over", func() {
+				Expect(result).NotTo(BeNil())
+				Expect(result.delayedRequeue).To(BeTrue())
+				Expect(result.message).To(Equal("Waiting for the database to report the lag of data center dc2 before failing over"))
+				Expect(adminClient.DatabaseConfiguration.GetPrimaryDataCenter()).To(Equal("dc1"))
+			})
+		})
+
+		When("the database only stores data in one region", func() {
+			It("should not fail over", func() {
+				status, err := adminClient.GetStatus()
+				Expect(err).NotTo(HaveOccurred())
+				status.Cluster.DatabaseConfiguration.UsableRegions = 1
+
+				result := waitForFailOverCatchUp(context.TODO(), clusterReconciler, cluster, status, "dc1", "dc2")
+				Expect(result).NotTo(BeNil())
+				Expect(result.delayedRequeue).To(BeTrue())
+				Expect(result.message).To(Equal("Waiting for data center dc2 to hold a replica of the data before failing over, usable_regions is 1"))
+			})
+		})
+
+		When("the target data center has no processes", func() {
+			BeforeEach(func() {
+				adminClient.MockLocalityInfo("dc2-log-1", map[string]string{
+					fdbtypes.FDBLocalityDCIDKey: "dc3",
+				})
+			})
+
+			It("should not fail over", func() {
+				Expect(result).NotTo(BeNil())
+				Expect(result.delayedRequeue).To(BeTrue())
+				Expect(result.message).To(Equal("Waiting for processes in data center dc2 to report to the database before failing over"))
+				Expect(adminClient.DatabaseConfiguration.GetPrimaryDataCenter()).To(Equal("dc1"))
+			})
+		})
+
+		When("the data center has caught up", func() {
+			It("should change the region priorities", func() {
+				Expect(result).To(BeNil())
+				Expect(adminClient.DatabaseConfiguration.GetPrimaryDataCenter()).To(Equal("dc2"))
+
+				Expect(cluster.Status.FailOver).NotTo(BeNil())
+				Expect(cluster.Status.FailOver.Phase).To(Equal(fdbtypes.FailOverPhaseRecovering))
+			})
+
+			When("the database has recovered", func() {
+				JustBeforeEach(func() {
+					result = updateStatus{}.reconcile(context.TODO(), clusterReconciler, cluster)
+				})
+
+				It("should complete the fail over", func() {
+					Expect(result).To(BeNil())
+					Expect(cluster.Status.FailOver).To(BeNil())
+					Expect(cluster.Status.ActivePrimaryDataCenter).To(Equal("dc2"))
+				})
+			})
+		})
+	})
+
+	When("the version reports the separated proxy counts", func() {
+		BeforeEach(func() {
+			cluster.Spec.Version = fdbtypes.Versions.WithSeparatedProxies.String()
+			cluster.Status.RunningVersion = cluster.Spec.Version
+
+			// FDB 7.0 reports the GRV proxy and commit proxy counts even
+			// when they are not set in the spec.
+			configuration := cluster.DesiredDatabaseConfiguration()
+			configuration.Proxies = 0
+			configuration.GrvProxies = 1
+			configuration.CommitProxies = 2
+			adminClient.DatabaseConfiguration = &configuration
+		})
+
+		JustBeforeEach(func() {
+			result = updateDatabaseConfiguration{}.reconcile(context.TODO(), clusterReconciler, cluster)
+		})
+
+		It("should not change the configuration", func() {
+			Expect(result).To(BeNil())
+			Expect(adminClient.DatabaseConfiguration.Proxies).To(Equal(0))
+			Expect(adminClient.DatabaseConfiguration.GrvProxies).To(Equal(1))
+			Expect(adminClient.DatabaseConfiguration.CommitProxies).To(Equal(2))
+		})
+
+		It("should consider the configuration reconciled", func() {
+			Expect(updateStatus{}.reconcile(context.TODO(), clusterReconciler, cluster)).To(BeNil())
+			Expect(cluster.Status.DatabaseConfiguration).To(Equal(cluster.DesiredDatabaseConfiguration()))
+		})
+	})
+
+	When("another operator instance wants a different configuration", func() {
+		BeforeEach(func() {
+			cluster.Spec.LockOptions.DisableLocks = pointer.Bool(false)
+			cluster.Spec.DatabaseConfiguration.RedundancyMode = fdbtypes.RedundancyModeTriple
+
+			lockClient := newMockLockClientUncast(cluster)
+			Expect(lockClient.UpdateOperatorInstance(fdbtypes.OperatorInstance{
+				ID:                        "dc2",
+				DatabaseConfigurationHash: "other",
+				Timestamp:                 time.Now().Unix(),
+			})).NotTo(HaveOccurred())
+		})
+
+		JustBeforeEach(func() {
+			result = updateDatabaseConfiguration{}.reconcile(context.TODO(), clusterReconciler, cluster)
+		})
+
+		It("should not change the configuration", func() {
+			Expect(result).NotTo(BeNil())
+			Expect(result.message).To(Equal("Waiting for all operator instances to agree on the database configuration"))
+			Expect(adminClient.DatabaseConfiguration.RedundancyMode).To(Equal(fdbtypes.RedundancyModeDouble))
+		})
+	})
+
+	When("enabling the perpetual storage wiggle", func() {
+		BeforeEach(func() {
+			cluster.Spec.Version = fdbtypes.Versions.WithPerpetualStorageWiggle.String()
+			cluster.Status.RunningVersion = cluster.Spec.Version
+			cluster.Spec.DatabaseConfiguration.PerpetualStorageWiggle = pointer.Int(1)
+		})
+
+		JustBeforeEach(func() {
+			result = updateDatabaseConfiguration{}.reconcile(context.TODO(), clusterReconciler, cluster)
+		})
+
+		It("should enable the wiggle with the gradual storage migration", func() {
+			Expect(result).To(BeNil())
+			Expect(adminClient.DatabaseConfiguration.PerpetualStorageWiggle).To(Equal(pointer.Int(1)))
+			Expect(adminClient.DatabaseConfiguration.StorageMigrationType).NotTo(BeNil())
+			Expect(*adminClient.DatabaseConfiguration.StorageMigrationType).To(Equal(fdbtypes.StorageMigrationTypeGradual))
+		})
+
+		When("pausing the wiggle outside of maintenance windows", func() {
+			var now time.Time
+
+			BeforeEach(func() {
+				now = time.Date(2021, time.June, 1, 12, 0, 0, 0, time.UTC)
+				clusterReconciler.clock = func() time.Time {
+					return now
+				}
+				cluster.Spec.AutomationOptions.StorageWiggle.PauseOutsideMaintenanceWindows = pointer.Bool(true)
+			})
+
+			AfterEach(func() {
+				clusterReconciler.clock = nil
+			})
+
+			When("the cluster is outside of its maintenance windows", func() {
+				BeforeEach(func() {
+					cluster.Spec.MaintenanceWindows = []fdbtypes.MaintenanceWindow{{
+						Schedule: "0 1 * * *",
+						Duration: metav1.Duration{Duration: time.Hour},
+					}}
+				})
+
+				It("should pause the wiggle until the next maintenance window", func() {
+					Expect(result).NotTo(BeNil())
+					Expect(result.scheduled).To(BeTrue())
+					Expect(result.delayedRequeue).To(BeFalse())
+					Expect(result.delay).To(Equal(13 * time.Hour))
+					Expect(result.message).To(Equal("Resuming storage wiggle when the next maintenance window opens at 2021-06-02T01:00:00Z"))
+					Expect(adminClient.DatabaseConfiguration.PerpetualStorageWiggle).To(Equal(pointer.Int(0)))
+				})
+
+				It("should consider the paused wiggle reconciled", func() {
+					Expect(updateStatus{}.reconcile(context.TODO(), clusterReconciler, cluster)).To(BeNil())
+					Expect(cluster.Status.DatabaseConfiguration).To(Equal(cluster.DesiredDatabaseConfiguration()))
+				})
+			})
+
+			When("the cluster is inside of its maintenance window", func() {
+				BeforeEach(func() {
+					cluster.Spec.MaintenanceWindows = []fdbtypes.MaintenanceWindow{{
+						Schedule: "0 11 * * *",
+						Duration: metav1.Duration{Duration: 2 * time.Hour},
+					}}
+				})
+
+				It("should run the wiggle until the maintenance window closes", func() {
+					Expect(result).NotTo(BeNil())
+					Expect(result.scheduled).To(BeTrue())
+					Expect(result.delayedRequeue).To(BeFalse())
+					Expect(result.delay).To(Equal(time.Hour))
+					Expect(result.message).To(Equal("Pausing storage wiggle when the maintenance window closes at 2021-06-01T13:00:00Z"))
+					Expect(adminClient.DatabaseConfiguration.PerpetualStorageWiggle).To(Equal(pointer.Int(1)))
+				})
+			})
+
+			When("the cluster has no maintenance windows", func() {
+				It("should run the wiggle without a requeue", func() {
+					Expect(result).To(BeNil())
+					Expect(adminClient.DatabaseConfiguration.PerpetualStorageWiggle).To(Equal(pointer.Int(1)))
+				})
+			})
+		})
+	})
+})
+
+var _ = Describe("reconciling a cluster that pauses the storage wiggle", func() {
+	var cluster *fdbtypes.FoundationDBCluster
+	var result reconcile.Result
+
+	BeforeEach(func() {
+		now := time.Date(2021, time.June, 1, 12, 0, 0, 0, time.UTC)
+		clusterReconciler.clock = func() time.Time {
+			return now
+		}
+
+		cluster = internal.CreateDefaultCluster()
+		cluster.Spec.Version = fdbtypes.Versions.WithPerpetualStorageWiggle.String()
+		cluster.Spec.DatabaseConfiguration.PerpetualStorageWiggle = pointer.Int(1)
+		cluster.Spec.AutomationOptions.StorageWiggle.PauseOutsideMaintenanceWindows = pointer.Bool(true)
+		cluster.Spec.MaintenanceWindows = []fdbtypes.MaintenanceWindow{{
+			Schedule: "0 1 * * *",
+			Duration: metav1.Duration{Duration: time.Hour},
+		}}
+		err := setupClusterForTest(cluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		result, err = reconcileCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		clusterReconciler.clock = nil
+	})
+
+	It("should complete the reconciliation and requeue when the maintenance window opens", func() {
+		Expect(result.Requeue).To(BeFalse())
+		Expect(result.RequeueAfter).To(Equal(13 * time.Hour))
+
+		generations, err := reloadClusterGenerations(cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(generations.Reconciled).To(Equal(cluster.ObjectMeta.Generation))
+	})
+
+	It("should not record the scheduled requeue", func() {
+		_, err := reloadClusterGenerations(cluster)
+		Expect(err).NotTo(HaveOccurred())
+		for _, entry := range cluster.Status.ReconciliationHistory {
+			Expect(entry.SubReconciler).NotTo(Equal("controllers.updateDatabaseConfiguration"))
+		}
+	})
+})
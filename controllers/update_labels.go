@@ -75,7 +75,7 @@ func (updateLabels) reconcile(ctx context.Context, r *FoundationDBClusterReconci
 		}
 
 		// We can skip all stateless processes because they won't have a PVC attached.
-		if !processGroup.ProcessClass.IsStateful() {
+		if !processGroup.ProcessClass.IsStateful() && !cluster.IsDedicatedCoordinator(processGroup.ProcessClass) {
 			continue
 		}
 
@@ -22,8 +22,11 @@ package controllers
 
 import (
 	"context"
+	"time"
 
 	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
 )
 
 // updateLockConfiguration reconciles the state of the locking system in the
@@ -32,7 +35,7 @@ type updateLockConfiguration struct{}
 
 // reconcile runs the reconciler's work.
 func (updateLockConfiguration) reconcile(_ context.Context, r *FoundationDBClusterReconciler, cluster *fdbtypes.FoundationDBCluster) *requeue {
-	if len(cluster.Spec.LockOptions.DenyList) == 0 || !cluster.ShouldUseLocks() || !cluster.Status.Configured {
+	if !cluster.ShouldUseLocks() || !cluster.Status.Configured {
 		return nil
 	}
 
@@ -41,10 +44,99 @@ func (updateLockConfiguration) reconcile(_ context.Context, r *FoundationDBClust
 		return &requeue{curError: err}
 	}
 
-	err = lockClient.UpdateDenyList(cluster.Spec.LockOptions.DenyList)
+	if len(cluster.Spec.LockOptions.DenyList) > 0 {
+		err = lockClient.UpdateDenyList(cluster.Spec.LockOptions.DenyList)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+	}
+
+	err = registerOperatorInstance(r, cluster, lockClient)
 	if err != nil {
 		return &requeue{curError: err}
 	}
 
 	return nil
 }
+
+// registerOperatorInstance registers this instance of the operator in the
+// database, with the database configuration it wants to apply. The
+// registration is refreshed when the configuration changes or when half of
+// the lock duration has passed, so the other instances know it's active.
+func registerOperatorInstance(r *FoundationDBClusterReconciler, cluster *fdbtypes.FoundationDBCluster, lockClient fdbadminclient.LockClient) error {
+	configurationHash, err := getDatabaseConfigurationHash(cluster)
+	if err != nil {
+		return err
+	}
+
+	instances, err := lockClient.GetOperatorInstances()
+	if err != nil {
+		return err
+	}
+
+	now := r.now()
+	for _, instance := range instances {
+		if instance.ID != cluster.GetLockID() {
+			continue
+		}
+
+		if instance.DataCenter == cluster.Spec.DataCenter && instance.DatabaseConfigurationHash == configurationHash &&
+			now.Sub(time.Unix(instance.Timestamp, 0)) < cluster.GetLockDuration()/2 {
+			return nil
+		}
+	}
+
+	return lockClient.UpdateOperatorInstance(fdbtypes.OperatorInstance{
+		ID:                        cluster.GetLockID(),
+		DataCenter:                cluster.Spec.DataCenter,
+		DatabaseConfigurationHash: configurationHash,
+		Timestamp:                 now.Unix(),
+	})
+}
+
+// getDatabaseConfigurationHash provides a hash of the database configuration
+// from the cluster spec, which the operator instances compare before they
+// change the configuration.
+func getDatabaseConfigurationHash(cluster *fdbtypes.FoundationDBCluster) (string, error) {
+	configuration := cluster.DesiredDatabaseConfiguration()
+	configuration.RoleCounts.Storage = 0
+	return internal.GetJSONHash(configuration)
+}
+
+// getDisagreeingOperatorInstances provides the IDs of the other active
+// operator instances that want to apply a different database configuration
+// than this instance.
+func getDisagreeingOperatorInstances(r *FoundationDBClusterReconciler, cluster *fdbtypes.FoundationDBCluster) ([]string, error) {
+	if !cluster.ShouldUseLocks() {
+		return nil, nil
+	}
+
+	configurationHash, err := getDatabaseConfigurationHash(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	lockClient, err := r.getLockClient(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	instances, err := lockClient.GetOperatorInstances()
+	if err != nil {
+		return nil, err
+	}
+
+	now := r.now()
+	var disagreeing []string
+	for _, instance := range instances {
+		if instance.ID == cluster.GetLockID() || !instance.IsActive(cluster, now) {
+			continue
+		}
+
+		if instance.DatabaseConfigurationHash != configurationHash {
+			disagreeing = append(disagreeing, instance.ID)
+		}
+	}
+
+	return disagreeing, nil
+}
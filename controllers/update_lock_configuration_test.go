@@ -22,6 +22,7 @@ package controllers
 
 import (
 	"context"
+	"time"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
 
@@ -114,4 +115,52 @@ var _ = Describe("update_lock_configuration", func() {
 			Expect(list).To(Equal([]string{"dc3"}))
 		})
 	})
+
+	Context("with the registration of the operator instance", func() {
+		It("should register the instance with its database configuration", func() {
+			configurationHash, err := getDatabaseConfigurationHash(cluster)
+			Expect(err).NotTo(HaveOccurred())
+
+			instances, err := lockClient.GetOperatorInstances()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(instances).To(HaveLen(1))
+			Expect(instances[0].ID).To(Equal(cluster.GetLockID()))
+			Expect(instances[0].DatabaseConfigurationHash).To(Equal(configurationHash))
+			Expect(instances[0].IsActive(cluster, time.Now())).To(BeTrue())
+		})
+
+		When("another instance wants a different configuration", func() {
+			BeforeEach(func() {
+				err = lockClient.UpdateOperatorInstance(fdbtypes.OperatorInstance{
+					ID:                        "dc2",
+					DatabaseConfigurationHash: "other",
+					Timestamp:                 time.Now().Unix(),
+				})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should report the other instance", func() {
+				disagreeing, err := getDisagreeingOperatorInstances(clusterReconciler, cluster)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(disagreeing).To(Equal([]string{"dc2"}))
+			})
+		})
+
+		When("an inactive instance wants a different configuration", func() {
+			BeforeEach(func() {
+				err = lockClient.UpdateOperatorInstance(fdbtypes.OperatorInstance{
+					ID:                        "dc2",
+					DatabaseConfigurationHash: "other",
+					Timestamp:                 time.Now().Add(-1 * cluster.GetLockDuration()).Unix(),
+				})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should ignore the other instance", func() {
+				disagreeing, err := getDisagreeingOperatorInstances(clusterReconciler, cluster)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(disagreeing).To(BeEmpty())
+			})
+		})
+	})
 })
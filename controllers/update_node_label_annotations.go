@@ -0,0 +1,98 @@
+/*
+ * update_node_label_annotations.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2021 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// updateNodeLabelAnnotations provides a reconciliation step for copying the
+// node labels that substitution variables read from to the annotations of the
+// pods.
+//
+// The pods wait in an init container until the annotations are present, so
+// this has to run before any reconciler that waits for the pods to start.
+type updateNodeLabelAnnotations struct{}
+
+// reconcile runs the reconciler's work.
+func (updateNodeLabelAnnotations) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbtypes.FoundationDBCluster) *requeue {
+	if !internal.HasNodeLabelSubstitutionVariables(cluster) {
+		return nil
+	}
+
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "updateNodeLabelAnnotations")
+
+	pods, err := r.PodLifecycleManager.GetPods(ctx, r, cluster, internal.GetPodListOptions(cluster, "", "")...)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" || pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		// The nodes are read without the cache, so that the operator doesn't
+		// need a cluster-wide watch for all nodes.
+		node := &corev1.Node{}
+		err = r.NodeReader.Get(ctx, client.ObjectKey{Name: pod.Spec.NodeName}, node)
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				logger.Info("Could not find node for Pod", "pod", pod.Name, "node", pod.Spec.NodeName)
+				continue
+			}
+			return &requeue{curError: err}
+		}
+
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+
+		changed := false
+		for key, value := range internal.GetNodeLabelAnnotations(cluster, node) {
+			current, present := pod.Annotations[key]
+			if present && current == value {
+				continue
+			}
+
+			pod.Annotations[key] = value
+			changed = true
+		}
+
+		if !changed {
+			continue
+		}
+
+		logger.Info("Update node label annotations", "pod", pod.Name)
+		err = r.PodLifecycleManager.UpdateMetadata(ctx, r, cluster, pod)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+/*
+ * update_node_label_annotations_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2021 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("update_node_label_annotations", func() {
+	var cluster *fdbtypes.FoundationDBCluster
+	var node *corev1.Node
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		err := setupClusterForTest(cluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		node = &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"topology.example.com/rack": "rack-1"}},
+		}
+		Expect(k8sClient.Create(context.TODO(), node)).NotTo(HaveOccurred())
+
+		pods, err := clusterReconciler.PodLifecycleManager.GetPods(context.TODO(), clusterReconciler, cluster, internal.GetSinglePodListOptions(cluster, "storage-1")...)
+		Expect(err).NotTo(HaveOccurred())
+		pod := pods[0]
+		pod.Spec.NodeName = node.Name
+		Expect(k8sClient.Update(context.TODO(), pod)).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(k8sClient.Delete(context.TODO(), node)).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		Expect(updateNodeLabelAnnotations{}.reconcile(context.TODO(), clusterReconciler, cluster)).To(BeNil())
+	})
+
+	getAnnotations := func(processGroupID string) map[string]string {
+		pods, err := clusterReconciler.PodLifecycleManager.GetPods(context.TODO(), clusterReconciler, cluster, internal.GetSinglePodListOptions(cluster, processGroupID)...)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pods).To(HaveLen(1))
+		return pods[0].Annotations
+	}
+
+	When("a substitution variable reads a node label", func() {
+		BeforeEach(func() {
+			cluster.Spec.SubstitutionVariables = []fdbtypes.SubstitutionVariable{
+				{Name: "FDB_RACK", NodeLabel: "topology.example.com/rack"},
+			}
+		})
+
+		It("should copy the label to the pod annotations", func() {
+			Expect(getAnnotations("storage-1")).To(HaveKeyWithValue("foundationdb.org/substitution-fdb-rack", "rack-1"))
+		})
+
+		It("should not annotate pods that are not scheduled", func() {
+			Expect(getAnnotations("storage-2")).NotTo(HaveKey("foundationdb.org/substitution-fdb-rack"))
+		})
+	})
+
+	When("the node doesn't have the label", func() {
+		BeforeEach(func() {
+			cluster.Spec.SubstitutionVariables = []fdbtypes.SubstitutionVariable{
+				{Name: "FDB_ROW", NodeLabel: "topology.example.com/row"},
+			}
+		})
+
+		It("should set an empty annotation", func() {
+			Expect(getAnnotations("storage-1")).To(HaveKeyWithValue("foundationdb.org/substitution-fdb-row", ""))
+		})
+	})
+
+	When("no substitution variable reads a node label", func() {
+		It("should not change the pod annotations", func() {
+			Expect(getAnnotations("storage-1")).NotTo(HaveKey(HavePrefix(fdbtypes.SubstitutionVariableAnnotationPrefix)))
+		})
+	})
+})
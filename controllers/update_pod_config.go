@@ -22,6 +22,7 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -51,8 +52,16 @@ func (updatePodConfig) reconcile(ctx context.Context, r *FoundationDBClusterReco
 
 	podMap := internal.CreatePodMap(cluster, pods)
 
+	tlsCertificateData, err := r.getTLSCertificateData(ctx, cluster)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
 	allSynced := true
 	hasUpdate := false
+	// Pods without values for substitution variables don't block the
+	// following reconcilers, since those are needed to provide the values.
+	missingSubstitutions := false
 	var errs []error
 	// We try to update all process groups and if we observe an error we add it to the error list.
 	for _, processGroup := range cluster.Status.ProcessGroups {
@@ -74,23 +83,23 @@ func (updatePodConfig) reconcile(ctx context.Context, r *FoundationDBClusterReco
 			continue
 		}
 
-		serverPerPod, err := internal.GetStorageServersPerPodForPod(pod)
+		processClass, err := podmanager.GetProcessClass(cluster, pod)
 		if err != nil {
-			curLogger.Error(err, "Error when receiving storage server per Pod")
+			curLogger.Error(err, "Error when fetching process class from Pod")
 			errs = append(errs, err)
 			continue
 		}
 
-		processClass, err := podmanager.GetProcessClass(cluster, pod)
+		serverPerPod, err := internal.GetServersPerPodForPod(pod, processClass)
 		if err != nil {
-			curLogger.Error(err, "Error when fetching process class from Pod")
+			curLogger.Error(err, "Error when receiving servers per Pod")
 			errs = append(errs, err)
 			continue
 		}
 
 		imageType := internal.GetImageType(pod)
 
-		configMapHash, err := internal.GetDynamicConfHash(configMap, processClass, imageType, serverPerPod)
+		configMapHash, err := internal.GetDynamicConfHash(configMap, processClass, processGroup.ProcessGroupID, imageType, serverPerPod)
 		if err != nil {
 			curLogger.Error(err, "Error when receiving dynamic ConfigMap hash")
 			errs = append(errs, err)
@@ -101,9 +110,13 @@ func (updatePodConfig) reconcile(ctx context.Context, r *FoundationDBClusterReco
 			continue
 		}
 
-		synced, err := r.updatePodDynamicConf(cluster, pod)
+		synced, err := r.updatePodDynamicConf(cluster, pod, tlsCertificateData)
 		if !synced {
-			allSynced = false
+			if errors.As(err, &missingSubstitutionVariablesError{}) {
+				missingSubstitutions = true
+			} else {
+				allSynced = false
+			}
 			hasUpdate = true
 			if err != nil {
 				curLogger.Error(err, "Update Pod ConfigMap annotation")
@@ -156,5 +169,9 @@ func (updatePodConfig) reconcile(ctx context.Context, r *FoundationDBClusterReco
 		return &requeue{message: "Waiting for Pod to receive ConfigMap update", delay: podSchedulingDelayDuration}
 	}
 
+	if missingSubstitutions {
+		return &requeue{message: "Waiting for Pods to receive values for substitution variables", delay: podSchedulingDelayDuration, delayedRequeue: true}
+	}
+
 	return nil
 }
@@ -63,6 +63,23 @@ var _ = Describe("updatePodConfig", func() {
 		})
 	})
 
+	When("a Pod has no value for a substitution variable", func() {
+		BeforeEach(func() {
+			cluster.Spec.SubstitutionVariables = []fdbtypes.SubstitutionVariable{
+				{Name: "FDB_TEAM", PodAnnotation: "example.com/team"},
+			}
+			settings := cluster.Spec.Processes[fdbtypes.ProcessClassGeneral]
+			settings.CustomParameters = append(settings.CustomParameters, "locality_team=$FDB_TEAM")
+			cluster.Spec.Processes[fdbtypes.ProcessClassGeneral] = settings
+		})
+
+		It("should delay the requeue without blocking the reconciliation", func() {
+			Expect(requeue).NotTo(BeNil())
+			Expect(requeue.delayedRequeue).To(BeTrue())
+			Expect(requeue.message).To(Equal("Waiting for Pods to receive values for substitution variables"))
+		})
+	})
+
 	When("a Pod is stuck in Pending", func() {
 		BeforeEach(func() {
 			pods[0].Status.Phase = corev1.PodPending
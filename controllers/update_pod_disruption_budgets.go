@@ -0,0 +1,247 @@
+/*
+ * update_pod_disruption_budgets.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2021 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"sort"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/podmanager"
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// updatePodDisruptionBudgets provides a reconciliation step for managing the
+// PodDisruptionBudgets of a cluster.
+type updatePodDisruptionBudgets struct{}
+
+// reconcile runs the reconciler's work.
+func (updatePodDisruptionBudgets) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbtypes.FoundationDBCluster) *requeue {
+	if !cluster.GetManagePodDisruptionBudgets() {
+		return nil
+	}
+
+	err := reconcilePodDisruptionBudgets(ctx, r, cluster, nil)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	return nil
+}
+
+// reconcilePodDisruptionBudgets creates and updates the PodDisruptionBudgets
+// for the process classes of a cluster and deletes any other budgets that the
+// operator created for the cluster. The budgets of the restricted process
+// classes don't allow any disruptions. A budget that was restricted before
+// stays restricted until the pods of its process class have been recreated.
+func reconcilePodDisruptionBudgets(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbtypes.FoundationDBCluster, restrictedClasses map[fdbtypes.ProcessClass]bool) error {
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "updatePodDisruptionBudgets")
+
+	recreatingClasses, err := getRecreatingProcessClasses(ctx, r, cluster)
+	if err != nil {
+		return err
+	}
+
+	pdbs, err := r.listPodDisruptionBudgets(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	existingPdbs := make(map[string]*policyv1.PodDisruptionBudget, len(pdbs))
+	for index, pdb := range pdbs {
+		existingPdbs[pdb.Name] = &pdbs[index]
+	}
+
+	desiredNames := make(map[string]bool)
+	for _, processClass := range getPodDisruptionBudgetProcessClasses(cluster) {
+		name := internal.GetPodDisruptionBudgetName(cluster, processClass)
+		desiredNames[name] = true
+		existing := existingPdbs[name]
+
+		maxUnavailable := internal.GetPodDisruptionBudgetMaxUnavailable(cluster)
+		if restrictedClasses[processClass] || (existing != nil && recreatingClasses[processClass] && isRestrictedPodDisruptionBudget(existing)) {
+			maxUnavailable = intstr.FromInt(0)
+		}
+
+		pdb := internal.GetPodDisruptionBudget(cluster, processClass, maxUnavailable)
+		if existing == nil {
+			logger.Info("Creating PodDisruptionBudget", "name", name, "maxUnavailable", maxUnavailable.String())
+			err = r.Create(ctx, r.podDisruptionBudgetObject(pdb))
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		needsUpdate := !equality.Semantic.DeepEqual(existing.Spec, pdb.Spec)
+		if mergeLabelsInMetadata(&existing.ObjectMeta, pdb.ObjectMeta) {
+			needsUpdate = true
+		}
+
+		if needsUpdate {
+			logger.Info("Updating PodDisruptionBudget", "name", name, "maxUnavailable", maxUnavailable.String())
+			existing.Spec = pdb.Spec
+			err = r.Update(ctx, r.podDisruptionBudgetObject(existing))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Budgets that overlap with the budgets of the process classes would
+	// block all evictions, e.g. a budget for the whole cluster, and budgets
+	// for process classes that are no longer used are not needed.
+	for index, pdb := range pdbs {
+		if desiredNames[pdb.Name] || !metav1.IsControlledBy(&pdb, cluster) {
+			continue
+		}
+
+		logger.Info("Deleting PodDisruptionBudget", "name", pdb.Name)
+		err = r.Delete(ctx, r.podDisruptionBudgetObject(&pdbs[index]))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getPodDisruptionBudgetProcessClasses returns the process classes that the
+// cluster runs pods for, sorted by name.
+func getPodDisruptionBudgetProcessClasses(cluster *fdbtypes.FoundationDBCluster) []fdbtypes.ProcessClass {
+	classes := make(map[fdbtypes.ProcessClass]bool)
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		classes[processGroup.ProcessClass] = true
+	}
+
+	result := make([]fdbtypes.ProcessClass, 0, len(classes))
+	for processClass := range classes {
+		result = append(result, processClass)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i] < result[j]
+	})
+
+	return result
+}
+
+// getRecreatingProcessClasses returns the process classes that have pods
+// which are terminating or which have not been recreated and started yet.
+func getRecreatingProcessClasses(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbtypes.FoundationDBCluster) (map[fdbtypes.ProcessClass]bool, error) {
+	recreating := make(map[fdbtypes.ProcessClass]bool)
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if processGroup.IsMarkedForRemoval() {
+			continue
+		}
+
+		for _, conditionType := range []fdbtypes.ProcessGroupConditionType{fdbtypes.MissingPod, fdbtypes.PodPending, fdbtypes.MissingProcesses} {
+			if processGroup.GetConditionTime(conditionType) != nil {
+				recreating[processGroup.ProcessClass] = true
+			}
+		}
+	}
+
+	pods, err := r.PodLifecycleManager.GetPods(ctx, r, cluster, internal.GetPodListOptions(cluster, "", "")...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pod := range pods {
+		if pod.DeletionTimestamp == nil {
+			continue
+		}
+
+		processClass, err := podmanager.GetProcessClass(cluster, pod)
+		if err != nil {
+			return nil, err
+		}
+		recreating[processClass] = true
+	}
+
+	return recreating, nil
+}
+
+// listPodDisruptionBudgets lists the PodDisruptionBudgets that match the
+// labels of a cluster. The budgets are always returned as policy/v1 objects,
+// even if the reconciler uses policy/v1beta1.
+func (r *FoundationDBClusterReconciler) listPodDisruptionBudgets(ctx context.Context, cluster *fdbtypes.FoundationDBCluster) ([]policyv1.PodDisruptionBudget, error) {
+	options := []client.ListOption{client.InNamespace(cluster.Namespace), client.MatchingLabels(cluster.Spec.LabelConfig.MatchLabels)}
+
+	if r.UsePolicyV1PodDisruptionBudgets {
+		pdbs := &policyv1.PodDisruptionBudgetList{}
+		err := r.List(ctx, pdbs, options...)
+		if err != nil {
+			return nil, err
+		}
+
+		return pdbs.Items, nil
+	}
+
+	pdbs := &policyv1beta1.PodDisruptionBudgetList{}
+	err := r.List(ctx, pdbs, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]policyv1.PodDisruptionBudget, 0, len(pdbs.Items))
+	for _, pdb := range pdbs.Items {
+		result = append(result, policyv1.PodDisruptionBudget{
+			ObjectMeta: pdb.ObjectMeta,
+			Spec: policyv1.PodDisruptionBudgetSpec{
+				MinAvailable:   pdb.Spec.MinAvailable,
+				MaxUnavailable: pdb.Spec.MaxUnavailable,
+				Selector:       pdb.Spec.Selector,
+			},
+		})
+	}
+
+	return result, nil
+}
+
+// podDisruptionBudgetObject converts a PodDisruptionBudget into the API
+// version that the reconciler uses.
+func (r *FoundationDBClusterReconciler) podDisruptionBudgetObject(pdb *policyv1.PodDisruptionBudget) client.Object {
+	if r.UsePolicyV1PodDisruptionBudgets {
+		return pdb
+	}
+
+	return &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: pdb.ObjectMeta,
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			MinAvailable:   pdb.Spec.MinAvailable,
+			MaxUnavailable: pdb.Spec.MaxUnavailable,
+			Selector:       pdb.Spec.Selector,
+		},
+	}
+}
+
+// isRestrictedPodDisruptionBudget determines if a PodDisruptionBudget allows
+// no disruptions at all.
+func isRestrictedPodDisruptionBudget(pdb *policyv1.PodDisruptionBudget) bool {
+	maxUnavailable := pdb.Spec.MaxUnavailable
+	return maxUnavailable != nil && maxUnavailable.Type == intstr.Int && maxUnavailable.IntVal == 0
+}
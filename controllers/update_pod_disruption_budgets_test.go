@@ -0,0 +1,199 @@
+/*
+ * update_pod_disruption_budgets_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2021 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("updatePodDisruptionBudgets", func() {
+	var cluster *fdbtypes.FoundationDBCluster
+	var result *requeue
+
+	getPodDisruptionBudgets := func() map[string]policyv1.PodDisruptionBudget {
+		pdbs := &policyv1.PodDisruptionBudgetList{}
+		err := k8sClient.List(context.TODO(), pdbs, client.InNamespace(cluster.Namespace))
+		Expect(err).NotTo(HaveOccurred())
+
+		result := make(map[string]policyv1.PodDisruptionBudget, len(pdbs.Items))
+		for _, pdb := range pdbs.Items {
+			result[pdb.Name] = pdb
+		}
+
+		return result
+	}
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		err := setupClusterForTest(cluster)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		result = updatePodDisruptionBudgets{}.reconcile(context.TODO(), clusterReconciler, cluster)
+	})
+
+	When("the management of PodDisruptionBudgets is disabled", func() {
+		It("should not create any PodDisruptionBudgets", func() {
+			Expect(result).To(BeNil())
+			Expect(getPodDisruptionBudgets()).To(BeEmpty())
+		})
+	})
+
+	When("the management of PodDisruptionBudgets is enabled", func() {
+		BeforeEach(func() {
+			cluster.Spec.AutomationOptions.ManagePodDisruptionBudgets = pointer.Bool(true)
+		})
+
+		It("should create a PodDisruptionBudget for every process class", func() {
+			Expect(result).To(BeNil())
+
+			pdbs := getPodDisruptionBudgets()
+			Expect(pdbs).To(HaveLen(4))
+			for _, processClass := range []fdbtypes.ProcessClass{fdbtypes.ProcessClassStorage, fdbtypes.ProcessClassLog, fdbtypes.ProcessClassStateless, fdbtypes.ProcessClassClusterController} {
+				pdb, ok := pdbs[internal.GetPodDisruptionBudgetName(cluster, processClass)]
+				Expect(ok).To(BeTrue())
+				Expect(*pdb.Spec.MaxUnavailable).To(Equal(intstr.FromInt(1)))
+				Expect(pdb.Spec.Selector.MatchLabels).To(Equal(internal.GetPodMatchLabels(cluster, processClass, "")))
+			}
+		})
+
+		When("the redundancy mode is changed", func() {
+			JustBeforeEach(func() {
+				Expect(result).To(BeNil())
+				cluster.Spec.DatabaseConfiguration.RedundancyMode = fdbtypes.RedundancyModeTriple
+				result = updatePodDisruptionBudgets{}.reconcile(context.TODO(), clusterReconciler, cluster)
+			})
+
+			It("should update the PodDisruptionBudgets", func() {
+				Expect(result).To(BeNil())
+				pdb := getPodDisruptionBudgets()[internal.GetPodDisruptionBudgetName(cluster, fdbtypes.ProcessClassStorage)]
+				Expect(*pdb.Spec.MaxUnavailable).To(Equal(intstr.FromInt(2)))
+			})
+		})
+
+		When("a PodDisruptionBudget for the whole cluster exists", func() {
+			BeforeEach(func() {
+				pdb := internal.GetPodDisruptionBudget(cluster, fdbtypes.ProcessClassStorage, intstr.FromInt(1))
+				pdb.Name = cluster.Name
+				pdb.Spec.Selector.MatchLabels = internal.GetPodMatchLabels(cluster, "", "")
+				err := k8sClient.Create(context.TODO(), pdb)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should delete the PodDisruptionBudget for the whole cluster", func() {
+				Expect(result).To(BeNil())
+				pdbs := getPodDisruptionBudgets()
+				Expect(pdbs).To(HaveLen(4))
+				Expect(pdbs).NotTo(HaveKey(cluster.Name))
+			})
+		})
+
+		When("the pods of a process class are being updated", func() {
+			JustBeforeEach(func() {
+				Expect(result).To(BeNil())
+				err := reconcilePodDisruptionBudgets(context.TODO(), clusterReconciler, cluster, map[fdbtypes.ProcessClass]bool{fdbtypes.ProcessClassStorage: true})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should not allow any disruptions for the process class", func() {
+				pdbs := getPodDisruptionBudgets()
+				Expect(*pdbs[internal.GetPodDisruptionBudgetName(cluster, fdbtypes.ProcessClassStorage)].Spec.MaxUnavailable).To(Equal(intstr.FromInt(0)))
+				Expect(*pdbs[internal.GetPodDisruptionBudgetName(cluster, fdbtypes.ProcessClassLog)].Spec.MaxUnavailable).To(Equal(intstr.FromInt(1)))
+			})
+
+			When("a pod of the process class is still being recreated", func() {
+				JustBeforeEach(func() {
+					processGroup := fdbtypes.FindProcessGroupByID(cluster.Status.ProcessGroups, "storage-1")
+					processGroup.UpdateCondition(fdbtypes.MissingProcesses, true, nil, "")
+					result = updatePodDisruptionBudgets{}.reconcile(context.TODO(), clusterReconciler, cluster)
+				})
+
+				It("should keep the restriction", func() {
+					Expect(result).To(BeNil())
+					pdb := getPodDisruptionBudgets()[internal.GetPodDisruptionBudgetName(cluster, fdbtypes.ProcessClassStorage)]
+					Expect(*pdb.Spec.MaxUnavailable).To(Equal(intstr.FromInt(0)))
+				})
+			})
+
+			When("the deletion of the remaining pods is deferred", func() {
+				JustBeforeEach(func() {
+					processGroup := fdbtypes.FindProcessGroupByID(cluster.Status.ProcessGroups, "storage-1")
+					processGroup.UpdateCondition(fdbtypes.IncorrectPodSpec, true, nil, "")
+					result = updatePodDisruptionBudgets{}.reconcile(context.TODO(), clusterReconciler, cluster)
+				})
+
+				It("should lift the restriction", func() {
+					Expect(result).To(BeNil())
+					pdb := getPodDisruptionBudgets()[internal.GetPodDisruptionBudgetName(cluster, fdbtypes.ProcessClassStorage)]
+					Expect(*pdb.Spec.MaxUnavailable).To(Equal(intstr.FromInt(1)))
+				})
+			})
+
+			When("the pods have been recreated", func() {
+				JustBeforeEach(func() {
+					result = updatePodDisruptionBudgets{}.reconcile(context.TODO(), clusterReconciler, cluster)
+				})
+
+				It("should lift the restriction", func() {
+					Expect(result).To(BeNil())
+					pdb := getPodDisruptionBudgets()[internal.GetPodDisruptionBudgetName(cluster, fdbtypes.ProcessClassStorage)]
+					Expect(*pdb.Spec.MaxUnavailable).To(Equal(intstr.FromInt(1)))
+				})
+			})
+		})
+
+		When("the API server only serves policy/v1beta1", func() {
+			var reconciler *FoundationDBClusterReconciler
+
+			BeforeEach(func() {
+				reconciler = createTestClusterReconciler()
+				reconciler.UsePolicyV1PodDisruptionBudgets = false
+			})
+
+			JustBeforeEach(func() {
+				result = updatePodDisruptionBudgets{}.reconcile(context.TODO(), reconciler, cluster)
+			})
+
+			It("should create the PodDisruptionBudget in policy/v1beta1", func() {
+				Expect(result).To(BeNil())
+
+				pdbs := &policyv1beta1.PodDisruptionBudgetList{}
+				err := k8sClient.List(context.TODO(), pdbs, client.InNamespace(cluster.Namespace))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pdbs.Items).To(HaveLen(4))
+				for _, pdb := range pdbs.Items {
+					Expect(*pdb.Spec.MaxUnavailable).To(Equal(intstr.FromInt(1)))
+				}
+			})
+		})
+	})
+})
@@ -23,6 +23,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
 	"github.com/go-logr/logr"
@@ -92,18 +93,21 @@ func (updatePods) reconcile(ctx context.Context, r *FoundationDBClusterReconcile
 			return &requeue{curError: err}
 		}
 
-		if pod.ObjectMeta.Annotations[fdbtypes.LastSpecKey] != specHash {
-			logger.Info("Update Pod",
-				"processGroupID", processGroup.ProcessGroupID,
-				"reason", fmt.Sprintf("specHash has changed from %s to %s", specHash, pod.ObjectMeta.Annotations[fdbtypes.LastSpecKey]))
-
+		specHashChanged := pod.ObjectMeta.Annotations[fdbtypes.LastSpecKey] != specHash
+		if specHashChanged || internal.HasAnnotationSubstitutionVariables(cluster) {
 			podClient, message := r.getPodClient(cluster, pod)
 			if podClient == nil {
+				if !specHashChanged {
+					continue
+				}
 				return &requeue{message: message, delay: podSchedulingDelayDuration}
 			}
 
 			substitutions, err := podClient.GetVariableSubstitutions()
 			if err != nil {
+				if !specHashChanged {
+					continue
+				}
 				return &requeue{curError: err}
 			}
 
@@ -113,6 +117,23 @@ func (updatePods) reconcile(ctx context.Context, r *FoundationDBClusterReconcile
 				continue
 			}
 
+			if specHashChanged {
+				logger.Info("Update Pod",
+					"processGroupID", processGroup.ProcessGroupID,
+					"reason", fmt.Sprintf("specHash has changed from %s to %s", specHash, pod.ObjectMeta.Annotations[fdbtypes.LastSpecKey]))
+			} else {
+				// The containers only read the annotations when they start,
+				// so the pod has to be recreated to pick up the new values.
+				stale := internal.GetStaleSubstitutionVariables(cluster, pod, substitutions)
+				if len(stale) == 0 {
+					continue
+				}
+
+				logger.Info("Update Pod",
+					"processGroupID", processGroup.ProcessGroupID,
+					"reason", fmt.Sprintf("annotations for substitution variables have changed: %s", strings.Join(stale, ", ")))
+			}
+
 			zone := substitutions["FDB_ZONE_ID"]
 			if r.InSimulation {
 				zone = "simulation"
@@ -141,6 +162,11 @@ func (updatePods) reconcile(ctx context.Context, r *FoundationDBClusterReconcile
 			}
 			return &requeue{message: "Pod deletion is disabled"}
 		}
+
+		req := checkMaintenanceWindow(cluster, "pod updates")
+		if req != nil {
+			return req
+		}
 	}
 
 	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r.Client)
@@ -214,6 +240,24 @@ func deletePodsForUpdates(ctx context.Context, r *FoundationDBClusterReconciler,
 		}
 	}
 
+	if cluster.GetManagePodDisruptionBudgets() {
+		// Don't allow any further disruptions for the affected process
+		// classes while we recreate the pods.
+		restrictedClasses := make(map[fdbtypes.ProcessClass]bool)
+		for _, pod := range deletions {
+			processClass, err := podmanager.GetProcessClass(cluster, pod)
+			if err != nil {
+				return &requeue{curError: err}
+			}
+			restrictedClasses[processClass] = true
+		}
+
+		err = reconcilePodDisruptionBudgets(ctx, r, cluster, restrictedClasses)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+	}
+
 	logger.Info("Deleting pods", "zone", zone, "count", len(deletions), "deletionMode", string(cluster.Spec.AutomationOptions.DeletionMode))
 	r.Recorder.Event(cluster, corev1.EventTypeNormal, "UpdatingPods", fmt.Sprintf("Recreating pods in zone %s", zone))
 
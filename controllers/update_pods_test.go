@@ -21,14 +21,23 @@
 package controllers
 
 import (
+	"context"
 	"fmt"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/podclient"
 
 	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var _ = Describe("update_pods", func() {
@@ -101,4 +110,259 @@ var _ = Describe("update_pods", func() {
 				}),
 		)
 	})
+
+	Context("When the pod spec has changed", func() {
+		var cluster *fdbtypes.FoundationDBCluster
+		var requeue *requeue
+		var originalPods corev1.PodList
+
+		BeforeEach(func() {
+			cluster = internal.CreateDefaultCluster()
+			err := setupClusterForTest(cluster)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = k8sClient.List(context.TODO(), &originalPods, internal.GetPodListOptions(cluster, "", "")...)
+			Expect(err).NotTo(HaveOccurred())
+
+			generalSettings := cluster.Spec.Processes[fdbtypes.ProcessClassGeneral]
+			for index, container := range generalSettings.PodTemplate.Spec.Containers {
+				if container.Name != "foundationdb" {
+					continue
+				}
+
+				generalSettings.PodTemplate.Spec.Containers[index].Env = append(container.Env, corev1.EnvVar{Name: "TEST_CHANGE", Value: "true"})
+			}
+		})
+
+		JustBeforeEach(func() {
+			requeue = updatePods{}.reconcile(context.TODO(), clusterReconciler, cluster)
+		})
+
+		When("the cluster is outside of its maintenance windows", func() {
+			BeforeEach(func() {
+				// The window opens in the next hour and stays open for one minute.
+				nextHour := time.Now().UTC().Add(time.Hour)
+				cluster.Spec.MaintenanceWindows = []fdbtypes.MaintenanceWindow{
+					{
+						Schedule: fmt.Sprintf("0 %d * * *", nextHour.Hour()),
+						Duration: metav1.Duration{Duration: time.Minute},
+					},
+				}
+			})
+
+			It("should delay the requeue until the next window", func() {
+				Expect(requeue).NotTo(BeNil())
+				Expect(requeue.delayedRequeue).To(BeTrue())
+				Expect(requeue.delay).To(BeNumerically(">", 0))
+				Expect(requeue.delay).To(BeNumerically("<=", time.Hour))
+				Expect(requeue.message).To(HavePrefix("Deferring pod updates until the next maintenance window at"))
+			})
+
+			It("should not delete any pods", func() {
+				pods := &corev1.PodList{}
+				err := k8sClient.List(context.TODO(), pods, internal.GetPodListOptions(cluster, "", "")...)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(pods.Items)).To(Equal(len(originalPods.Items)))
+			})
+		})
+
+		When("the cluster is inside of its maintenance windows", func() {
+			BeforeEach(func() {
+				cluster.Spec.MaintenanceWindows = []fdbtypes.MaintenanceWindow{
+					{
+						Schedule: "* * * * *",
+						Duration: metav1.Duration{Duration: time.Hour},
+					},
+				}
+			})
+
+			It("should not defer the pod updates", func() {
+				if requeue != nil {
+					Expect(requeue.message).NotTo(HavePrefix("Deferring pod updates"))
+				}
+			})
+
+			It("should delete pods", func() {
+				pods := &corev1.PodList{}
+				err := k8sClient.List(context.TODO(), pods, internal.GetPodListOptions(cluster, "", "")...)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(pods.Items)).To(BeNumerically("<", len(originalPods.Items)))
+			})
+
+			When("the PodDisruptionBudgets are managed", func() {
+				BeforeEach(func() {
+					cluster.Spec.AutomationOptions.ManagePodDisruptionBudgets = pointer.Bool(true)
+				})
+
+				It("should only restrict the PodDisruptionBudgets of the updated process classes", func() {
+					pods := &corev1.PodList{}
+					err := k8sClient.List(context.TODO(), pods, internal.GetPodListOptions(cluster, "", "")...)
+					Expect(err).NotTo(HaveOccurred())
+
+					remaining := make(map[string]bool, len(pods.Items))
+					for _, pod := range pods.Items {
+						remaining[pod.Name] = true
+					}
+
+					updatedClasses := make(map[fdbtypes.ProcessClass]bool)
+					for _, pod := range originalPods.Items {
+						if !remaining[pod.Name] {
+							updatedClasses[internal.GetProcessClassFromMeta(cluster, pod.ObjectMeta)] = true
+						}
+					}
+					Expect(updatedClasses).NotTo(BeEmpty())
+
+					pdbs := &policyv1.PodDisruptionBudgetList{}
+					err = k8sClient.List(context.TODO(), pdbs, client.InNamespace(cluster.Namespace))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(pdbs.Items).NotTo(BeEmpty())
+					for _, pdb := range pdbs.Items {
+						processClass := fdbtypes.ProcessClass(pdb.Spec.Selector.MatchLabels[cluster.GetProcessClassLabel()])
+						if updatedClasses[processClass] {
+							Expect(*pdb.Spec.MaxUnavailable).To(Equal(intstr.FromInt(0)))
+						} else {
+							Expect(*pdb.Spec.MaxUnavailable).To(Equal(intstr.FromInt(1)))
+						}
+					}
+				})
+			})
+		})
+	})
+
+	Context("When a substitution variable reads a pod annotation", func() {
+		var cluster *fdbtypes.FoundationDBCluster
+		var reconciler *FoundationDBClusterReconciler
+		var requeue *requeue
+		var pod *corev1.Pod
+		var startedPod *corev1.Pod
+
+		BeforeEach(func() {
+			cluster = internal.CreateDefaultCluster()
+			cluster.Spec.SubstitutionVariables = []fdbtypes.SubstitutionVariable{
+				{Name: "FDB_TEAM", PodAnnotation: "example.com/team"},
+			}
+			err := setupClusterForTest(cluster)
+			Expect(err).NotTo(HaveOccurred())
+
+			pods, err := clusterReconciler.PodLifecycleManager.GetPods(context.TODO(), clusterReconciler, cluster, internal.GetSinglePodListOptions(cluster, "storage-1")...)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pods).To(HaveLen(1))
+			pod = pods[0]
+
+			// The environment of the containers is resolved when they start,
+			// so the sidecar reports the annotations from that point in time.
+			startedPod = pod.DeepCopy()
+			reconciler = createTestClusterReconciler()
+			reconciler.PodClientProvider = func(cluster *fdbtypes.FoundationDBCluster, pod *corev1.Pod) (podclient.FdbPodClient, error) {
+				if pod.Name == startedPod.Name {
+					return internal.NewMockFdbPodClient(cluster, startedPod)
+				}
+				return internal.NewMockFdbPodClient(cluster, pod)
+			}
+		})
+
+		JustBeforeEach(func() {
+			requeue = updatePods{}.reconcile(context.TODO(), reconciler, cluster)
+		})
+
+		getPods := func() []*corev1.Pod {
+			pods, err := clusterReconciler.PodLifecycleManager.GetPods(context.TODO(), clusterReconciler, cluster, internal.GetSinglePodListOptions(cluster, "storage-1")...)
+			Expect(err).NotTo(HaveOccurred())
+			return pods
+		}
+
+		When("the annotation is not set", func() {
+			It("should not recreate the pod", func() {
+				Expect(requeue).To(BeNil())
+				pods := getPods()
+				Expect(pods).To(HaveLen(1))
+				Expect(pods[0].UID).To(Equal(pod.UID))
+			})
+		})
+
+		When("the annotation is set after the containers started", func() {
+			BeforeEach(func() {
+				pod.Annotations["example.com/team"] = "team-1"
+				Expect(k8sClient.Update(context.TODO(), pod)).NotTo(HaveOccurred())
+			})
+
+			It("should recreate the pod", func() {
+				Expect(getPods()).To(BeEmpty())
+			})
+		})
+	})
+
+	Context("When a substitution variable reads a node label", func() {
+		var cluster *fdbtypes.FoundationDBCluster
+		var reconciler *FoundationDBClusterReconciler
+		var node *corev1.Node
+		var startedPod *corev1.Pod
+
+		BeforeEach(func() {
+			cluster = internal.CreateDefaultCluster()
+			cluster.Spec.SubstitutionVariables = []fdbtypes.SubstitutionVariable{
+				{Name: "FDB_RACK", NodeLabel: "topology.example.com/rack"},
+			}
+			err := setupClusterForTest(cluster)
+			Expect(err).NotTo(HaveOccurred())
+
+			node = &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"topology.example.com/rack": "rack-1"}},
+			}
+			Expect(k8sClient.Create(context.TODO(), node)).NotTo(HaveOccurred())
+
+			pods, err := clusterReconciler.PodLifecycleManager.GetPods(context.TODO(), clusterReconciler, cluster, internal.GetSinglePodListOptions(cluster, "storage-1")...)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pods).To(HaveLen(1))
+			pods[0].Spec.NodeName = node.Name
+			Expect(k8sClient.Update(context.TODO(), pods[0])).NotTo(HaveOccurred())
+
+			// The init container holds back the containers until the
+			// operator has copied the node label to the pod.
+			Expect(updateNodeLabelAnnotations{}.reconcile(context.TODO(), clusterReconciler, cluster)).To(BeNil())
+			pods, err = clusterReconciler.PodLifecycleManager.GetPods(context.TODO(), clusterReconciler, cluster, internal.GetSinglePodListOptions(cluster, "storage-1")...)
+			Expect(err).NotTo(HaveOccurred())
+			startedPod = pods[0].DeepCopy()
+
+			reconciler = createTestClusterReconciler()
+			reconciler.PodClientProvider = func(cluster *fdbtypes.FoundationDBCluster, pod *corev1.Pod) (podclient.FdbPodClient, error) {
+				if pod.Name == startedPod.Name {
+					return internal.NewMockFdbPodClient(cluster, startedPod)
+				}
+				return internal.NewMockFdbPodClient(cluster, pod)
+			}
+		})
+
+		AfterEach(func() {
+			Expect(k8sClient.Delete(context.TODO(), node)).NotTo(HaveOccurred())
+		})
+
+		getPods := func() []*corev1.Pod {
+			pods, err := clusterReconciler.PodLifecycleManager.GetPods(context.TODO(), clusterReconciler, cluster, internal.GetSinglePodListOptions(cluster, "storage-1")...)
+			Expect(err).NotTo(HaveOccurred())
+			return pods
+		}
+
+		When("the label is unchanged", func() {
+			It("should not recreate the pod", func() {
+				Expect(updatePods{}.reconcile(context.TODO(), reconciler, cluster)).To(BeNil())
+				pods := getPods()
+				Expect(pods).To(HaveLen(1))
+				Expect(pods[0].UID).To(Equal(startedPod.UID))
+			})
+		})
+
+		When("the label changes after the containers started", func() {
+			BeforeEach(func() {
+				node.Labels["topology.example.com/rack"] = "rack-2"
+				Expect(k8sClient.Update(context.TODO(), node)).NotTo(HaveOccurred())
+				Expect(updateNodeLabelAnnotations{}.reconcile(context.TODO(), clusterReconciler, cluster)).To(BeNil())
+			})
+
+			It("should recreate the pod", func() {
+				Expect(updatePods{}.reconcile(context.TODO(), reconciler, cluster)).NotTo(BeNil())
+				Expect(getPods()).To(BeEmpty())
+			})
+		})
+	})
 })
@@ -0,0 +1,116 @@
+/*
+ * update_runtime_knobs.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2021 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// updateRuntimeKnobs provides a reconciliation step for setting knobs through
+// the configuration database.
+type updateRuntimeKnobs struct{}
+
+// reconcile runs the reconciler's work.
+func (updateRuntimeKnobs) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbtypes.FoundationDBCluster) *requeue {
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "updateRuntimeKnobs")
+
+	if !cluster.Status.Configured {
+		return nil
+	}
+
+	desiredKnobs := cluster.GetRuntimeKnobs()
+	changes := getRuntimeKnobChanges(cluster.Status.RuntimeKnobs, desiredKnobs)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+	defer adminClient.Close()
+
+	// The knobs in the configuration database apply to all processes, so only
+	// one instance of the operator should change them at a time.
+	hasLock, err := r.takeLock(cluster, "updating runtime knobs")
+	if !hasLock {
+		return &requeue{curError: err}
+	}
+
+	logger.Info("Updating runtime knobs", "changes", changes)
+	r.Recorder.Event(cluster, corev1.EventTypeNormal, "UpdatingRuntimeKnobs", fmt.Sprintf("Updating %d knobs in the configuration database", len(changes)))
+	err = adminClient.SetRuntimeKnobs(changes)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	// The monitor conf only leaves out the knobs from the status, so the
+	// status must not be updated before the knobs are in the database.
+	names := make([]string, 0, len(changes))
+	for name := range changes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	appliedKnobs, err := adminClient.GetRuntimeKnobs(names)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	for _, name := range names {
+		if appliedKnobs[name] != changes[name] {
+			return &requeue{curError: fmt.Errorf("runtime knob %s has the value %q in the configuration database instead of %q", name, appliedKnobs[name], changes[name])}
+		}
+	}
+
+	cluster.Status.RuntimeKnobs = desiredKnobs
+	err = r.Status().Update(ctx, cluster)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	return nil
+}
+
+// getRuntimeKnobChanges determines the knobs that need to be set in the
+// configuration database to get from the current knobs to the desired knobs.
+// Knobs that need to be cleared have an empty value.
+func getRuntimeKnobChanges(current map[string]string, desired map[string]string) map[string]string {
+	changes := make(map[string]string)
+	for name, value := range desired {
+		if current[name] != value {
+			changes[name] = value
+		}
+	}
+
+	for name := range current {
+		if _, present := desired[name]; !present {
+			changes[name] = ""
+		}
+	}
+
+	return changes
+}
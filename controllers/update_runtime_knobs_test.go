@@ -0,0 +1,204 @@
+/*
+ * update_runtime_knobs_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2021 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("update_runtime_knobs", func() {
+	var cluster *fdbtypes.FoundationDBCluster
+	var adminClient *mockAdminClient
+	var err error
+	var requeue *requeue
+
+	setKnobs := func(parameters ...fdbtypes.FoundationDBCustomParameter) {
+		settings := cluster.Spec.Processes[fdbtypes.ProcessClassGeneral]
+		settings.CustomParameters = parameters
+		cluster.Spec.Processes[fdbtypes.ProcessClassGeneral] = settings
+	}
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		cluster.Spec.Version = fdbtypes.Versions.WithRuntimeKnobs.String()
+		cluster.Spec.AutomationOptions.RuntimeKnobs = []string{"max_trace_lines", "min_trace_severity"}
+		err = internal.NormalizeClusterSpec(cluster, internal.DeprecationOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		setKnobs("knob_max_trace_lines=1000", "knob_disable_posix_kernel_aio=1")
+
+		err = k8sClient.Create(context.TODO(), cluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		result, err := reconcileCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Requeue).To(BeFalse())
+
+		_, err = reloadCluster(cluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		adminClient, err = newMockAdminClientUncast(cluster, k8sClient)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	When("reconciling a new cluster", func() {
+		It("should set the runtime knobs in the database", func() {
+			Expect(adminClient.RuntimeKnobs).To(Equal(map[string]string{"max_trace_lines": "1000"}))
+			Expect(cluster.Status.RuntimeKnobs).To(Equal(map[string]string{"max_trace_lines": "1000"}))
+		})
+
+		It("should only put the other knobs on the command line", func() {
+			status, err := adminClient.GetStatus()
+			Expect(err).NotTo(HaveOccurred())
+			for _, process := range status.Cluster.Processes {
+				Expect(process.CommandLine).To(ContainSubstring("--knob_disable_posix_kernel_aio=1"))
+				Expect(process.CommandLine).NotTo(ContainSubstring("--knob_max_trace_lines"))
+				Expect(process.CommandLine).To(ContainSubstring("--config_db=simple"))
+			}
+		})
+
+		It("should report the active knobs for the process groups", func() {
+			for _, processGroup := range cluster.Status.ProcessGroups {
+				Expect(processGroup.ActiveKnobs).To(Equal(map[string]string{
+					"disable_posix_kernel_aio": "1",
+					"max_trace_lines":          "1000",
+				}))
+			}
+		})
+	})
+
+	When("running the reconciler", func() {
+		JustBeforeEach(func() {
+			requeue = updateRuntimeKnobs{}.reconcile(context.TODO(), clusterReconciler, cluster)
+			if requeue != nil {
+				Expect(requeue.curError).NotTo(HaveOccurred())
+			}
+			_, err = reloadCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("with no changes", func() {
+			It("should not requeue", func() {
+				Expect(requeue).To(BeNil())
+			})
+		})
+
+		Context("with a changed runtime knob", func() {
+			BeforeEach(func() {
+				setKnobs("knob_max_trace_lines=2000", "knob_min_trace_severity=20", "knob_disable_posix_kernel_aio=1")
+			})
+
+			It("should update the knobs in the database", func() {
+				Expect(adminClient.RuntimeKnobs).To(Equal(map[string]string{"max_trace_lines": "2000", "min_trace_severity": "20"}))
+			})
+
+			It("should update the status", func() {
+				Expect(cluster.Status.RuntimeKnobs).To(Equal(map[string]string{"max_trace_lines": "2000", "min_trace_severity": "20"}))
+			})
+		})
+
+		Context("with a changed runtime knob and a denied lock", func() {
+			BeforeEach(func() {
+				cluster.Spec.LockOptions.DisableLocks = pointer.Bool(false)
+				lockClient := newMockLockClientUncast(cluster)
+				lockClient.cluster = cluster
+				err = lockClient.UpdateDenyList([]fdbtypes.LockDenyListEntry{{ID: cluster.GetLockID()}})
+				Expect(err).NotTo(HaveOccurred())
+				setKnobs("knob_max_trace_lines=2000", "knob_disable_posix_kernel_aio=1")
+			})
+
+			It("should requeue", func() {
+				Expect(requeue).NotTo(BeNil())
+			})
+
+			It("should not update the knobs in the database", func() {
+				Expect(adminClient.RuntimeKnobs).To(Equal(map[string]string{"max_trace_lines": "1000"}))
+				Expect(cluster.Status.RuntimeKnobs).To(Equal(map[string]string{"max_trace_lines": "1000"}))
+			})
+		})
+
+		Context("with a removed runtime knob", func() {
+			BeforeEach(func() {
+				setKnobs("knob_disable_posix_kernel_aio=1")
+			})
+
+			It("should clear the knob in the database", func() {
+				Expect(adminClient.RuntimeKnobs).To(BeEmpty())
+				Expect(cluster.Status.RuntimeKnobs).To(BeNil())
+			})
+		})
+	})
+
+	When("the configuration database doesn't apply the change", func() {
+		BeforeEach(func() {
+			adminClient.ignoreRuntimeKnobChanges = true
+			setKnobs("knob_max_trace_lines=1000", "knob_min_trace_severity=20", "knob_disable_posix_kernel_aio=1")
+			requeue = updateRuntimeKnobs{}.reconcile(context.TODO(), clusterReconciler, cluster)
+			_, err = reloadCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should return an error", func() {
+			Expect(requeue).NotTo(BeNil())
+			Expect(requeue.curError).To(HaveOccurred())
+		})
+
+		It("should not update the status", func() {
+			Expect(cluster.Status.RuntimeKnobs).To(Equal(map[string]string{"max_trace_lines": "1000"}))
+		})
+	})
+
+	When("changing a runtime knob", func() {
+		BeforeEach(func() {
+			adminClient.KilledAddresses = nil
+			setKnobs("knob_max_trace_lines=2000", "knob_disable_posix_kernel_aio=1")
+			err = k8sClient.Update(context.TODO(), cluster)
+			Expect(err).NotTo(HaveOccurred())
+
+			result, err := reconcileCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeFalse())
+
+			_, err = reloadCluster(cluster)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should not bounce the processes", func() {
+			Expect(adminClient.KilledAddresses).To(BeEmpty())
+		})
+
+		It("should update the knob in the database", func() {
+			Expect(adminClient.RuntimeKnobs).To(Equal(map[string]string{"max_trace_lines": "2000"}))
+		})
+
+		It("should report the new value in the active knobs", func() {
+			for _, processGroup := range cluster.Status.ProcessGroups {
+				Expect(processGroup.ActiveKnobs).To(HaveKeyWithValue("max_trace_lines", "2000"))
+			}
+		})
+	})
+})
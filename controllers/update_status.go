@@ -23,8 +23,10 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"math"
 	"reflect"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/podmanager"
@@ -35,6 +37,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -51,9 +54,18 @@ func (updateStatus) reconcile(ctx context.Context, r *FoundationDBClusterReconci
 	originalStatus := cluster.Status.DeepCopy()
 	status := fdbtypes.FoundationDBClusterStatus{}
 	status.Generations.Reconciled = cluster.Status.Generations.Reconciled
+	status.ReconciliationHistory = cluster.Status.ReconciliationHistory
+	status.ClientConfigTargets = cluster.Status.ClientConfigTargets
+	status.TLSCertificate = cluster.Status.TLSCertificate
+	status.TLSMigration = cluster.Status.TLSMigration
+	status.ActiveFreezes = cluster.GetActiveFreezes(time.Now())
+	status.ProcessGroupParameterOverrides = cluster.GetActiveParameterOverrides(time.Now())
+	status.RuntimeKnobs = cluster.Status.RuntimeKnobs
+	status.UpgradeCheck = cluster.Status.UpgradeCheck
 
 	// Initialize with the current desired storage servers per Pod
 	status.StorageServersPerDisk = []int{cluster.GetStorageServersPerPod()}
+	status.LogServersPerDisk = []int{cluster.GetLogServersPerPod()}
 	status.ImageTypes = []fdbtypes.ImageType{fdbtypes.ImageType(internal.GetDesiredImageType(cluster))}
 
 	var databaseStatus *fdbtypes.FoundationDBStatus
@@ -93,6 +105,9 @@ func (updateStatus) reconcile(ctx context.Context, r *FoundationDBClusterReconci
 					},
 				},
 			}
+		} else if hasPendingUpgradeCheck(cluster) {
+			status.UpgradeCheck = getUpgradeCheckStatus(cluster, adminClient, databaseStatus, cluster.Annotations[fdbtypes.UpgradeCheckVersionAnnotation])
+			status.UpgradeCheck.RequestID = cluster.Annotations[fdbtypes.UpgradeCheckRequestAnnotation]
 		}
 	}
 
@@ -108,9 +123,16 @@ func (updateStatus) reconcile(ctx context.Context, r *FoundationDBClusterReconci
 	status.HasListenIPsForAllPods = cluster.NeedsExplicitListenAddress()
 	status.DatabaseConfiguration = databaseStatus.Cluster.DatabaseConfiguration.NormalizeConfiguration()
 	cluster.ClearMissingVersionFlags(&status.DatabaseConfiguration)
+	cluster.ClearUnusedProxyCounts(&status.DatabaseConfiguration)
+	cluster.ClearMissingStorageWiggleOptions(&status.DatabaseConfiguration)
+	cluster.IgnorePausedStorageWiggle(&status.DatabaseConfiguration)
 	status.Configured = cluster.Status.Configured || (databaseStatus.Client.DatabaseStatus.Available && databaseStatus.Cluster.Layers.Error != "configurationMissing")
 
-	if cluster.Spec.MainContainer.EnableTLS {
+	status.TLSMigration = getTLSMigrationStatus(r, cluster, databaseStatus)
+	if status.TLSMigration != nil && status.TLSMigration.Phase != fdbtypes.TLSMigrationPhaseRemovingOldListener {
+		status.RequiredAddresses.TLS = true
+		status.RequiredAddresses.NonTLS = true
+	} else if cluster.Spec.MainContainer.EnableTLS {
 		status.RequiredAddresses.TLS = true
 	} else {
 		status.RequiredAddresses.NonTLS = true
@@ -129,6 +151,28 @@ func (updateStatus) reconcile(ctx context.Context, r *FoundationDBClusterReconci
 		status.Health.Healthy = databaseStatus.Client.DatabaseStatus.Healthy
 		status.Health.FullReplication = databaseStatus.Cluster.FullReplication
 		status.Health.DataMovementPriority = databaseStatus.Cluster.Data.MovingData.HighestPriority
+		status.DataCenters = getDataCenterStatus(cluster, databaseStatus)
+		status.ActivePrimaryDataCenter = getActivePrimaryDataCenter(databaseStatus)
+		status.FailOver = cluster.Status.FailOver
+
+		if status.FailOver != nil {
+			desiredPrimary := cluster.DesiredDatabaseConfiguration().GetPrimaryDataCenter()
+			if status.FailOver.To != desiredPrimary {
+				logger.Info("Dropping fail over that is no longer requested", "to", status.FailOver.To, "desiredPrimary", desiredPrimary)
+				status.FailOver = nil
+			} else if status.FailOver.Phase == fdbtypes.FailOverPhaseRecovering &&
+				status.ActivePrimaryDataCenter == status.FailOver.To &&
+				databaseStatus.Cluster.RecoveryState.Name == fdbtypes.RecoveryStateFullyRecovered {
+				r.Recorder.Event(cluster, corev1.EventTypeNormal, "FailOverCompleted", fmt.Sprintf("Data center %s is the primary", status.FailOver.To))
+				status.FailOver = nil
+			}
+		}
+
+		upgradeProgress, err := getUpgradeProgress(r, cluster, databaseStatus)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+		status.UpgradeProgress = upgradeProgress
 	}
 
 	cluster.Status.RequiredAddresses = status.RequiredAddresses
@@ -154,6 +198,17 @@ func (updateStatus) reconcile(ctx context.Context, r *FoundationDBClusterReconci
 	if err != nil {
 		return &requeue{curError: err}
 	}
+	updateCoordinatorConditions(cluster, status.ProcessGroups, databaseStatus)
+
+	runningVersion, err := fdbtypes.ParseFdbVersion(cluster.GetRunningVersion())
+	if err != nil {
+		return &requeue{curError: err}
+	}
+	if runningVersion.SupportsRuntimeKnobs() {
+		updateActiveKnobs(status.ProcessGroups, databaseStatus, status.RuntimeKnobs)
+	}
+	updateStorageWiggleStatus(status.ProcessGroups, databaseStatus, status.DatabaseConfiguration.StorageEngine)
+	updateTLSCertificateConditions(cluster, status.ProcessGroups)
 	removeDuplicateConditions(status)
 
 	existingConfigMap := &corev1.ConfigMap{}
@@ -177,6 +232,11 @@ func (updateStatus) reconcile(ctx context.Context, r *FoundationDBClusterReconci
 		status.RunningVersion = cluster.Spec.Version
 	}
 
+	status.PreviousVersion = cluster.Status.PreviousVersion
+	if originalStatus.RunningVersion != "" && status.RunningVersion != originalStatus.RunningVersion {
+		status.PreviousVersion = originalStatus.RunningVersion
+	}
+
 	status.ConnectionString = cluster.Status.ConnectionString
 	if status.ConnectionString == "" {
 		status.ConnectionString = existingConfigMap.Data[internal.ClusterFileKey]
@@ -265,9 +325,22 @@ func (updateStatus) reconcile(ctx context.Context, r *FoundationDBClusterReconci
 		status.Locks.DenyList = denyList
 	}
 
+	if len(status.DataCenters) > 0 && cluster.ShouldUseLocks() && status.Configured {
+		lockClient, err := r.getLockClient(cluster)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+		instances, err := lockClient.GetOperatorInstances()
+		if err != nil {
+			return &requeue{curError: err}
+		}
+		addOperatorInstances(cluster, status.DataCenters, instances, r.now())
+	}
+
 	// Sort slices that are assembled based on pods to prevent a reordering from
 	// issuing a new reconcile loop.
 	sort.Ints(status.StorageServersPerDisk)
+	sort.Ints(status.LogServersPerDisk)
 	sort.Slice(status.ImageTypes, func(i int, j int) bool {
 		return string(status.ImageTypes[i]) < string(status.ImageTypes[j])
 	})
@@ -297,9 +370,414 @@ func (updateStatus) reconcile(ctx context.Context, r *FoundationDBClusterReconci
 		}
 	}
 
+	// The request for the upgrade check is only removed once the result is
+	// in the status, so the result can't get lost.
+	if cluster.Annotations[fdbtypes.UpgradeCheckRequestAnnotation] != "" && !hasPendingUpgradeCheck(cluster) {
+		err = clearUpgradeCheckRequest(ctx, r, cluster)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+	}
+
+	return nil
+}
+
+// hasPendingUpgradeCheck determines whether the cluster has a request for the
+// upgrade checks that the operator has not reported a result for.
+func hasPendingUpgradeCheck(cluster *fdbtypes.FoundationDBCluster) bool {
+	request := cluster.Annotations[fdbtypes.UpgradeCheckRequestAnnotation]
+	if request == "" {
+		return false
+	}
+
+	return cluster.Status.UpgradeCheck == nil || cluster.Status.UpgradeCheck.RequestID != request
+}
+
+// clearUpgradeCheckRequest removes the annotations that request the upgrade
+// checks from the cluster.
+func clearUpgradeCheckRequest(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbtypes.FoundationDBCluster) error {
+	// Only the annotations are updated, so the defaults from normalizing the
+	// spec don't end up in the stored cluster.
+	current := &fdbtypes.FoundationDBCluster{}
+	err := r.Get(ctx, client.ObjectKeyFromObject(cluster), current)
+	if err != nil {
+		return err
+	}
+
+	delete(current.Annotations, fdbtypes.UpgradeCheckRequestAnnotation)
+	delete(current.Annotations, fdbtypes.UpgradeCheckVersionAnnotation)
+	err = r.Update(ctx, current)
+	if err != nil {
+		return err
+	}
+
+	cluster.ObjectMeta.Annotations = current.ObjectMeta.Annotations
+	cluster.ObjectMeta.ResourceVersion = current.ObjectMeta.ResourceVersion
+
 	return nil
 }
 
+// getDataCenterStatus builds the global view of the data centers from the
+// processes that report to the database.
+func getDataCenterStatus(cluster *fdbtypes.FoundationDBCluster, databaseStatus *fdbtypes.FoundationDBStatus) []fdbtypes.DataCenterStatus {
+	coordinators := make(map[string]fdbtypes.None, len(databaseStatus.Client.Coordinators.Coordinators))
+	for _, coordinator := range databaseStatus.Client.Coordinators.Coordinators {
+		coordinators[coordinator.Address.String()] = fdbtypes.None{}
+	}
+
+	dataCenters := make(map[string]*fdbtypes.DataCenterStatus)
+	for _, process := range databaseStatus.Cluster.Processes {
+		dcID := process.Locality[fdbtypes.FDBLocalityDCIDKey]
+		if dcID == "" {
+			continue
+		}
+
+		dataCenter, ok := dataCenters[dcID]
+		if !ok {
+			dataCenter = &fdbtypes.DataCenterStatus{
+				ID:      dcID,
+				Managed: dcID == cluster.Spec.DataCenter,
+			}
+			dataCenters[dcID] = dataCenter
+		}
+
+		dataCenter.ProcessCounts.IncreaseCount(process.ProcessClass, 1)
+		for _, role := range process.Roles {
+			increaseRoleCount(&dataCenter.RoleCounts, fdbtypes.ProcessRole(role.Role))
+		}
+
+		if process.Excluded {
+			dataCenter.ExcludedProcesses++
+		}
+
+		if _, ok := coordinators[process.Address.String()]; ok {
+			dataCenter.Coordinators++
+		}
+	}
+
+	result := make([]fdbtypes.DataCenterStatus, 0, len(dataCenters))
+	for _, dataCenter := range dataCenters {
+		result = append(result, *dataCenter)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+
+	return result
+}
+
+// increaseRoleCount adds a role that a process reports in the database status
+// to the role counts. Roles that can't be configured through the role counts
+// are ignored.
+func increaseRoleCount(counts *fdbtypes.RoleCounts, role fdbtypes.ProcessRole) {
+	switch role {
+	case fdbtypes.ProcessRoleStorage:
+		counts.Storage++
+	case fdbtypes.ProcessRoleLog:
+		counts.Logs++
+	case fdbtypes.ProcessRoleProxy:
+		counts.Proxies++
+	case fdbtypes.ProcessRoleGrvProxy:
+		counts.GrvProxies++
+	case fdbtypes.ProcessRoleCommitProxy:
+		counts.CommitProxies++
+	case fdbtypes.ProcessRoleResolver:
+		counts.Resolvers++
+	case fdbtypes.ProcessRoleLogRouter:
+		counts.LogRouters++
+	}
+}
+
+// addOperatorInstances adds the active operator instances that are registered
+// in the database to the data centers they manage.
+func addOperatorInstances(cluster *fdbtypes.FoundationDBCluster, dataCenters []fdbtypes.DataCenterStatus, instances []fdbtypes.OperatorInstance, now time.Time) {
+	for index := range dataCenters {
+		for _, instance := range instances {
+			if instance.DataCenter == dataCenters[index].ID && instance.IsActive(cluster, now) {
+				dataCenters[index].OperatorInstances = append(dataCenters[index].OperatorInstances, instance.ID)
+			}
+		}
+	}
+}
+
+// getUpgradeProgress determines the progress of an upgrade to the version from
+// the spec. This returns nil if all processes run that version.
+func getUpgradeProgress(r *FoundationDBClusterReconciler, cluster *fdbtypes.FoundationDBCluster, databaseStatus *fdbtypes.FoundationDBStatus) (*fdbtypes.UpgradeProgress, error) {
+	upgrading := cluster.Status.RunningVersion != cluster.Spec.Version
+	processVersions := make(map[string]int)
+	for _, process := range databaseStatus.Cluster.Processes {
+		if process.Version == "" {
+			continue
+		}
+
+		processVersions[process.Version]++
+		if process.Version != cluster.Spec.Version {
+			upgrading = true
+		}
+	}
+
+	if !upgrading {
+		return nil, nil
+	}
+
+	progress := &fdbtypes.UpgradeProgress{
+		Version:         cluster.Spec.Version,
+		ProcessVersions: processVersions,
+	}
+
+	if !cluster.ShouldUseLocks() {
+		return progress, nil
+	}
+
+	version, err := fdbtypes.ParseFdbVersion(cluster.Spec.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	// The pending upgrades are only informational, so a failure to read them
+	// shouldn't block the rest of the status update.
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "updateStatus")
+	lockClient, err := r.getLockClient(cluster)
+	if err != nil {
+		logger.Error(err, "Error getting lock client to read pending upgrades")
+		return progress, nil
+	}
+
+	pendingUpgrades, err := lockClient.GetPendingUpgrades(version)
+	if err != nil {
+		logger.Error(err, "Error reading pending upgrades")
+		return progress, nil
+	}
+
+	for processGroupID, pending := range pendingUpgrades {
+		if pending {
+			progress.PendingUpgrades = append(progress.PendingUpgrades, processGroupID)
+		}
+	}
+	sort.Strings(progress.PendingUpgrades)
+
+	return progress, nil
+}
+
+// updateActiveKnobs reports the knobs that are active for the processes in
+// each process group, combining the knobs from the command line with the
+// knobs that the operator has set in the configuration database.
+func updateActiveKnobs(processGroups []*fdbtypes.ProcessGroupStatus, databaseStatus *fdbtypes.FoundationDBStatus, runtimeKnobs map[string]string) {
+	commandLines := make(map[string]string, len(databaseStatus.Cluster.Processes))
+	for _, process := range databaseStatus.Cluster.Processes {
+		instanceID := process.Locality["instance_id"]
+		if _, present := commandLines[instanceID]; !present {
+			commandLines[instanceID] = process.CommandLine
+		}
+	}
+
+	for _, processGroup := range processGroups {
+		commandLine, present := commandLines[processGroup.ProcessGroupID]
+		if !present {
+			processGroup.ActiveKnobs = nil
+			continue
+		}
+
+		knobs := make(map[string]string, len(runtimeKnobs))
+		for name, value := range runtimeKnobs {
+			knobs[name] = value
+		}
+
+		for _, argument := range strings.Fields(commandLine) {
+			if !strings.HasPrefix(argument, "--knob_") {
+				continue
+			}
+
+			components := strings.SplitN(strings.TrimPrefix(argument, "--knob_"), "=", 2)
+			if len(components) == 2 {
+				knobs[components[0]] = components[1]
+			}
+		}
+
+		if len(knobs) == 0 {
+			knobs = nil
+		}
+		processGroup.ActiveKnobs = knobs
+	}
+}
+
+// updateStorageWiggleStatus reports the progress of the perpetual storage
+// wiggle for the storage servers in each process group, based on the storage
+// metadata in the database status.
+func updateStorageWiggleStatus(processGroups []*fdbtypes.ProcessGroupStatus, databaseStatus *fdbtypes.FoundationDBStatus, storageEngine fdbtypes.StorageEngine) {
+	wiggling := make(map[string]bool, len(databaseStatus.Cluster.StorageWiggler.WiggleServerAddresses))
+	for _, address := range databaseStatus.Cluster.StorageWiggler.WiggleServerAddresses {
+		wiggling[address.StringWithoutFlags()] = true
+	}
+
+	wiggleStatus := make(map[string]*fdbtypes.StorageWiggleStatus)
+	for _, process := range databaseStatus.Cluster.Processes {
+		instanceID := process.Locality[fdbtypes.FDBLocalityInstanceIDKey]
+		for _, role := range process.Roles {
+			if role.StorageMetadata == nil {
+				continue
+			}
+
+			processGroupStatus, present := wiggleStatus[instanceID]
+			if !present {
+				processGroupStatus = &fdbtypes.StorageWiggleStatus{}
+				wiggleStatus[instanceID] = processGroupStatus
+			}
+
+			if wiggling[process.Address.StringWithoutFlags()] {
+				processGroupStatus.InProgress = true
+			}
+
+			if role.StorageMetadata.CreatedTimestamp > 0 {
+				seconds, fraction := math.Modf(role.StorageMetadata.CreatedTimestamp)
+				createdTime := time.Unix(int64(seconds), int64(fraction*float64(time.Second)))
+				if processGroupStatus.LastRefreshed == nil || createdTime.Before(processGroupStatus.LastRefreshed.Time) {
+					processGroupStatus.LastRefreshed = &metav1.Time{Time: createdTime}
+				}
+			}
+
+			if processGroupStatus.StorageEngine == "" || role.StorageMetadata.StorageEngine != storageEngine {
+				processGroupStatus.StorageEngine = role.StorageMetadata.StorageEngine
+			}
+		}
+	}
+
+	for _, processGroup := range processGroups {
+		processGroup.StorageWiggle = wiggleStatus[processGroup.ProcessGroupID]
+	}
+}
+
+// getTLSMigrationStatus starts a migration between TLS and non-TLS listeners
+// when the processes or coordinators use a different address scheme than the
+// spec, and advances the migration to the next phase once the database
+// reports that the current phase is complete.
+func getTLSMigrationStatus(r *FoundationDBClusterReconciler, cluster *fdbtypes.FoundationDBCluster, databaseStatus *fdbtypes.FoundationDBStatus) *fdbtypes.TLSMigrationStatus {
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "updateStatus")
+	enableTLS := cluster.Spec.MainContainer.EnableTLS
+	migration := cluster.Status.TLSMigration.DeepCopy()
+	if databaseStatus == nil {
+		return migration
+	}
+
+	if migration != nil && migration.TLS != enableTLS {
+		logger.Info("Restarting TLS migration for changed spec", "tls", enableTLS)
+		migration = nil
+	}
+
+	hasOldCoordinators := false
+	for _, coordinator := range databaseStatus.Client.Coordinators.Coordinators {
+		if coordinator.Address.Flags["tls"] != enableTLS {
+			hasOldCoordinators = true
+		}
+	}
+
+	hasOldListeners, hasNewListeners := getListenerSchemes(cluster, databaseStatus)
+
+	if migration == nil {
+		if !hasOldCoordinators && !hasOldListeners {
+			return nil
+		}
+
+		logger.Info("Starting TLS migration", "tls", enableTLS)
+		r.Recorder.Event(cluster, corev1.EventTypeNormal, "StartingTLSMigration", fmt.Sprintf("Migrating processes to TLS=%t", enableTLS))
+		return &fdbtypes.TLSMigrationStatus{
+			TLS:       enableTLS,
+			Phase:     fdbtypes.TLSMigrationPhaseEnablingDualListeners,
+			StartTime: &metav1.Time{Time: time.Now()},
+		}
+	}
+
+	var nextPhase fdbtypes.TLSMigrationPhase
+	switch migration.Phase {
+	case fdbtypes.TLSMigrationPhaseEnablingDualListeners:
+		if !hasNewListeners {
+			return migration
+		}
+		nextPhase = fdbtypes.TLSMigrationPhaseChangingCoordinators
+	case fdbtypes.TLSMigrationPhaseChangingCoordinators:
+		if hasOldCoordinators {
+			return migration
+		}
+		nextPhase = fdbtypes.TLSMigrationPhaseRemovingOldListener
+	case fdbtypes.TLSMigrationPhaseRemovingOldListener:
+		if hasOldListeners {
+			return migration
+		}
+		logger.Info("Completed TLS migration", "tls", enableTLS)
+		r.Recorder.Event(cluster, corev1.EventTypeNormal, "TLSMigrationCompleted", fmt.Sprintf("Processes are listening with TLS=%t", enableTLS))
+		return nil
+	default:
+		nextPhase = fdbtypes.TLSMigrationPhaseEnablingDualListeners
+	}
+
+	logger.Info("Advancing TLS migration", "phase", nextPhase)
+	r.Recorder.Event(cluster, corev1.EventTypeNormal, "TLSMigrationPhaseChanged", fmt.Sprintf("TLS migration is in phase %s", nextPhase))
+	migration.Phase = nextPhase
+	return migration
+}
+
+// getListenerSchemes checks the command lines of the processes in the cluster
+// to determine whether any process still listens on the address scheme that
+// the spec does not request, and whether all processes listen on the address
+// scheme that the spec requests.
+func getListenerSchemes(cluster *fdbtypes.FoundationDBCluster, databaseStatus *fdbtypes.FoundationDBStatus) (bool, bool) {
+	enableTLS := cluster.Spec.MainContainer.EnableTLS
+	processGroups := make(map[string]*fdbtypes.ProcessGroupStatus, len(cluster.Status.ProcessGroups))
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		processGroups[processGroup.ProcessGroupID] = processGroup
+	}
+
+	hasOldListeners := false
+	hasNewListeners := true
+	for _, process := range databaseStatus.Cluster.Processes {
+		processGroup, present := processGroups[process.Locality[fdbtypes.FDBLocalityInstanceIDKey]]
+		if !present || processGroup.IsMarkedForRemoval() || process.Address.IsEmpty() {
+			continue
+		}
+
+		addresses, err := fdbtypes.ParseProcessAddressesFromCmdline(process.CommandLine)
+		if err != nil {
+			hasNewListeners = false
+			continue
+		}
+
+		hasNewListener := false
+		for _, address := range addresses {
+			if address.Flags["tls"] == enableTLS {
+				hasNewListener = true
+			} else {
+				hasOldListeners = true
+			}
+		}
+
+		if !hasNewListener {
+			hasNewListeners = false
+		}
+	}
+
+	return hasOldListeners, hasNewListeners
+}
+
+// getActivePrimaryDataCenter determines which data center currently acts as
+// the primary. This uses the data center of the master process and falls
+// back to the data center with the highest priority in the configuration.
+func getActivePrimaryDataCenter(databaseStatus *fdbtypes.FoundationDBStatus) string {
+	if len(databaseStatus.Cluster.DatabaseConfiguration.Regions) == 0 {
+		return ""
+	}
+
+	for _, process := range databaseStatus.Cluster.Processes {
+		for _, role := range process.Roles {
+			if role.Role == string(fdbtypes.ProcessRoleMaster) {
+				return process.Locality[fdbtypes.FDBLocalityDCIDKey]
+			}
+		}
+	}
+
+	return databaseStatus.Cluster.DatabaseConfiguration.GetPrimaryDataCenter()
+}
+
 // containsAll determines if one map contains all the keys and matching values
 // from another map.
 func containsAll(current map[string]string, desired map[string]string) bool {
@@ -396,7 +874,7 @@ func checkAndSetProcessStatus(r *FoundationDBClusterReconciler, cluster *fdbtype
 
 	correct := false
 	for _, process := range processStatus {
-		commandLine, err := internal.GetStartCommand(cluster, processGroupStatus.ProcessClass, podClient, processNumber, processCount)
+		commandLine, err := internal.GetStartCommand(cluster, processGroupStatus.ProcessClass, processGroupStatus.ProcessGroupID, podClient, processNumber, processCount)
 		if err != nil {
 			if internal.IsNetworkError(err) {
 				processGroupStatus.UpdateCondition(fdbtypes.SidecarUnreachable, true, cluster.Status.ProcessGroups, processGroupStatus.ProcessGroupID)
@@ -477,14 +955,21 @@ func validateProcessGroups(ctx context.Context, r *FoundationDBClusterReconciler
 		}
 
 		// Even the process group will be removed we need to keep the config around.
-		// Set the processCount for the process group specific storage servers per pod
+		// Set the processCount for the process group specific storage or log servers per pod
 		if processGroup.ProcessClass == fdbtypes.ProcessClassStorage {
-			processCount, err = internal.GetStorageServersPerPodForPod(pod)
+			processCount, err = internal.GetServersPerPodForPod(pod, processGroup.ProcessClass)
 			if err != nil {
 				return processGroups, err
 			}
 
 			status.AddStorageServerPerDisk(processCount)
+		} else if processGroup.ProcessClass.IsLogProcess() {
+			processCount, err = internal.GetServersPerPodForPod(pod, processGroup.ProcessClass)
+			if err != nil {
+				return processGroups, err
+			}
+
+			status.AddLogServerPerDisk(processCount)
 		}
 
 		imageType := internal.GetImageType(pod)
@@ -532,7 +1017,7 @@ func validateProcessGroups(ctx context.Context, r *FoundationDBClusterReconciler
 			}
 		}
 
-		configMapHash, err := internal.GetDynamicConfHash(configMap, processGroup.ProcessClass, imageType, processCount)
+		configMapHash, err := internal.GetDynamicConfHash(configMap, processGroup.ProcessClass, processGroup.ProcessGroupID, imageType, processCount)
 		if err != nil {
 			return processGroups, err
 		}
@@ -601,6 +1086,15 @@ func validateProcessGroup(ctx context.Context, r *FoundationDBClusterReconciler,
 
 	processGroupStatus.UpdateCondition(fdbtypes.MissingPVC, incorrectPVC, cluster.Status.ProcessGroups, processGroupStatus.ProcessGroupID)
 
+	nodeUnschedulable := false
+	if r.EnableNodeChecks {
+		nodeUnschedulable, err = podIsOnUnschedulableNode(ctx, r, pod)
+		if err != nil {
+			return false, err
+		}
+	}
+	processGroupStatus.UpdateCondition(fdbtypes.NodeUnschedulable, nodeUnschedulable, cluster.Status.ProcessGroups, processGroupStatus.ProcessGroupID)
+
 	var needsSidecarConfInConfigMap bool
 	for _, container := range pod.Spec.Containers {
 		if container.Name == "foundationdb" {
@@ -652,6 +1146,91 @@ func validateProcessGroup(ctx context.Context, r *FoundationDBClusterReconciler,
 	return needsSidecarConfInConfigMap, nil
 }
 
+// podIsOnUnschedulableNode checks if the pod is running on a node that is
+// marked as unschedulable, e.g. because the node is being drained.
+func podIsOnUnschedulableNode(ctx context.Context, r *FoundationDBClusterReconciler, pod *corev1.Pod) (bool, error) {
+	if pod.Spec.NodeName == "" {
+		return false, nil
+	}
+
+	node := &corev1.Node{}
+	err := r.NodeReader.Get(ctx, client.ObjectKey{Name: pod.Spec.NodeName}, node)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return node.Spec.Unschedulable, nil
+}
+
+// updateTLSCertificateConditions sets the OutdatedTLSCertificate condition on
+// all process groups whose processes have not loaded the current TLS
+// certificate. Process groups without a recorded certificate are assumed to
+// have started with the current certificate.
+func updateTLSCertificateConditions(cluster *fdbtypes.FoundationDBCluster, processGroups []*fdbtypes.ProcessGroupStatus) {
+	current := cluster.Status.TLSCertificate
+
+	for _, processGroup := range processGroups {
+		if current == nil {
+			processGroup.TLSCertificate = nil
+		} else if processGroup.TLSCertificate == nil {
+			processGroup.TLSCertificate = current.DeepCopy()
+		}
+
+		outdated := current != nil && processGroup.TLSCertificate.Hash != current.Hash
+		processGroup.UpdateCondition(fdbtypes.OutdatedTLSCertificate, outdated, cluster.Status.ProcessGroups, processGroup.ProcessGroupID)
+	}
+}
+
+// updateCoordinatorConditions sets the CoordinatorUnreachable condition on
+// all process groups that host a coordinator which is reported as
+// unreachable in the database status.
+//
+// The NodeUnschedulable condition is only used to move coordinators off
+// drained nodes, so it is removed from all process groups that don't host a
+// coordinator. Otherwise cordoning a node would mark every process group on
+// it as unhealthy.
+func updateCoordinatorConditions(cluster *fdbtypes.FoundationDBCluster, processGroups []*fdbtypes.ProcessGroupStatus, databaseStatus *fdbtypes.FoundationDBStatus) {
+	coordinators := make(map[string]bool)
+	for _, coordinator := range databaseStatus.Client.Coordinators.Coordinators {
+		if coordinator.Address.IsDNSAddress() {
+			coordinators[coordinator.Address.StringAddress] = coordinator.Reachable
+		} else {
+			coordinators[coordinator.Address.IPAddress.String()] = coordinator.Reachable
+		}
+	}
+
+	for _, processGroup := range processGroups {
+		isCoordinator := false
+		unreachable := false
+		for _, address := range processGroup.Addresses {
+			if reachable, ok := coordinators[address]; ok {
+				isCoordinator = true
+				unreachable = !reachable
+				break
+			}
+		}
+
+		if !isCoordinator && cluster.UseDNSInClusterFile() {
+			_, idNum, err := podmanager.ParseProcessGroupID(processGroup.ProcessGroupID)
+			if err == nil {
+				podName, _ := internal.GetProcessGroupID(cluster, processGroup.ProcessClass, idNum)
+				var reachable bool
+				reachable, isCoordinator = coordinators[internal.GetPodDNSName(cluster, podName)]
+				unreachable = isCoordinator && !reachable
+			}
+		}
+
+		processGroup.UpdateCondition(fdbtypes.CoordinatorUnreachable, unreachable, cluster.Status.ProcessGroups, processGroup.ProcessGroupID)
+		if !isCoordinator {
+			processGroup.UpdateCondition(fdbtypes.NodeUnschedulable, false, cluster.Status.ProcessGroups, processGroup.ProcessGroupID)
+		}
+	}
+}
+
 // removeDuplicateConditions will remove all duplicated conditions from the status and if a process group has the ResourcesTerminating
 // condition it will remove all other conditions on that process group.
 func removeDuplicateConditions(status fdbtypes.FoundationDBClusterStatus) {
@@ -22,6 +22,8 @@ package controllers
 
 import (
 	"context"
+	"net"
+	"time"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/podmanager"
 
@@ -31,7 +33,9 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
 )
 
 var _ = Describe("update_status", func() {
@@ -424,6 +428,198 @@ var _ = Describe("update_status", func() {
 				Expect(pendingCount).To(BeNumerically("==", 1))
 			})
 		})
+
+		When("a Pod is running on an unschedulable node", func() {
+			var drainedProcessGroup string
+			var node *corev1.Node
+
+			BeforeEach(func() {
+				clusterReconciler.EnableNodeChecks = true
+				node = &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{Name: "drained-node"},
+					Spec:       corev1.NodeSpec{Unschedulable: true},
+				}
+				Expect(k8sClient.Create(context.TODO(), node)).NotTo(HaveOccurred())
+
+				drainedProcessGroup = podmanager.GetProcessGroupID(cluster, pods[0])
+				pods[0].Spec.NodeName = node.Name
+				err = k8sClient.Update(context.TODO(), pods[0])
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				clusterReconciler.EnableNodeChecks = false
+				Expect(k8sClient.Delete(context.TODO(), node)).NotTo(HaveOccurred())
+			})
+
+			It("should mark the process group as running on an unschedulable node", func() {
+				processGroupStatus, err := validateProcessGroups(context.TODO(), clusterReconciler, cluster, &cluster.Status, processMap, configMap)
+				Expect(err).NotTo(HaveOccurred())
+
+				unschedulableCount := 0
+				for _, processGroup := range processGroupStatus {
+					if processGroup.GetConditionTime(fdbtypes.NodeUnschedulable) == nil {
+						continue
+					}
+
+					Expect(processGroup.ProcessGroupID).To(Equal(drainedProcessGroup))
+					unschedulableCount++
+				}
+
+				Expect(unschedulableCount).To(BeNumerically("==", 1))
+			})
+		})
+	})
+
+	When("updating the coordinator conditions", func() {
+		var cluster *fdbtypes.FoundationDBCluster
+		var processGroups []*fdbtypes.ProcessGroupStatus
+		var databaseStatus *fdbtypes.FoundationDBStatus
+
+		BeforeEach(func() {
+			cluster = internal.CreateDefaultCluster()
+			processGroups = []*fdbtypes.ProcessGroupStatus{
+				{ProcessGroupID: "storage-1", ProcessClass: fdbtypes.ProcessClassStorage, Addresses: []string{"1.1.1.1"}},
+				{ProcessGroupID: "storage-2", ProcessClass: fdbtypes.ProcessClassStorage, Addresses: []string{"1.1.1.2"}},
+				{ProcessGroupID: "storage-3", ProcessClass: fdbtypes.ProcessClassStorage, Addresses: []string{"1.1.1.3"}},
+			}
+			databaseStatus = &fdbtypes.FoundationDBStatus{
+				Client: fdbtypes.FoundationDBStatusLocalClientInfo{
+					Coordinators: fdbtypes.FoundationDBStatusCoordinatorInfo{
+						Coordinators: []fdbtypes.FoundationDBStatusCoordinator{
+							{Address: fdbtypes.ProcessAddress{IPAddress: net.ParseIP("1.1.1.1"), Port: 4501}, Reachable: true},
+							{Address: fdbtypes.ProcessAddress{IPAddress: net.ParseIP("1.1.1.2"), Port: 4501}, Reachable: false},
+						},
+					},
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			updateCoordinatorConditions(cluster, processGroups, databaseStatus)
+		})
+
+		It("should mark the process group with the unreachable coordinator", func() {
+			Expect(processGroups[0].GetConditionTime(fdbtypes.CoordinatorUnreachable)).To(BeNil())
+			Expect(processGroups[1].GetConditionTime(fdbtypes.CoordinatorUnreachable)).NotTo(BeNil())
+			Expect(processGroups[2].GetConditionTime(fdbtypes.CoordinatorUnreachable)).To(BeNil())
+		})
+
+		When("the coordinator is reachable again", func() {
+			BeforeEach(func() {
+				updateCoordinatorConditions(cluster, processGroups, databaseStatus)
+				databaseStatus.Client.Coordinators.Coordinators[1].Reachable = true
+			})
+
+			It("should remove the condition", func() {
+				for _, processGroup := range processGroups {
+					Expect(processGroup.GetConditionTime(fdbtypes.CoordinatorUnreachable)).To(BeNil())
+				}
+			})
+		})
+
+		When("the pods are on an unschedulable node", func() {
+			BeforeEach(func() {
+				for _, processGroup := range processGroups {
+					processGroup.UpdateCondition(fdbtypes.NodeUnschedulable, true, nil, processGroup.ProcessGroupID)
+				}
+			})
+
+			It("should only keep the condition for the coordinators", func() {
+				Expect(processGroups[0].GetConditionTime(fdbtypes.NodeUnschedulable)).NotTo(BeNil())
+				Expect(processGroups[1].GetConditionTime(fdbtypes.NodeUnschedulable)).NotTo(BeNil())
+				Expect(processGroups[2].GetConditionTime(fdbtypes.NodeUnschedulable)).To(BeNil())
+			})
+		})
+
+		When("the coordinators use DNS names", func() {
+			BeforeEach(func() {
+				cluster.Spec.Version = fdbtypes.Versions.WithDNSInClusterFile.String()
+				enabled := true
+				cluster.Spec.Routing.UseDNSInClusterFile = &enabled
+				databaseStatus.Client.Coordinators.Coordinators[1].Address = fdbtypes.ProcessAddress{
+					StringAddress: internal.GetPodDNSName(cluster, "operator-test-1-storage-3"),
+					Port:          4501,
+				}
+			})
+
+			It("should mark the process group with the unreachable coordinator", func() {
+				Expect(processGroups[0].GetConditionTime(fdbtypes.CoordinatorUnreachable)).To(BeNil())
+				Expect(processGroups[1].GetConditionTime(fdbtypes.CoordinatorUnreachable)).To(BeNil())
+				Expect(processGroups[2].GetConditionTime(fdbtypes.CoordinatorUnreachable)).NotTo(BeNil())
+			})
+		})
+	})
+
+	When("building the storage wiggle status", func() {
+		var processGroups []*fdbtypes.ProcessGroupStatus
+		var databaseStatus *fdbtypes.FoundationDBStatus
+
+		storageProcess := func(processGroupID string, ip string, createdTimestamp float64, storageEngine fdbtypes.StorageEngine) fdbtypes.FoundationDBStatusProcessInfo {
+			return fdbtypes.FoundationDBStatusProcessInfo{
+				Address:  fdbtypes.ProcessAddress{IPAddress: net.ParseIP(ip), Port: 4501},
+				Locality: map[string]string{fdbtypes.FDBLocalityInstanceIDKey: processGroupID},
+				Roles: []fdbtypes.FoundationDBStatusProcessRoleInfo{
+					{
+						Role: string(fdbtypes.ProcessRoleStorage),
+						StorageMetadata: &fdbtypes.FoundationDBStatusStorageMetadata{
+							CreatedTimestamp: createdTimestamp,
+							StorageEngine:    storageEngine,
+						},
+					},
+				},
+			}
+		}
+
+		BeforeEach(func() {
+			processGroups = []*fdbtypes.ProcessGroupStatus{
+				{ProcessGroupID: "storage-1", ProcessClass: fdbtypes.ProcessClassStorage},
+				{ProcessGroupID: "storage-2", ProcessClass: fdbtypes.ProcessClassStorage},
+				{ProcessGroupID: "log-1", ProcessClass: fdbtypes.ProcessClassLog, StorageWiggle: &fdbtypes.StorageWiggleStatus{}},
+			}
+			databaseStatus = &fdbtypes.FoundationDBStatus{
+				Cluster: fdbtypes.FoundationDBStatusClusterInfo{
+					Processes: map[string]fdbtypes.FoundationDBStatusProcessInfo{
+						"storage-1-1": storageProcess("storage-1", "1.1.1.1", 1600000000, fdbtypes.StorageEngineSSD2),
+						"storage-1-2": storageProcess("storage-1", "1.1.1.1", 1500000000, fdbtypes.StorageEngineMemory2),
+						"storage-2-1": storageProcess("storage-2", "1.1.1.2", 1600000000, fdbtypes.StorageEngineSSD2),
+						"log-1": {
+							Address:  fdbtypes.ProcessAddress{IPAddress: net.ParseIP("1.1.1.3"), Port: 4501},
+							Locality: map[string]string{fdbtypes.FDBLocalityInstanceIDKey: "log-1"},
+						},
+					},
+					StorageWiggler: fdbtypes.FoundationDBStatusStorageWiggler{
+						WiggleServerAddresses: []fdbtypes.ProcessAddress{
+							{IPAddress: net.ParseIP("1.1.1.2"), Port: 4501, Flags: map[string]bool{"tls": true}},
+						},
+					},
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			updateStorageWiggleStatus(processGroups, databaseStatus, fdbtypes.StorageEngineSSD2)
+		})
+
+		It("should report the oldest storage server", func() {
+			Expect(processGroups[0].StorageWiggle).NotTo(BeNil())
+			Expect(processGroups[0].StorageWiggle.LastRefreshed.Unix()).To(Equal(int64(1500000000)))
+			Expect(processGroups[1].StorageWiggle.LastRefreshed.Unix()).To(Equal(int64(1600000000)))
+		})
+
+		It("should report the storage engine that differs from the database", func() {
+			Expect(processGroups[0].StorageWiggle.StorageEngine).To(Equal(fdbtypes.StorageEngineMemory2))
+			Expect(processGroups[1].StorageWiggle.StorageEngine).To(Equal(fdbtypes.StorageEngineSSD2))
+		})
+
+		It("should report the process groups that are being wiggled", func() {
+			Expect(processGroups[0].StorageWiggle.InProgress).To(BeFalse())
+			Expect(processGroups[1].StorageWiggle.InProgress).To(BeTrue())
+		})
+
+		It("should clear the status for process groups without storage servers", func() {
+			Expect(processGroups[2].StorageWiggle).To(BeNil())
+		})
 	})
 
 	When("removing duplicated entries in process group status", func() {
@@ -476,6 +672,288 @@ var _ = Describe("update_status", func() {
 		})
 	})
 
+	When("building the data center status", func() {
+		var cluster *fdbtypes.FoundationDBCluster
+		var dataCenters []fdbtypes.DataCenterStatus
+
+		BeforeEach(func() {
+			cluster = internal.CreateDefaultCluster()
+			cluster.Spec.DataCenter = "dc1"
+
+			newProcess := func(dcID string, processClass fdbtypes.ProcessClass, ip string, excluded bool, roles ...fdbtypes.ProcessRole) fdbtypes.FoundationDBStatusProcessInfo {
+				roleInfo := make([]fdbtypes.FoundationDBStatusProcessRoleInfo, 0, len(roles))
+				for _, role := range roles {
+					roleInfo = append(roleInfo, fdbtypes.FoundationDBStatusProcessRoleInfo{Role: string(role)})
+				}
+
+				return fdbtypes.FoundationDBStatusProcessInfo{
+					Address:      fdbtypes.ProcessAddress{IPAddress: net.ParseIP(ip), Port: 4501},
+					ProcessClass: processClass,
+					Excluded:     excluded,
+					Locality: map[string]string{
+						fdbtypes.FDBLocalityDCIDKey: dcID,
+					},
+					Roles: roleInfo,
+				}
+			}
+
+			databaseStatus := &fdbtypes.FoundationDBStatus{
+				Client: fdbtypes.FoundationDBStatusLocalClientInfo{
+					Coordinators: fdbtypes.FoundationDBStatusCoordinatorInfo{
+						Coordinators: []fdbtypes.FoundationDBStatusCoordinator{
+							{Address: fdbtypes.ProcessAddress{IPAddress: net.ParseIP("1.1.1.1"), Port: 4501}},
+							{Address: fdbtypes.ProcessAddress{IPAddress: net.ParseIP("1.1.2.1"), Port: 4501}},
+						},
+					},
+				},
+				Cluster: fdbtypes.FoundationDBStatusClusterInfo{
+					Processes: map[string]fdbtypes.FoundationDBStatusProcessInfo{
+						"dc2-storage-1":      newProcess("dc2", fdbtypes.ProcessClassStorage, "1.1.2.1", false, fdbtypes.ProcessRoleStorage, fdbtypes.ProcessRoleCoordinator),
+						"dc1-storage-1":      newProcess("dc1", fdbtypes.ProcessClassStorage, "1.1.1.1", false, fdbtypes.ProcessRoleStorage),
+						"dc1-storage-2":      newProcess("dc1", fdbtypes.ProcessClassStorage, "1.1.1.2", true),
+						"dc1-log-1":          newProcess("dc1", fdbtypes.ProcessClassLog, "1.1.1.3", false, fdbtypes.ProcessRoleLog),
+						"dc1-grv_proxy-1":    newProcess("dc1", fdbtypes.ProcessClassGrvProxy, "1.1.1.4", false, fdbtypes.ProcessRoleGrvProxy),
+						"dc1-commit_proxy-1": newProcess("dc1", fdbtypes.ProcessClassCommitProxy, "1.1.1.5", false, fdbtypes.ProcessRoleCommitProxy, fdbtypes.ProcessRoleResolver),
+						"dc1-commit_proxy-2": newProcess("dc1", fdbtypes.ProcessClassCommitProxy, "1.1.1.6", false, fdbtypes.ProcessRoleCommitProxy),
+						"unknown-1":          newProcess("", fdbtypes.ProcessClassStorage, "1.1.3.1", false),
+					},
+				},
+			}
+
+			dataCenters = getDataCenterStatus(cluster, databaseStatus)
+		})
+
+		It("should report every data center", func() {
+			Expect(dataCenters).To(Equal([]fdbtypes.DataCenterStatus{
+				{
+					ID:                "dc1",
+					Managed:           true,
+					ProcessCounts:     fdbtypes.ProcessCounts{Storage: 2, Log: 1, GrvProxy: 1, CommitProxy: 2},
+					RoleCounts:        fdbtypes.RoleCounts{Storage: 1, Logs: 1, GrvProxies: 1, CommitProxies: 2, Resolvers: 1},
+					ExcludedProcesses: 1,
+					Coordinators:      1,
+				},
+				{
+					ID:            "dc2",
+					ProcessCounts: fdbtypes.ProcessCounts{Storage: 1},
+					RoleCounts:    fdbtypes.RoleCounts{Storage: 1},
+					Coordinators:  1,
+				},
+			}))
+		})
+	})
+
+	When("adding the operator instances", func() {
+		var cluster *fdbtypes.FoundationDBCluster
+		var dataCenters []fdbtypes.DataCenterStatus
+
+		BeforeEach(func() {
+			cluster = internal.CreateDefaultCluster()
+			now := time.Now()
+			dataCenters = []fdbtypes.DataCenterStatus{{ID: "dc1"}, {ID: "dc2"}}
+			addOperatorInstances(cluster, dataCenters, []fdbtypes.OperatorInstance{
+				{ID: "dc1", DataCenter: "dc1", Timestamp: now.Unix()},
+				{ID: "dc2", DataCenter: "dc2", Timestamp: now.Add(-1 * cluster.GetLockDuration()).Unix()},
+				{ID: "dc3", DataCenter: "dc3", Timestamp: now.Unix()},
+			}, now)
+		})
+
+		It("should only report the active instances for known data centers", func() {
+			Expect(dataCenters).To(Equal([]fdbtypes.DataCenterStatus{
+				{ID: "dc1", OperatorInstances: []string{"dc1"}},
+				{ID: "dc2"},
+			}))
+		})
+	})
+
+	When("building the upgrade progress", func() {
+		var cluster *fdbtypes.FoundationDBCluster
+		var databaseStatus *fdbtypes.FoundationDBStatus
+		var progress *fdbtypes.UpgradeProgress
+
+		BeforeEach(func() {
+			cluster = internal.CreateDefaultCluster()
+			cluster.Spec.Version = fdbtypes.Versions.NextMajorVersion.String()
+			cluster.Status.RunningVersion = fdbtypes.Versions.Default.String()
+
+			databaseStatus = &fdbtypes.FoundationDBStatus{
+				Cluster: fdbtypes.FoundationDBStatusClusterInfo{
+					Processes: map[string]fdbtypes.FoundationDBStatusProcessInfo{
+						"storage-1": {Version: fdbtypes.Versions.Default.String()},
+						"storage-2": {Version: fdbtypes.Versions.Default.String()},
+						"log-1":     {Version: fdbtypes.Versions.NextMajorVersion.String()},
+						"log-2":     {},
+					},
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			progress, err = getUpgradeProgress(clusterReconciler, cluster, databaseStatus)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should count the processes per version", func() {
+			Expect(progress).To(Equal(&fdbtypes.UpgradeProgress{
+				Version: fdbtypes.Versions.NextMajorVersion.String(),
+				ProcessVersions: map[string]int{
+					fdbtypes.Versions.Default.String():          2,
+					fdbtypes.Versions.NextMajorVersion.String(): 1,
+				},
+			}))
+		})
+
+		When("locks are enabled", func() {
+			BeforeEach(func() {
+				cluster.Spec.LockOptions.DisableLocks = pointer.Bool(false)
+				lockClient := newMockLockClientUncast(cluster)
+				Expect(lockClient.AddPendingUpgrades(fdbtypes.Versions.NextMajorVersion, []string{"storage-2", "storage-1"})).NotTo(HaveOccurred())
+			})
+
+			It("should report the pending upgrades", func() {
+				Expect(progress.PendingUpgrades).To(Equal([]string{"storage-1", "storage-2"}))
+			})
+		})
+
+		When("all processes run the new version", func() {
+			BeforeEach(func() {
+				cluster.Status.RunningVersion = cluster.Spec.Version
+				databaseStatus.Cluster.Processes = map[string]fdbtypes.FoundationDBStatusProcessInfo{
+					"storage-1": {Version: fdbtypes.Versions.NextMajorVersion.String()},
+				}
+			})
+
+			It("should not report any progress", func() {
+				Expect(progress).To(BeNil())
+			})
+		})
+	})
+
+	When("building the TLS migration status", func() {
+		var cluster *fdbtypes.FoundationDBCluster
+		var databaseStatus *fdbtypes.FoundationDBStatus
+		var migration *fdbtypes.TLSMigrationStatus
+
+		setListeners := func(publicAddress string) {
+			for _, processGroupID := range []string{"storage-1", "storage-2"} {
+				databaseStatus.Cluster.Processes[processGroupID] = fdbtypes.FoundationDBStatusProcessInfo{
+					Address:     fdbtypes.ProcessAddress{IPAddress: net.ParseIP("1.1.1.1"), Port: 4501},
+					CommandLine: "/usr/bin/fdbserver --public_address=" + publicAddress,
+					Locality:    map[string]string{fdbtypes.FDBLocalityInstanceIDKey: processGroupID},
+				}
+			}
+		}
+
+		setCoordinators := func(tls bool) {
+			databaseStatus.Client.Coordinators.Coordinators = []fdbtypes.FoundationDBStatusCoordinator{
+				{Address: fdbtypes.ProcessAddress{IPAddress: net.ParseIP("1.1.1.1"), Port: 4501, Flags: map[string]bool{"tls": tls}}},
+			}
+		}
+
+		BeforeEach(func() {
+			cluster = internal.CreateDefaultCluster()
+			cluster.Spec.MainContainer.EnableTLS = true
+			cluster.Status.ProcessGroups = []*fdbtypes.ProcessGroupStatus{
+				{ProcessGroupID: "storage-1"},
+				{ProcessGroupID: "storage-2"},
+			}
+
+			databaseStatus = &fdbtypes.FoundationDBStatus{
+				Cluster: fdbtypes.FoundationDBStatusClusterInfo{
+					Processes: map[string]fdbtypes.FoundationDBStatusProcessInfo{},
+				},
+			}
+			setListeners("1.1.1.1:4501")
+			setCoordinators(false)
+		})
+
+		JustBeforeEach(func() {
+			migration = getTLSMigrationStatus(clusterReconciler, cluster, databaseStatus)
+		})
+
+		It("should start the migration", func() {
+			Expect(migration).NotTo(BeNil())
+			Expect(migration.TLS).To(BeTrue())
+			Expect(migration.Phase).To(Equal(fdbtypes.TLSMigrationPhaseEnablingDualListeners))
+			Expect(migration.StartTime).NotTo(BeNil())
+		})
+
+		When("the processes listen on the TLS address and the non-TLS address", func() {
+			BeforeEach(func() {
+				cluster.Status.TLSMigration = &fdbtypes.TLSMigrationStatus{TLS: true, Phase: fdbtypes.TLSMigrationPhaseEnablingDualListeners}
+				setListeners("1.1.1.1:4500:tls,1.1.1.1:4501")
+			})
+
+			It("should wait for the coordinator change", func() {
+				Expect(migration.Phase).To(Equal(fdbtypes.TLSMigrationPhaseChangingCoordinators))
+			})
+		})
+
+		When("only some processes listen on the TLS address", func() {
+			BeforeEach(func() {
+				cluster.Status.TLSMigration = &fdbtypes.TLSMigrationStatus{TLS: true, Phase: fdbtypes.TLSMigrationPhaseEnablingDualListeners}
+				databaseStatus.Cluster.Processes["storage-1"] = fdbtypes.FoundationDBStatusProcessInfo{
+					Address:     fdbtypes.ProcessAddress{IPAddress: net.ParseIP("1.1.1.1"), Port: 4500, Flags: map[string]bool{"tls": true}},
+					CommandLine: "/usr/bin/fdbserver --public_address=1.1.1.1:4500:tls,1.1.1.1:4501",
+					Locality:    map[string]string{fdbtypes.FDBLocalityInstanceIDKey: "storage-1"},
+				}
+			})
+
+			It("should stay in the current phase", func() {
+				Expect(migration.Phase).To(Equal(fdbtypes.TLSMigrationPhaseEnablingDualListeners))
+			})
+		})
+
+		When("the coordinators use TLS", func() {
+			BeforeEach(func() {
+				cluster.Status.TLSMigration = &fdbtypes.TLSMigrationStatus{TLS: true, Phase: fdbtypes.TLSMigrationPhaseChangingCoordinators}
+				setListeners("1.1.1.1:4500:tls,1.1.1.1:4501")
+				setCoordinators(true)
+			})
+
+			It("should remove the old listener", func() {
+				Expect(migration.Phase).To(Equal(fdbtypes.TLSMigrationPhaseRemovingOldListener))
+			})
+		})
+
+		When("the processes only listen on the TLS address", func() {
+			BeforeEach(func() {
+				cluster.Status.TLSMigration = &fdbtypes.TLSMigrationStatus{TLS: true, Phase: fdbtypes.TLSMigrationPhaseRemovingOldListener}
+				setListeners("1.1.1.1:4500:tls")
+				setCoordinators(true)
+			})
+
+			It("should complete the migration", func() {
+				Expect(migration).To(BeNil())
+			})
+		})
+
+		When("the spec is changed back during the migration", func() {
+			BeforeEach(func() {
+				cluster.Spec.MainContainer.EnableTLS = false
+				cluster.Status.TLSMigration = &fdbtypes.TLSMigrationStatus{TLS: true, Phase: fdbtypes.TLSMigrationPhaseChangingCoordinators}
+				setListeners("1.1.1.1:4500:tls,1.1.1.1:4501")
+			})
+
+			It("should start a new migration", func() {
+				Expect(migration.TLS).To(BeFalse())
+				Expect(migration.Phase).To(Equal(fdbtypes.TLSMigrationPhaseEnablingDualListeners))
+			})
+		})
+
+		When("the processes and coordinators already use TLS", func() {
+			BeforeEach(func() {
+				setListeners("1.1.1.1:4500:tls")
+				setCoordinators(true)
+			})
+
+			It("should not start a migration", func() {
+				Expect(migration).To(BeNil())
+			})
+		})
+	})
+
 	Describe("Reconcile", func() {
 		var cluster *fdbtypes.FoundationDBCluster
 		var err error
@@ -510,6 +988,47 @@ var _ = Describe("update_status", func() {
 			Expect(cluster.Status.Generations.Reconciled).To(Equal(cluster.ObjectMeta.Generation))
 		})
 
+		When("an upgrade check is requested", func() {
+			BeforeEach(func() {
+				cluster.Annotations = map[string]string{
+					fdbtypes.UpgradeCheckVersionAnnotation: fdbtypes.Versions.NextPatchVersion.String(),
+					fdbtypes.UpgradeCheckRequestAnnotation: "request-1",
+				}
+				err = k8sClient.Update(context.TODO(), cluster)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should report the result for the request", func() {
+				Expect(cluster.Status.UpgradeCheck).NotTo(BeNil())
+				Expect(cluster.Status.UpgradeCheck.Version).To(Equal(fdbtypes.Versions.NextPatchVersion.String()))
+				Expect(cluster.Status.UpgradeCheck.RequestID).To(Equal("request-1"))
+			})
+
+			It("should remove the request", func() {
+				Expect(cluster.Annotations).NotTo(HaveKey(fdbtypes.UpgradeCheckVersionAnnotation))
+				Expect(cluster.Annotations).NotTo(HaveKey(fdbtypes.UpgradeCheckRequestAnnotation))
+			})
+
+			When("reconciling the status again", func() {
+				var timestamp *metav1.Time
+
+				JustBeforeEach(func() {
+					timestamp = cluster.Status.UpgradeCheck.Timestamp
+					err = internal.NormalizeClusterSpec(cluster, internal.DeprecationOptions{})
+					Expect(err).NotTo(HaveOccurred())
+					requeue = updateStatus{}.reconcile(context.TODO(), clusterReconciler, cluster)
+					Expect(requeue).To(BeNil())
+					_, err = reloadCluster(cluster)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("should keep the previous result", func() {
+					Expect(cluster.Status.UpgradeCheck.RequestID).To(Equal("request-1"))
+					Expect(cluster.Status.UpgradeCheck.Timestamp).To(Equal(timestamp))
+				})
+			})
+		})
+
 		When("enabling an explicit listen address", func() {
 			BeforeEach(func() {
 				enabled := false
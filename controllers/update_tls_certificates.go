@@ -0,0 +1,162 @@
+/*
+ * update_tls_certificates.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2021 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CertificateIssuer provides TLS certificates for the processes in a cluster.
+type CertificateIssuer interface {
+	// IssueCertificate issues a new certificate for a cluster. The returned
+	// data must contain the PEM encoded certificate in the tls.crt key and
+	// the PEM encoded private key in the tls.key key.
+	IssueCertificate(ctx context.Context, cluster *fdbtypes.FoundationDBCluster) (map[string][]byte, error)
+}
+
+// RegisterCertificateIssuer registers an issuer that can be referenced by name
+// in the TLS certificate config of a cluster.
+func (r *FoundationDBClusterReconciler) RegisterCertificateIssuer(name string, issuer CertificateIssuer) error {
+	if name == "" {
+		return fmt.Errorf("certificate issuer must have a name")
+	}
+
+	if issuer == nil {
+		return fmt.Errorf("certificate issuer %s must not be nil", name)
+	}
+
+	if _, present := r.CertificateIssuers[name]; present {
+		return fmt.Errorf("certificate issuer %s is already registered", name)
+	}
+
+	if r.CertificateIssuers == nil {
+		r.CertificateIssuers = make(map[string]CertificateIssuer)
+	}
+	r.CertificateIssuers[name] = issuer
+
+	return nil
+}
+
+// updateTLSCertificates provides a reconciliation step for issuing TLS
+// certificates and tracking the certificate in the TLS secret.
+type updateTLSCertificates struct{}
+
+// reconcile runs the reconciler's work.
+func (updateTLSCertificates) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbtypes.FoundationDBCluster) *requeue {
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "updateTLSCertificates")
+
+	config := cluster.Spec.TLSCertificates
+	if config == nil {
+		if cluster.Status.TLSCertificate == nil {
+			return nil
+		}
+
+		cluster.Status.TLSCertificate = nil
+		err := r.Status().Update(ctx, cluster)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+		return nil
+	}
+
+	secretName := config.GetSecretName(cluster.Name)
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: secretName}, secret)
+	secretMissing := k8serrors.IsNotFound(err)
+	if err != nil && !secretMissing {
+		return &requeue{curError: err}
+	}
+
+	var certificate *fdbtypes.TLSCertificateStatus
+	var certificateErr error
+	if secretMissing {
+		certificateErr = fmt.Errorf("TLS secret %s/%s does not exist", cluster.Namespace, secretName)
+	} else {
+		certificate, certificateErr = internal.GetTLSCertificateStatus(secret)
+	}
+
+	needsCertificate := certificateErr != nil || time.Until(certificate.NotAfter.Time) < config.GetRenewBefore()
+	if config.Issuer != "" && needsCertificate {
+		issuer, present := r.CertificateIssuers[config.Issuer]
+		if !present {
+			return &requeue{curError: fmt.Errorf("unknown certificate issuer %s", config.Issuer)}
+		}
+
+		data, err := issuer.IssueCertificate(ctx, cluster)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		logger.Info("Issued TLS certificate", "issuer", config.Issuer)
+		r.Recorder.Event(cluster, corev1.EventTypeNormal, "IssuedTLSCertificate", fmt.Sprintf("Issued a new TLS certificate through %s", config.Issuer))
+
+		if secretMissing {
+			secret = &corev1.Secret{
+				ObjectMeta: internal.GetObjectMetadata(cluster, nil, "", ""),
+				Type:       corev1.SecretTypeTLS,
+			}
+			secret.Name = secretName
+			secret.OwnerReferences = internal.BuildOwnerReference(cluster.TypeMeta, cluster.ObjectMeta)
+			secret.Data = data
+			err = r.Create(ctx, secret)
+		} else {
+			secret.Data = data
+			err = r.Update(ctx, secret)
+		}
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		certificate, certificateErr = internal.GetTLSCertificateStatus(secret)
+	}
+
+	if certificateErr != nil {
+		return &requeue{curError: certificateErr}
+	}
+
+	if equality.Semantic.DeepEqual(cluster.Status.TLSCertificate, certificate) {
+		return nil
+	}
+
+	if cluster.Status.TLSCertificate != nil && cluster.Status.TLSCertificate.Hash != certificate.Hash {
+		logger.Info("Detected new TLS certificate", "notAfter", certificate.NotAfter)
+		r.Recorder.Event(cluster, corev1.EventTypeNormal, "TLSCertificateChanged",
+			fmt.Sprintf("Detected a new TLS certificate that expires at %s", certificate.NotAfter.UTC().Format(time.RFC3339)))
+	}
+
+	cluster.Status.TLSCertificate = certificate
+	err = r.Status().Update(ctx, cluster)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	return nil
+}
@@ -0,0 +1,238 @@
+/*
+ * update_tls_certificates_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2021 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"k8s.io/utils/pointer"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type mockCertificateIssuer struct {
+	notAfter time.Time
+	issued   int
+}
+
+// IssueCertificate issues a new self-signed certificate.
+func (issuer *mockCertificateIssuer) IssueCertificate(_ context.Context, _ *fdbtypes.FoundationDBCluster) (map[string][]byte, error) {
+	issuer.issued++
+	return internal.CreateTestTLSCertificate(issuer.notAfter)
+}
+
+var _ = Describe("update_tls_certificates", func() {
+	var cluster *fdbtypes.FoundationDBCluster
+	var requeue *requeue
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+	})
+
+	Context("with a secret from the user", func() {
+		var secret *corev1.Secret
+
+		BeforeEach(func() {
+			data, err := internal.CreateTestTLSCertificate(time.Now().Add(60 * 24 * time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			secret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: cluster.Namespace, Name: "fdb-certs"},
+				Data:       data,
+			}
+			Expect(k8sClient.Create(context.TODO(), secret)).NotTo(HaveOccurred())
+
+			cluster.Spec.TLSCertificates = &fdbtypes.TLSCertificateConfig{SecretName: "fdb-certs"}
+			err = setupClusterForTest(cluster)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should record the certificate in the status", func() {
+			Expect(cluster.Status.TLSCertificate).NotTo(BeNil())
+			Expect(cluster.Status.TLSCertificate.NotAfter).NotTo(BeNil())
+
+			for _, processGroup := range cluster.Status.ProcessGroups {
+				Expect(processGroup.TLSCertificate).To(Equal(cluster.Status.TLSCertificate))
+				Expect(processGroup.GetConditionTime(fdbtypes.OutdatedTLSCertificate)).To(BeNil())
+			}
+		})
+
+		When("the certificate in the secret changes", func() {
+			var previousHash string
+
+			BeforeEach(func() {
+				previousHash = cluster.Status.TLSCertificate.Hash
+
+				data, err := internal.CreateTestTLSCertificate(time.Now().Add(90 * 24 * time.Hour))
+				Expect(err).NotTo(HaveOccurred())
+				secret.Data = data
+				Expect(k8sClient.Update(context.TODO(), secret)).NotTo(HaveOccurred())
+			})
+
+			JustBeforeEach(func() {
+				requeue = updateTLSCertificates{}.reconcile(context.TODO(), clusterReconciler, cluster)
+				Expect(requeue).To(BeNil())
+				Expect(updateStatus{}.reconcile(context.TODO(), clusterReconciler, cluster)).To(BeNil())
+			})
+
+			It("should mark all process groups as outdated", func() {
+				Expect(cluster.Status.TLSCertificate.Hash).NotTo(Equal(previousHash))
+
+				for _, processGroup := range cluster.Status.ProcessGroups {
+					Expect(processGroup.TLSCertificate.Hash).To(Equal(previousHash))
+					Expect(processGroup.GetConditionTime(fdbtypes.OutdatedTLSCertificate)).NotTo(BeNil())
+				}
+			})
+
+			When("the processes are bounced", func() {
+				var adminClient *mockAdminClient
+
+				BeforeEach(func() {
+					var err error
+					adminClient, err = newMockAdminClientUncast(cluster, k8sClient)
+					Expect(err).NotTo(HaveOccurred())
+					adminClient.MockLocalityInfo("cluster_controller-1", map[string]string{fdbtypes.FDBLocalityZoneIDKey: "zone-1"})
+					adminClient.MockLocalityInfo("log-1", map[string]string{fdbtypes.FDBLocalityZoneIDKey: "zone-1"})
+				})
+
+				JustBeforeEach(func() {
+					requeue = bounceProcesses{}.reconcile(context.TODO(), clusterReconciler, cluster)
+				})
+
+				It("should only bounce the processes in one fault domain", func() {
+					Expect(requeue).To(BeNil())
+					Expect(adminClient.KilledAddresses).To(HaveLen(2))
+
+					for _, processGroup := range cluster.Status.ProcessGroups {
+						rotated := processGroup.ProcessGroupID == "cluster_controller-1" || processGroup.ProcessGroupID == "log-1"
+						Expect(processGroup.TLSCertificate.Hash == cluster.Status.TLSCertificate.Hash).To(Equal(rotated))
+						Expect(processGroup.GetConditionTime(fdbtypes.OutdatedTLSCertificate) == nil).To(Equal(rotated))
+					}
+				})
+
+				When("the cluster doesn't have the desired fault tolerance", func() {
+					BeforeEach(func() {
+						adminClient.maxZoneFailuresWithoutLosingData = pointer.Int(0)
+					})
+
+					It("should not bounce any processes", func() {
+						Expect(requeue).NotTo(BeNil())
+						Expect(requeue.curError).To(BeNil())
+						Expect(adminClient.KilledAddresses).To(BeEmpty())
+					})
+				})
+			})
+		})
+	})
+
+	Context("with a certificate issuer", func() {
+		var issuer *mockCertificateIssuer
+
+		BeforeEach(func() {
+			issuer = &mockCertificateIssuer{notAfter: time.Now().Add(60 * 24 * time.Hour)}
+			err := clusterReconciler.RegisterCertificateIssuer("test", issuer)
+			Expect(err).NotTo(HaveOccurred())
+
+			cluster.Spec.TLSCertificates = &fdbtypes.TLSCertificateConfig{Issuer: "test"}
+			err = setupClusterForTest(cluster)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			clusterReconciler.CertificateIssuers = nil
+		})
+
+		It("should store the certificate in a secret", func() {
+			Expect(issuer.issued).To(Equal(1))
+
+			secret := &corev1.Secret{}
+			err := k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: cluster.Namespace, Name: "operator-test-1-tls"}, secret)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(secret.Type).To(Equal(corev1.SecretTypeTLS))
+			Expect(secret.OwnerReferences).To(HaveLen(1))
+			Expect(secret.Data).To(HaveKey(corev1.TLSCertKey))
+			Expect(secret.Data).To(HaveKey(corev1.TLSPrivateKeyKey))
+		})
+
+		When("the certificate is about to expire", func() {
+			var previousHash string
+
+			BeforeEach(func() {
+				previousHash = cluster.Status.TLSCertificate.Hash
+				cluster.Spec.TLSCertificates.RenewBeforeSeconds = pointer.Int(90 * 24 * 60 * 60)
+				issuer.notAfter = time.Now().Add(120 * 24 * time.Hour)
+				requeue = updateTLSCertificates{}.reconcile(context.TODO(), clusterReconciler, cluster)
+			})
+
+			It("should issue a new certificate", func() {
+				Expect(requeue).To(BeNil())
+				Expect(issuer.issued).To(Equal(2))
+				Expect(cluster.Status.TLSCertificate.Hash).NotTo(Equal(previousHash))
+			})
+		})
+
+		When("the issuer is unknown", func() {
+			BeforeEach(func() {
+				cluster.Spec.TLSCertificates.Issuer = "missing"
+				cluster.Spec.TLSCertificates.RenewBeforeSeconds = pointer.Int(90 * 24 * 60 * 60)
+				requeue = updateTLSCertificates{}.reconcile(context.TODO(), clusterReconciler, cluster)
+			})
+
+			It("should return an error", func() {
+				Expect(requeue).NotTo(BeNil())
+				Expect(requeue.curError).To(MatchError("unknown certificate issuer missing"))
+			})
+		})
+	})
+
+	Describe("registering a certificate issuer", func() {
+		var reconciler *FoundationDBClusterReconciler
+
+		BeforeEach(func() {
+			reconciler = createTestClusterReconciler()
+		})
+
+		It("should register the issuer", func() {
+			issuer := &mockCertificateIssuer{}
+			Expect(reconciler.RegisterCertificateIssuer("test", issuer)).To(Succeed())
+			Expect(reconciler.CertificateIssuers).To(HaveKeyWithValue("test", issuer))
+		})
+
+		It("should reject an issuer without a name", func() {
+			Expect(reconciler.RegisterCertificateIssuer("", &mockCertificateIssuer{})).To(MatchError("certificate issuer must have a name"))
+		})
+
+		It("should reject a missing issuer", func() {
+			Expect(reconciler.RegisterCertificateIssuer("test", nil)).To(MatchError("certificate issuer test must not be nil"))
+		})
+
+		It("should reject a duplicate issuer", func() {
+			Expect(reconciler.RegisterCertificateIssuer("test", &mockCertificateIssuer{})).To(Succeed())
+			Expect(reconciler.RegisterCertificateIssuer("test", &mockCertificateIssuer{})).To(MatchError("certificate issuer test is already registered"))
+		})
+	})
+})
@@ -27,6 +27,7 @@ import (
 	"os"
 	"os/exec"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -613,3 +614,80 @@ func (client *cliAdminClient) GetCoordinatorSet() (map[string]struct{}, error) {
 func (client *cliAdminClient) SetKnobs(knobs []string) {
 	client.knobs = knobs
 }
+
+// SetRuntimeKnobs sets knobs in the configuration database.
+func (client *cliAdminClient) SetRuntimeKnobs(knobs map[string]string) error {
+	if len(knobs) == 0 {
+		return nil
+	}
+
+	_, err := client.runCommand(cliCommand{command: getRuntimeKnobsCommand(knobs)})
+	return err
+}
+
+// GetRuntimeKnobs reads knobs from the configuration database.
+func (client *cliAdminClient) GetRuntimeKnobs(names []string) (map[string]string, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	commands := make([]string, 0, len(names))
+	for _, name := range names {
+		commands = append(commands, fmt.Sprintf("getknob %s", name))
+	}
+
+	output, err := client.runCommand(cliCommand{command: strings.Join(commands, "; ")})
+	if err != nil {
+		return nil, err
+	}
+
+	return parseRuntimeKnobs(output), nil
+}
+
+// runtimeKnobPattern matches the output of getknob for a knob that is set.
+var runtimeKnobPattern = regexp.MustCompile("`([^`']+)' is `(.*)'")
+
+// parseRuntimeKnobs parses the output of getknob commands. Knobs that are not
+// set are left out of the result.
+func parseRuntimeKnobs(output string) map[string]string {
+	knobs := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		match := runtimeKnobPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		// The configuration database stores the knobs as tuples, so string
+		// values are printed in quotes.
+		knobs[match[1]] = strings.Trim(match[2], "\"")
+	}
+
+	return knobs
+}
+
+// getRuntimeKnobsCommand builds the fdbcli command that sets knobs in the
+// configuration database in a single transaction. An empty value clears the
+// knob.
+//
+// fdbcli can report a failed commit only in its output, so the operator reads
+// the knobs back with GetRuntimeKnobs to confirm the change.
+func getRuntimeKnobsCommand(knobs map[string]string) string {
+	names := make([]string, 0, len(knobs))
+	for name := range knobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	commands := make([]string, 0, len(knobs)+2)
+	commands = append(commands, "begin")
+	for _, name := range names {
+		if knobs[name] == "" {
+			commands = append(commands, fmt.Sprintf("clearknob %s", name))
+		} else {
+			commands = append(commands, fmt.Sprintf("setknob %s %s", name, knobs[name]))
+		}
+	}
+	commands = append(commands, "commit")
+
+	return strings.Join(commands, "; ")
+}
@@ -106,6 +106,26 @@ var _ = Describe("admin_client_test", func() {
 			expectedErr error
 		}
 
+		Describe("getRuntimeKnobsCommand", func() {
+			It("should set and clear the knobs in one transaction", func() {
+				command := getRuntimeKnobsCommand(map[string]string{
+					"min_trace_severity": "",
+					"max_trace_lines":    "100000",
+				})
+				Expect(command).To(Equal("begin; setknob max_trace_lines 100000; clearknob min_trace_severity; commit"))
+			})
+		})
+
+		Describe("parseRuntimeKnobs", func() {
+			It("should parse the knobs that are set", func() {
+				knobs := parseRuntimeKnobs("`max_trace_lines' is `100000'\n`min_trace_severity' is not found\n`trace_format' is `\"json\"'\n")
+				Expect(knobs).To(Equal(map[string]string{
+					"max_trace_lines": "100000",
+					"trace_format":    "json",
+				}))
+			})
+		})
+
 		DescribeTable("Test remove warnings in JSON string",
 			func(tc testCase) {
 				result, err := removeWarningsInJSON(tc.input)
@@ -21,6 +21,7 @@
 package fdbclient
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -278,6 +279,59 @@ func (client *realLockClient) UpdateDenyList(locks []fdbtypes.LockDenyListEntry)
 	return err
 }
 
+// UpdateOperatorInstance registers an instance of the operator in the
+// database.
+func (client *realLockClient) UpdateOperatorInstance(instance fdbtypes.OperatorInstance) error {
+	value, err := json.Marshal(instance)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.database.Transact(func(tr fdb.Transaction) (interface{}, error) {
+		err := tr.Options().SetAccessSystemKeys()
+		if err != nil {
+			return nil, err
+		}
+
+		tr.Set(fdb.Key(fmt.Sprintf("%s/instances/%s", client.cluster.GetLockPrefix(), instance.ID)), value)
+		return nil, nil
+	})
+	return err
+}
+
+// GetOperatorInstances returns the instances of the operator that are
+// registered in the database.
+func (client *realLockClient) GetOperatorInstances() ([]fdbtypes.OperatorInstance, error) {
+	instances, err := client.database.Transact(func(tr fdb.Transaction) (interface{}, error) {
+		err := tr.Options().SetReadSystemKeys()
+		if err != nil {
+			return nil, err
+		}
+
+		keyRange, err := fdb.PrefixRange([]byte(fmt.Sprintf("%s/instances/", client.cluster.GetLockPrefix())))
+		if err != nil {
+			return nil, err
+		}
+
+		results := tr.GetRange(keyRange, fdb.RangeOptions{}).GetSliceOrPanic()
+		instances := make([]fdbtypes.OperatorInstance, 0, len(results))
+		for _, result := range results {
+			instance := fdbtypes.OperatorInstance{}
+			err = json.Unmarshal(result.Value, &instance)
+			if err != nil {
+				return nil, err
+			}
+			instances = append(instances, instance)
+		}
+		return instances, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return instances.([]fdbtypes.OperatorInstance), nil
+}
+
 // getDenyListKeyRange defines a key range containing the full deny list.
 func (client *realLockClient) getDenyListKeyRange() (fdb.KeyRange, error) {
 	keyPrefix := []byte(fmt.Sprintf("%s/denyList/", client.cluster.GetLockPrefix()))
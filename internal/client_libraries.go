@@ -0,0 +1,218 @@
+/*
+ * client_libraries.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2021 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ClientLibrariesVolumeName defines the name of the volume that holds the
+	// client libraries in application pods.
+	ClientLibrariesVolumeName = "fdb-client-libraries"
+
+	// ClientLibrariesMountPath defines the path where the client libraries
+	// are mounted in application containers.
+	ClientLibrariesMountPath = "/var/fdb-client-libraries"
+
+	// ExternalClientDirectoryEnv defines the environment variable that tells
+	// the FoundationDB client where to find the additional client libraries.
+	ExternalClientDirectoryEnv = "FDB_NETWORK_OPTION_EXTERNAL_CLIENT_DIRECTORY"
+)
+
+// GetClientLibraryConfigMapName returns the name of the config map that lists
+// the published client libraries of a cluster.
+func GetClientLibraryConfigMapName(clusterName string) string {
+	return fmt.Sprintf("%s-client-libraries", clusterName)
+}
+
+// GetClientLibraryVersions returns the versions whose client libraries should
+// be published for a cluster. This contains the newest of the requested
+// versions for every minor version, since the client libraries are compatible
+// within a minor version.
+func GetClientLibraryVersions(cluster *fdbtypes.FoundationDBCluster) ([]fdbtypes.FdbVersion, error) {
+	versionStrings := []string{
+		cluster.Status.RunningVersion,
+		cluster.Spec.Version,
+		cluster.Annotations[fdbtypes.UpgradeCheckVersionAnnotation],
+	}
+	// The request for an upgrade check is removed once the result is
+	// reported, so the version from the last result is kept as well.
+	if cluster.Status.UpgradeCheck != nil {
+		versionStrings = append(versionStrings, cluster.Status.UpgradeCheck.Version)
+	}
+	versionStrings = append(versionStrings, cluster.Spec.ClientLibraries.AdditionalVersions...)
+
+	versionsByMinor := make(map[string]fdbtypes.FdbVersion)
+	for _, versionString := range versionStrings {
+		if versionString == "" {
+			continue
+		}
+
+		version, err := fdbtypes.ParseFdbVersion(versionString)
+		if err != nil {
+			return nil, err
+		}
+
+		minorVersion := getMinorVersion(version)
+		current, present := versionsByMinor[minorVersion]
+		if !present || version.IsAtLeast(current) {
+			versionsByMinor[minorVersion] = version
+		}
+	}
+
+	versions := make([]fdbtypes.FdbVersion, 0, len(versionsByMinor))
+	for _, version := range versionsByMinor {
+		versions = append(versions, version)
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return !versions[i].IsAtLeast(versions[j])
+	})
+
+	return versions, nil
+}
+
+// GetClientLibraryConfigMap builds the config map that lists the published
+// client libraries of a cluster. The config map maps every minor version to
+// the sidecar image that provides the client library.
+func GetClientLibraryConfigMap(cluster *fdbtypes.FoundationDBCluster) (*corev1.ConfigMap, error) {
+	versions, err := GetClientLibraryVersions(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	processSettings := cluster.GetProcessSettings(fdbtypes.ProcessClassGeneral)
+	allowOverride := processSettings.GetAllowTagOverride()
+
+	var sidecarImage string
+	if processSettings.PodTemplate != nil {
+		for _, container := range processSettings.PodTemplate.Spec.Containers {
+			if container.Name == "foundationdb-kubernetes-sidecar" {
+				sidecarImage = container.Image
+			}
+		}
+	}
+
+	data := make(map[string]string, len(versions))
+	for _, version := range versions {
+		image, err := GetImage(sidecarImage, cluster.Spec.SidecarContainer.ImageConfigs, version.String(), allowOverride)
+		if err != nil {
+			return nil, err
+		}
+
+		data[getMinorVersion(version)] = image
+	}
+
+	metadata := GetObjectMetadata(cluster, nil, "", "")
+	metadata.Name = GetClientLibraryConfigMapName(cluster.Name)
+	metadata.OwnerReferences = BuildOwnerReference(cluster.TypeMeta, cluster.ObjectMeta)
+
+	return &corev1.ConfigMap{
+		ObjectMeta: metadata,
+		Data:       data,
+	}, nil
+}
+
+// GetPublishedClientLibraryConfigMap builds the copy of the client library
+// config map that the operator publishes in the namespace of a client config
+// target, so that the libraries can be injected into pods in that namespace.
+func GetPublishedClientLibraryConfigMap(cluster *fdbtypes.FoundationDBCluster, namespace string) (*corev1.ConfigMap, error) {
+	configMap, err := GetClientLibraryConfigMap(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	configMap.ObjectMeta = GetClientConfigMetadata(cluster, fdbtypes.ClientConfigTarget{
+		Namespace: namespace,
+		Name:      configMap.Name,
+	})
+
+	return configMap, nil
+}
+
+// InjectClientLibraries adds init containers to a pod that copy the client
+// libraries from the config map into a shared volume, and points the
+// containers of the pod to that volume. This returns false if the pod already
+// has the client libraries.
+func InjectClientLibraries(pod *corev1.Pod, configMap *corev1.ConfigMap) bool {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Name == ClientLibrariesVolumeName {
+			return false
+		}
+	}
+
+	minorVersions := make([]string, 0, len(configMap.Data))
+	for minorVersion := range configMap.Data {
+		minorVersions = append(minorVersions, minorVersion)
+	}
+	sort.Strings(minorVersions)
+
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		Name:         ClientLibrariesVolumeName,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	})
+
+	readOnlyRootFilesystem := true
+	for _, minorVersion := range minorVersions {
+		pod.Spec.InitContainers = append(pod.Spec.InitContainers, corev1.Container{
+			Name:  fmt.Sprintf("foundationdb-client-library-%s", strings.ReplaceAll(minorVersion, ".", "-")),
+			Image: configMap.Data[minorVersion],
+			Args: []string{
+				"--copy-library", minorVersion,
+				"--output-dir", "/var/output-files",
+				"--init-mode",
+			},
+			VolumeMounts: []corev1.VolumeMount{
+				{Name: ClientLibrariesVolumeName, MountPath: "/var/output-files"},
+			},
+			SecurityContext: &corev1.SecurityContext{
+				ReadOnlyRootFilesystem: &readOnlyRootFilesystem,
+			},
+		})
+	}
+
+	for index := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[index]
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      ClientLibrariesVolumeName,
+			MountPath: ClientLibrariesMountPath,
+			ReadOnly:  true,
+		})
+
+		extendEnv(container, corev1.EnvVar{
+			Name:  ExternalClientDirectoryEnv,
+			Value: fmt.Sprintf("%s/lib/multiversion", ClientLibrariesMountPath),
+		})
+	}
+
+	return true
+}
+
+// getMinorVersion returns the major and minor version as a string.
+func getMinorVersion(version fdbtypes.FdbVersion) string {
+	return fmt.Sprintf("%d.%d", version.Major, version.Minor)
+}
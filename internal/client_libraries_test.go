@@ -0,0 +1,176 @@
+/*
+ * client_libraries_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2021 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("client_libraries", func() {
+	var cluster *fdbtypes.FoundationDBCluster
+
+	BeforeEach(func() {
+		cluster = CreateDefaultCluster()
+		err := NormalizeClusterSpec(cluster, DeprecationOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		cluster.Status.RunningVersion = "6.2.20"
+		cluster.Spec.Version = "6.3.13"
+	})
+
+	Describe("GetClientLibraryVersions", func() {
+		It("should include the running version and the target version", func() {
+			versions, err := GetClientLibraryVersions(cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(Equal([]fdbtypes.FdbVersion{
+				{Major: 6, Minor: 2, Patch: 20},
+				{Major: 6, Minor: 3, Patch: 13},
+			}))
+		})
+
+		When("the versions share a minor version", func() {
+			BeforeEach(func() {
+				cluster.Spec.Version = "6.2.21"
+				cluster.Spec.ClientLibraries.AdditionalVersions = []string{"6.2.19", "6.1.12"}
+			})
+
+			It("should use the newest version for every minor version", func() {
+				versions, err := GetClientLibraryVersions(cluster)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(versions).To(Equal([]fdbtypes.FdbVersion{
+					{Major: 6, Minor: 1, Patch: 12},
+					{Major: 6, Minor: 2, Patch: 21},
+				}))
+			})
+		})
+
+		When("an upgrade check is requested", func() {
+			BeforeEach(func() {
+				cluster.Spec.Version = cluster.Status.RunningVersion
+				cluster.Annotations = map[string]string{
+					fdbtypes.UpgradeCheckVersionAnnotation: "7.0.0",
+				}
+			})
+
+			It("should include the version from the upgrade check", func() {
+				versions, err := GetClientLibraryVersions(cluster)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(versions).To(Equal([]fdbtypes.FdbVersion{
+					{Major: 6, Minor: 2, Patch: 20},
+					{Major: 7, Minor: 0, Patch: 0},
+				}))
+			})
+		})
+
+		When("an upgrade check has been reported", func() {
+			BeforeEach(func() {
+				cluster.Spec.Version = cluster.Status.RunningVersion
+				cluster.Status.UpgradeCheck = &fdbtypes.UpgradeCheckStatus{Version: "7.0.0"}
+			})
+
+			It("should include the version from the upgrade check", func() {
+				versions, err := GetClientLibraryVersions(cluster)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(versions).To(Equal([]fdbtypes.FdbVersion{
+					{Major: 6, Minor: 2, Patch: 20},
+					{Major: 7, Minor: 0, Patch: 0},
+				}))
+			})
+		})
+
+		When("an additional version is invalid", func() {
+			BeforeEach(func() {
+				cluster.Spec.ClientLibraries.AdditionalVersions = []string{"invalid"}
+			})
+
+			It("should return an error", func() {
+				_, err := GetClientLibraryVersions(cluster)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("GetClientLibraryConfigMap", func() {
+		It("should map the minor versions to the sidecar images", func() {
+			configMap, err := GetClientLibraryConfigMap(cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(configMap.Name).To(Equal("operator-test-1-client-libraries"))
+			Expect(configMap.Namespace).To(Equal(cluster.Namespace))
+			Expect(configMap.OwnerReferences).To(HaveLen(1))
+			Expect(configMap.Data).To(Equal(map[string]string{
+				"6.2": "foundationdb/foundationdb-kubernetes-sidecar:6.2.20-1",
+				"6.3": "foundationdb/foundationdb-kubernetes-sidecar:6.3.13-1",
+			}))
+		})
+	})
+
+	Describe("InjectClientLibraries", func() {
+		var pod *corev1.Pod
+		var configMap *corev1.ConfigMap
+
+		BeforeEach(func() {
+			pod = &corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "app"},
+					},
+				},
+			}
+
+			var err error
+			configMap, err = GetClientLibraryConfigMap(cluster)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should add an init container for every minor version", func() {
+			Expect(InjectClientLibraries(pod, configMap)).To(BeTrue())
+			Expect(pod.Spec.InitContainers).To(HaveLen(2))
+			Expect(pod.Spec.InitContainers[0].Name).To(Equal("foundationdb-client-library-6-2"))
+			Expect(pod.Spec.InitContainers[0].Image).To(Equal("foundationdb/foundationdb-kubernetes-sidecar:6.2.20-1"))
+			Expect(pod.Spec.InitContainers[0].Args).To(Equal([]string{
+				"--copy-library", "6.2",
+				"--output-dir", "/var/output-files",
+				"--init-mode",
+			}))
+			Expect(pod.Spec.InitContainers[1].Name).To(Equal("foundationdb-client-library-6-3"))
+		})
+
+		It("should point the containers to the client libraries", func() {
+			Expect(InjectClientLibraries(pod, configMap)).To(BeTrue())
+			Expect(pod.Spec.Volumes).To(HaveLen(1))
+			Expect(pod.Spec.Volumes[0].Name).To(Equal(ClientLibrariesVolumeName))
+			Expect(pod.Spec.Containers[0].VolumeMounts).To(Equal([]corev1.VolumeMount{
+				{Name: ClientLibrariesVolumeName, MountPath: ClientLibrariesMountPath, ReadOnly: true},
+			}))
+			Expect(pod.Spec.Containers[0].Env).To(Equal([]corev1.EnvVar{
+				{Name: ExternalClientDirectoryEnv, Value: "/var/fdb-client-libraries/lib/multiversion"},
+			}))
+		})
+
+		It("should not inject the client libraries twice", func() {
+			Expect(InjectClientLibraries(pod, configMap)).To(BeTrue())
+			Expect(InjectClientLibraries(pod, configMap)).To(BeFalse())
+			Expect(pod.Spec.InitContainers).To(HaveLen(2))
+		})
+	})
+})
@@ -43,16 +43,9 @@ func GetConfigMap(cluster *v1beta1.FoundationDBCluster) (*corev1.ConfigMap, erro
 	data[ClusterFileKey] = connectionString
 	data["running-version"] = cluster.Status.RunningVersion
 
-	var caFile strings.Builder
-	for _, ca := range cluster.Spec.TrustedCAs {
-		if caFile.Len() > 0 {
-			caFile.WriteString("\n")
-		}
-		caFile.WriteString(ca)
-	}
-
-	if caFile.Len() > 0 {
-		data["ca-file"] = caFile.String()
+	caFile := getCAFile(cluster)
+	if caFile != "" {
+		data["ca-file"] = caFile
 	}
 
 	desiredCountStruct, err := cluster.GetProcessCountsWithDefaults()
@@ -73,15 +66,30 @@ func GetConfigMap(cluster *v1beta1.FoundationDBCluster) (*corev1.ConfigMap, erro
 		storageServersPerDisk = []int{cluster.GetStorageServersPerPod()}
 	}
 
+	logServersPerDisk := cluster.Status.LogServersPerDisk
+	if len(cluster.Status.LogServersPerDisk) == 0 {
+		logServersPerDisk = []int{cluster.GetLogServersPerPod()}
+	}
+
+	getServersPerPodOptions := func(processClass v1beta1.ProcessClass) []int {
+		if processClass == v1beta1.ProcessClassStorage {
+			return storageServersPerDisk
+		}
+		if processClass.IsLogProcess() {
+			return logServersPerDisk
+		}
+		return []int{1}
+	}
+
 	for processClass, count := range desiredCounts {
 		if count == 0 {
 			continue
 		}
 
 		if _, useUnifiedImage := imageTypes[FDBImageTypeUnified]; useUnifiedImage {
-			if processClass == v1beta1.ProcessClassStorage {
-				for _, serversPerPod := range storageServersPerDisk {
-					config, err := GetMonitorProcessConfiguration(cluster, processClass, serversPerPod, FDBImageTypeUnified, nil)
+			if processClass == v1beta1.ProcessClassStorage || processClass.IsLogProcess() {
+				for _, serversPerPod := range getServersPerPodOptions(processClass) {
+					config, err := GetMonitorProcessConfiguration(cluster, processClass, "", serversPerPod, FDBImageTypeUnified, nil)
 					if err != nil {
 						return nil, err
 					}
@@ -93,7 +101,7 @@ func GetConfigMap(cluster *v1beta1.FoundationDBCluster) (*corev1.ConfigMap, erro
 					data[filename] = string(jsonData)
 				}
 			} else {
-				config, err := GetMonitorProcessConfiguration(cluster, processClass, 1, FDBImageTypeUnified, nil)
+				config, err := GetMonitorProcessConfiguration(cluster, processClass, "", 1, FDBImageTypeUnified, nil)
 				if err != nil {
 					return nil, err
 				}
@@ -106,9 +114,9 @@ func GetConfigMap(cluster *v1beta1.FoundationDBCluster) (*corev1.ConfigMap, erro
 		}
 
 		if _, useSplitImage := imageTypes[FDBImageTypeSplit]; useSplitImage {
-			if processClass == v1beta1.ProcessClassStorage {
-				for _, serversPerPod := range storageServersPerDisk {
-					err := setMonitorConfForFilename(cluster, data, GetConfigMapMonitorConfEntry(processClass, FDBImageTypeSplit, serversPerPod), connectionString, processClass, serversPerPod)
+			if processClass == v1beta1.ProcessClassStorage || processClass.IsLogProcess() {
+				for _, serversPerPod := range getServersPerPodOptions(processClass) {
+					err := setMonitorConfForFilename(cluster, data, GetConfigMapMonitorConfEntry(processClass, FDBImageTypeSplit, serversPerPod), connectionString, processClass, "", serversPerPod)
 					if err != nil {
 						return nil, err
 					}
@@ -116,13 +124,42 @@ func GetConfigMap(cluster *v1beta1.FoundationDBCluster) (*corev1.ConfigMap, erro
 				continue
 			}
 
-			err := setMonitorConfForFilename(cluster, data, GetConfigMapMonitorConfEntry(processClass, FDBImageTypeSplit, 1), connectionString, processClass, 1)
+			err := setMonitorConfForFilename(cluster, data, GetConfigMapMonitorConfEntry(processClass, FDBImageTypeSplit, 1), connectionString, processClass, "", 1)
 			if err != nil {
 				return nil, err
 			}
 		}
 	}
 
+	// Expired overrides keep their entry with the monitor conf for the process
+	// class, since the pods still reference it.
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if !cluster.HasParameterOverride(processGroup.ProcessGroupID) {
+			continue
+		}
+
+		for _, serversPerPod := range getServersPerPodOptions(processGroup.ProcessClass) {
+			if _, useUnifiedImage := imageTypes[FDBImageTypeUnified]; useUnifiedImage {
+				config, err := GetMonitorProcessConfiguration(cluster, processGroup.ProcessClass, processGroup.ProcessGroupID, serversPerPod, FDBImageTypeUnified, nil)
+				if err != nil {
+					return nil, err
+				}
+				jsonData, err := json.Marshal(config)
+				if err != nil {
+					return nil, err
+				}
+				data[GetConfigMapProcessGroupMonitorConfEntry(processGroup.ProcessClass, processGroup.ProcessGroupID, FDBImageTypeUnified, serversPerPod)] = string(jsonData)
+			}
+
+			if _, useSplitImage := imageTypes[FDBImageTypeSplit]; useSplitImage {
+				err := setMonitorConfForFilename(cluster, data, GetConfigMapProcessGroupMonitorConfEntry(processGroup.ProcessClass, processGroup.ProcessGroupID, FDBImageTypeSplit, serversPerPod), connectionString, processGroup.ProcessClass, processGroup.ProcessGroupID, serversPerPod)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
 	versionString := cluster.Status.RunningVersion
 	if versionString == "" {
 		versionString = cluster.Spec.Version
@@ -133,7 +170,7 @@ func GetConfigMap(cluster *v1beta1.FoundationDBCluster) (*corev1.ConfigMap, erro
 	}
 	needsInstanceIDSubstitution := !version.HasInstanceIDInSidecarSubstitutions()
 
-	substitutionCount := len(cluster.Spec.SidecarVariables)
+	substitutionCount := len(cluster.Spec.SidecarVariables) + len(cluster.Spec.SubstitutionVariables)
 	if needsInstanceIDSubstitution {
 		substitutionCount++
 	}
@@ -143,6 +180,7 @@ func GetConfigMap(cluster *v1beta1.FoundationDBCluster) (*corev1.ConfigMap, erro
 	if substitutionCount > 0 {
 		substitutionKeys = make([]string, 0, substitutionCount)
 		substitutionKeys = append(substitutionKeys, cluster.Spec.SidecarVariables...)
+		substitutionKeys = append(substitutionKeys, GetSubstitutionVariableNames(cluster)...)
 
 		if needsInstanceIDSubstitution {
 			substitutionKeys = append(substitutionKeys, "FDB_INSTANCE_ID")
@@ -155,6 +193,10 @@ func GetConfigMap(cluster *v1beta1.FoundationDBCluster) (*corev1.ConfigMap, erro
 		filesToCopy = append(filesToCopy, "ca.pem")
 	}
 
+	if cluster.Spec.TLSCertificates != nil {
+		filesToCopy = append(filesToCopy, TLSCertificateFiles...)
+	}
+
 	needsSidecarConf := !version.PrefersCommandLineArgumentsInSidecar() ||
 		cluster.Status.NeedsSidecarConfInConfigMap
 
@@ -188,6 +230,60 @@ func GetConfigMap(cluster *v1beta1.FoundationDBCluster) (*corev1.ConfigMap, erro
 	}, nil
 }
 
+// getCAFile builds a CA bundle from the trusted CAs of a cluster.
+func getCAFile(cluster *v1beta1.FoundationDBCluster) string {
+	var caFile strings.Builder
+	for _, ca := range cluster.Spec.TrustedCAs {
+		if caFile.Len() > 0 {
+			caFile.WriteString("\n")
+		}
+		caFile.WriteString(ca)
+	}
+
+	return caFile.String()
+}
+
+// GetClientConfigData builds the data that the operator publishes to a client
+// config target.
+func GetClientConfigData(cluster *v1beta1.FoundationDBCluster, target v1beta1.ClientConfigTarget) map[string]string {
+	data := map[string]string{
+		ClusterFileKey: cluster.Status.ConnectionString,
+	}
+
+	if target.IncludeTrustedCAs {
+		caFile := getCAFile(cluster)
+		if caFile != "" {
+			data["ca-file"] = caFile
+		}
+	}
+
+	return data
+}
+
+// GetClientConfigMetadata builds the metadata for a client config target.
+// The cluster is recorded in an annotation. Owner references can't point to
+// objects in other namespaces, so they are only set for targets in the
+// namespace of the cluster.
+func GetClientConfigMetadata(cluster *v1beta1.FoundationDBCluster, target v1beta1.ClientConfigTarget) metav1.ObjectMeta {
+	metadata := GetObjectMetadata(cluster, nil, "", "")
+	metadata.Namespace = target.Namespace
+	metadata.Name = target.GetName(cluster.Name)
+	metadata.Annotations = map[string]string{
+		v1beta1.ClientConfigSourceAnnotation: fmt.Sprintf("%s/%s", cluster.Namespace, cluster.Name),
+	}
+	if target.Namespace == cluster.Namespace {
+		metadata.OwnerReferences = BuildOwnerReference(cluster.TypeMeta, cluster.ObjectMeta)
+	}
+
+	return metadata
+}
+
+// GetConfigMapName returns the name of the config map that holds the
+// configuration for the processes of a cluster.
+func GetConfigMapName(cluster *v1beta1.FoundationDBCluster) string {
+	return getConfigMapMetadata(cluster).Name
+}
+
 func getConfigMapMetadata(cluster *v1beta1.FoundationDBCluster) metav1.ObjectMeta {
 	var metadata metav1.ObjectMeta
 	if cluster.Spec.ConfigMap != nil {
@@ -205,11 +301,11 @@ func getConfigMapMetadata(cluster *v1beta1.FoundationDBCluster) metav1.ObjectMet
 	return metadata
 }
 
-func setMonitorConfForFilename(cluster *v1beta1.FoundationDBCluster, data map[string]string, filename string, connectionString string, processClass v1beta1.ProcessClass, serversPerPod int) error {
+func setMonitorConfForFilename(cluster *v1beta1.FoundationDBCluster, data map[string]string, filename string, connectionString string, processClass v1beta1.ProcessClass, processGroupID string, serversPerPod int) error {
 	if connectionString == "" {
 		data[filename] = ""
 	} else {
-		conf, err := GetMonitorConf(cluster, processClass, nil, serversPerPod)
+		conf, err := GetMonitorConf(cluster, processClass, processGroupID, nil, serversPerPod)
 		if err != nil {
 			return err
 		}
@@ -222,27 +318,40 @@ func setMonitorConfForFilename(cluster *v1beta1.FoundationDBCluster, data map[st
 // GetConfigMapMonitorConfEntry returns the specific key for the monitor conf in the ConfigMap
 func GetConfigMapMonitorConfEntry(pClass v1beta1.ProcessClass, imageType FDBImageType, serversPerPod int) string {
 	if imageType == FDBImageTypeUnified {
-		if serversPerPod > 1 && pClass == v1beta1.ProcessClassStorage {
+		if serversPerPod > 1 && (pClass == v1beta1.ProcessClassStorage || pClass.IsLogProcess()) {
 			return fmt.Sprintf("fdbmonitor-conf-%s-json-multiple", pClass)
 		}
 
 		return fmt.Sprintf("fdbmonitor-conf-%s-json", pClass)
 	}
-	if serversPerPod > 1 && pClass == v1beta1.ProcessClassStorage {
+	if serversPerPod > 1 && (pClass == v1beta1.ProcessClassStorage || pClass.IsLogProcess()) {
 		return fmt.Sprintf("fdbmonitor-conf-%s-density-%d", pClass, serversPerPod)
 	}
 
 	return fmt.Sprintf("fdbmonitor-conf-%s", pClass)
 }
 
+// GetConfigMapProcessGroupMonitorConfEntry returns the key for the monitor
+// conf of a process group with a parameter override in the ConfigMap.
+func GetConfigMapProcessGroupMonitorConfEntry(pClass v1beta1.ProcessClass, processGroupID string, imageType FDBImageType, serversPerPod int) string {
+	return fmt.Sprintf("%s-process-group-%s", GetConfigMapMonitorConfEntry(pClass, imageType, serversPerPod), processGroupID)
+}
+
 // GetDynamicConfHash gets a hash of the data from the config map holding the
 // cluster's dynamic conf.
 //
 // This will omit keys that we do not expect the Pods to reference e.g. for storage Pods only include the storage config.
-func GetDynamicConfHash(configMap *corev1.ConfigMap, pClass v1beta1.ProcessClass, imageType FDBImageType, serversPerPod int) (string, error) {
+// If the config map has a monitor conf for the process group, that will be
+// used instead of the monitor conf for the process class.
+func GetDynamicConfHash(configMap *corev1.ConfigMap, pClass v1beta1.ProcessClass, processGroupID string, imageType FDBImageType, serversPerPod int) (string, error) {
+	monitorConfKey := GetConfigMapProcessGroupMonitorConfEntry(pClass, processGroupID, imageType, serversPerPod)
+	if _, present := configMap.Data[monitorConfKey]; !present {
+		monitorConfKey = GetConfigMapMonitorConfEntry(pClass, imageType, serversPerPod)
+	}
+
 	fields := []string{
 		ClusterFileKey,
-		GetConfigMapMonitorConfEntry(pClass, imageType, serversPerPod),
+		monitorConfKey,
 		"running-version",
 		"ca-file",
 		"sidecar-conf",
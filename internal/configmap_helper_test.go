@@ -23,6 +23,7 @@ package internal
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
 	monitorapi "github.com/apple/foundationdb/fdbkubernetesmonitor/api"
@@ -73,7 +74,7 @@ var _ = Describe("configmap_helper", func() {
 			})
 
 			It("should have the basic files", func() {
-				expectedConf, err := GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, nil, 1)
+				expectedConf, err := GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, "", nil, 1)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(configMap.Data[ClusterFileKey]).To(Equal("operator-test:asdfasf@127.0.0.1:4501"))
@@ -94,7 +95,7 @@ var _ = Describe("configmap_helper", func() {
 				config := monitorapi.ProcessConfiguration{}
 				err = json.Unmarshal([]byte(jsonData), &config)
 				Expect(err).NotTo(HaveOccurred())
-				expectedConfig, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, 1, FDBImageTypeUnified, nil)
+				expectedConfig, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, "", 1, FDBImageTypeUnified, nil)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(config).To(Equal(expectedConfig))
 			})
@@ -111,7 +112,7 @@ var _ = Describe("configmap_helper", func() {
 			})
 
 			It("includes the data for the split monitor conf", func() {
-				expectedConf, err := GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, nil, 1)
+				expectedConf, err := GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, "", nil, 1)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(configMap.Data["fdbmonitor-conf-storage"]).To(Equal(expectedConf))
 			})
@@ -122,6 +123,43 @@ var _ = Describe("configmap_helper", func() {
 			})
 		})
 
+		When("a process group has a parameter override", func() {
+			BeforeEach(func() {
+				cluster.Status.ProcessGroups = []*fdbtypes.ProcessGroupStatus{
+					{ProcessGroupID: "storage-1", ProcessClass: fdbtypes.ProcessClassStorage},
+					{ProcessGroupID: "storage-2", ProcessClass: fdbtypes.ProcessClassStorage},
+				}
+				cluster.Spec.ProcessGroupParameterOverrides = map[string]fdbtypes.ProcessGroupParameterOverride{
+					"storage-1": {CustomParameters: fdbtypes.FoundationDBCustomParameters{"knob_trace_level=1"}},
+					"storage-2": {
+						CustomParameters: fdbtypes.FoundationDBCustomParameters{"knob_trace_level=2"},
+						ExpirationTime:   &metav1.Time{Time: time.Now().Add(-1 * time.Minute)},
+					},
+				}
+			})
+
+			It("includes the monitor conf for the process group", func() {
+				expectedConf, err := GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, "storage-1", nil, 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(expectedConf).To(ContainSubstring("knob_trace_level = 1"))
+				Expect(configMap.Data["fdbmonitor-conf-storage-process-group-storage-1"]).To(Equal(expectedConf))
+				Expect(configMap.Data["fdbmonitor-conf-storage"]).NotTo(ContainSubstring("knob_trace_level"))
+			})
+
+			It("includes the monitor conf for the process class for expired overrides", func() {
+				Expect(configMap.Data).To(HaveKey("fdbmonitor-conf-storage-process-group-storage-2"))
+				Expect(configMap.Data["fdbmonitor-conf-storage-process-group-storage-2"]).To(Equal(configMap.Data["fdbmonitor-conf-storage"]))
+			})
+
+			It("uses the monitor conf for the process group in the hash", func() {
+				classHash, err := GetDynamicConfHash(configMap, fdbtypes.ProcessClassStorage, "storage-3", FDBImageTypeSplit, 1)
+				Expect(err).NotTo(HaveOccurred())
+				overrideHash, err := GetDynamicConfHash(configMap, fdbtypes.ProcessClassStorage, "storage-1", FDBImageTypeSplit, 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(overrideHash).NotTo(Equal(classHash))
+			})
+		})
+
 		When("both image types are enabled", func() {
 			BeforeEach(func() {
 				cluster.Status.ImageTypes = []fdbtypes.ImageType{"split", "unified"}
@@ -145,11 +183,11 @@ var _ = Describe("configmap_helper", func() {
 					cluster.Status.ImageTypes = []fdbtypes.ImageType{"split"}
 				})
 				It("includes the data for both configurations", func() {
-					expectedConf, err := GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, nil, 1)
+					expectedConf, err := GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, "", nil, 1)
 					Expect(err).NotTo(HaveOccurred())
 					Expect(configMap.Data["fdbmonitor-conf-storage"]).To(Equal(expectedConf))
 
-					expectedConf, err = GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, nil, 2)
+					expectedConf, err = GetMonitorConf(cluster, fdbtypes.ProcessClassStorage, "", nil, 2)
 					Expect(err).NotTo(HaveOccurred())
 					Expect(configMap.Data["fdbmonitor-conf-storage-density-2"]).To(Equal(expectedConf))
 				})
@@ -166,7 +204,7 @@ var _ = Describe("configmap_helper", func() {
 					config := monitorapi.ProcessConfiguration{}
 					err = json.Unmarshal([]byte(jsonData), &config)
 					Expect(err).NotTo(HaveOccurred())
-					expectedConfig, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, 1, FDBImageTypeUnified, nil)
+					expectedConfig, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, "", 1, FDBImageTypeUnified, nil)
 					Expect(err).NotTo(HaveOccurred())
 					Expect(config).To(Equal(expectedConfig))
 
@@ -175,7 +213,7 @@ var _ = Describe("configmap_helper", func() {
 					config = monitorapi.ProcessConfiguration{}
 					err = json.Unmarshal([]byte(jsonData), &config)
 					Expect(err).NotTo(HaveOccurred())
-					expectedConfig, err = GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, 2, FDBImageTypeUnified, nil)
+					expectedConfig, err = GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, "", 2, FDBImageTypeUnified, nil)
 					Expect(err).NotTo(HaveOccurred())
 					Expect(config).To(Equal(expectedConfig))
 				})
@@ -183,6 +221,27 @@ var _ = Describe("configmap_helper", func() {
 			})
 		})
 
+		Context("with multiple log servers per disk", func() {
+			BeforeEach(func() {
+				cluster.Status.LogServersPerDisk = []int{1, 2}
+				cluster.Status.ImageTypes = []fdbtypes.ImageType{"split"}
+			})
+
+			It("includes the data for both configurations", func() {
+				expectedConf, err := GetMonitorConf(cluster, fdbtypes.ProcessClassLog, "", nil, 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(configMap.Data["fdbmonitor-conf-log"]).To(Equal(expectedConf))
+
+				expectedConf, err = GetMonitorConf(cluster, fdbtypes.ProcessClassLog, "", nil, 2)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(configMap.Data["fdbmonitor-conf-log-density-2"]).To(Equal(expectedConf))
+			})
+
+			It("does not include a density configuration for other process classes", func() {
+				Expect(configMap.Data).NotTo(HaveKey("fdbmonitor-conf-storage-density-2"))
+			})
+		})
+
 		Context("with custom resource labels", func() {
 			BeforeEach(func() {
 				cluster.Spec.LabelConfig = fdbtypes.LabelConfig{
@@ -387,4 +446,50 @@ var _ = Describe("configmap_helper", func() {
 		})
 	})
 
+	Describe("GetClientConfigData", func() {
+		var target fdbtypes.ClientConfigTarget
+
+		BeforeEach(func() {
+			cluster.Status.ConnectionString = fakeConnectionString
+			cluster.Spec.TrustedCAs = []string{"---CERT1----", "---CERT2----"}
+			target = fdbtypes.ClientConfigTarget{Namespace: "app"}
+		})
+
+		It("should only contain the cluster file", func() {
+			Expect(GetClientConfigData(cluster, target)).To(Equal(map[string]string{
+				ClusterFileKey: fakeConnectionString,
+			}))
+		})
+
+		When("the trusted CAs are included", func() {
+			BeforeEach(func() {
+				target.IncludeTrustedCAs = true
+			})
+
+			It("should contain the CA file", func() {
+				Expect(GetClientConfigData(cluster, target)).To(Equal(map[string]string{
+					ClusterFileKey: fakeConnectionString,
+					"ca-file":      "---CERT1----\n---CERT2----",
+				}))
+			})
+		})
+	})
+
+	Describe("GetClientConfigMetadata", func() {
+		It("should point to the target and record the source cluster", func() {
+			metadata := GetClientConfigMetadata(cluster, fdbtypes.ClientConfigTarget{Namespace: "app"})
+			Expect(metadata.Namespace).To(Equal("app"))
+			Expect(metadata.Name).To(Equal("operator-test-1-client-config"))
+			Expect(metadata.OwnerReferences).To(BeEmpty())
+			Expect(metadata.Annotations).To(Equal(map[string]string{
+				fdbtypes.ClientConfigSourceAnnotation: "my-ns/operator-test-1",
+			}))
+		})
+
+		It("should set the owner reference for a target in the namespace of the cluster", func() {
+			metadata := GetClientConfigMetadata(cluster, fdbtypes.ClientConfigTarget{Namespace: cluster.Namespace})
+			Expect(metadata.OwnerReferences).To(Equal(BuildOwnerReference(cluster.TypeMeta, cluster.ObjectMeta)))
+		})
+	})
+
 })
@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strings"
 
 	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
 	corev1 "k8s.io/api/core/v1"
@@ -236,6 +237,17 @@ func NormalizeClusterSpec(cluster *fdbtypes.FoundationDBCluster, options Depreca
 		cluster.Spec.SidecarContainer.ImageName = ""
 	}
 
+	for _, window := range cluster.Spec.MaintenanceWindows {
+		err := window.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	if cluster.Spec.PrimaryDataCenter != "" && !cluster.Spec.DatabaseConfiguration.HasMainDataCenter(cluster.Spec.PrimaryDataCenter) {
+		return fmt.Errorf("primary data center %s is not the main data center of any region", cluster.Spec.PrimaryDataCenter)
+	}
+
 	// Validate customParameters
 	for processClass := range cluster.Spec.Processes {
 		if setting, ok := cluster.Spec.Processes[processClass]; ok {
@@ -250,7 +262,39 @@ func NormalizeClusterSpec(cluster *fdbtypes.FoundationDBCluster, options Depreca
 		}
 	}
 
+	for processGroupID, override := range cluster.Spec.ProcessGroupParameterOverrides {
+		err := override.CustomParameters.ValidateCustomParameters()
+		if err != nil {
+			return fmt.Errorf("invalid parameter override for process group %s: %w", processGroupID, err)
+		}
+	}
+
+	// Mutual TLS only protects the sidecar when it requires a client
+	// certificate that matches its peer verification rules.
+	if cluster.Spec.SidecarAuthentication != nil && cluster.Spec.SidecarAuthentication.GetMode() == fdbtypes.SidecarAuthenticationModeMutualTLS {
+		if !cluster.Spec.SidecarContainer.EnableTLS || cluster.Spec.SidecarContainer.PeerVerificationRules == "" {
+			return fmt.Errorf("sidecar authentication mode %s requires enableTls and peerVerificationRules on the sidecar container", fdbtypes.SidecarAuthenticationModeMutualTLS)
+		}
+	}
+
+	// Reject references to unknown variables before they are rolled out to
+	// the processes, which would fail to start.
+	err := ValidateSubstitutionVariables(cluster)
+	if err != nil {
+		return err
+	}
+
 	if !options.OnlyShowChanges {
+		// Use the same node label for the data hall locality and the
+		// topology spread of the data halls.
+		ensureDataHallLocality(&cluster.Spec)
+
+		// Set up smaller resource requirements for dedicated coordinators,
+		// before the general defaults are applied.
+		if cluster.UseDedicatedCoordinators() {
+			ensureCoordinatorResources(&cluster.Spec)
+		}
+
 		// Set up resource requirements for the main container.
 		updatePodTemplates(&cluster.Spec, func(template *v1.PodTemplateSpec) {
 			template.Spec.Containers, _ = ensureContainerPresent(template.Spec.Containers, "foundationdb", 0)
@@ -413,6 +457,89 @@ func ensureConfigMapPresent(spec *fdbtypes.FoundationDBClusterSpec) {
 	}
 }
 
+// ensureDataHallLocality derives the data hall locality and the topology key
+// for the data halls from each other, so they only have to be configured once.
+//
+// When only the data hall key is set, the data hall is read from the node
+// label with that key. When only the data hall is set, and it reads a
+// substitution variable from a node label, that label becomes the data hall
+// key.
+func ensureDataHallLocality(spec *fdbtypes.FoundationDBClusterSpec) {
+	if spec.FaultDomain.DataHallKey != "" && spec.DataHall == "" {
+		spec.DataHall = "$" + dataHallVariableName
+		for _, variable := range spec.SubstitutionVariables {
+			if variable.Name == dataHallVariableName {
+				return
+			}
+		}
+
+		spec.SubstitutionVariables = append(spec.SubstitutionVariables, fdbtypes.SubstitutionVariable{
+			Name:      dataHallVariableName,
+			NodeLabel: spec.FaultDomain.DataHallKey,
+		})
+		return
+	}
+
+	if spec.FaultDomain.DataHallKey == "" && strings.HasPrefix(spec.DataHall, "$") {
+		for _, variable := range spec.SubstitutionVariables {
+			if variable.Name == spec.DataHall[1:] && variable.NodeLabel != "" {
+				spec.FaultDomain.DataHallKey = variable.NodeLabel
+				return
+			}
+		}
+	}
+}
+
+// ensureCoordinatorResources fills in the default resource requirements for
+// the main container of dedicated coordinators. Values that are set in the
+// coordinator process settings, or in the general process settings when the
+// coordinator settings have no pod template, are kept.
+func ensureCoordinatorResources(spec *fdbtypes.FoundationDBClusterSpec) {
+	if spec.Processes == nil {
+		spec.Processes = make(map[fdbtypes.ProcessClass]fdbtypes.ProcessSettings)
+	}
+
+	settings := spec.Processes[fdbtypes.ProcessClassCoordinator]
+	if settings.PodTemplate == nil {
+		generalSettings := spec.Processes[fdbtypes.ProcessClassGeneral]
+		if generalSettings.PodTemplate != nil {
+			settings.PodTemplate = generalSettings.PodTemplate.DeepCopy()
+		} else {
+			settings.PodTemplate = &corev1.PodTemplateSpec{}
+		}
+	}
+
+	settings.PodTemplate.Spec.Containers = customizeContainerFromList(settings.PodTemplate.Spec.Containers, "foundationdb", func(container *corev1.Container) {
+		defaults := corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("500m"),
+			corev1.ResourceMemory: resource.MustParse("512Mi"),
+		}
+
+		if container.Resources.Requests == nil {
+			container.Resources.Requests = corev1.ResourceList{}
+		}
+
+		for name, value := range defaults {
+			if _, present := container.Resources.Requests[name]; !present {
+				container.Resources.Requests[name] = value
+			}
+		}
+
+		if container.Resources.Limits == nil {
+			container.Resources.Limits = container.Resources.Requests.DeepCopy()
+			return
+		}
+
+		for name := range defaults {
+			if _, present := container.Resources.Limits[name]; !present {
+				container.Resources.Limits[name] = container.Resources.Requests[name]
+			}
+		}
+	})
+
+	spec.Processes[fdbtypes.ProcessClassCoordinator] = settings
+}
+
 // ensurePodTemplatePresent defines a pod template in the general process
 // settings.
 func ensurePodTemplatePresent(spec *fdbtypes.FoundationDBClusterSpec) {
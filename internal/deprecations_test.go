@@ -586,6 +586,39 @@ var _ = Describe("[internal] deprecations", func() {
 					Expect(err).To(HaveOccurred())
 				})
 			})
+
+			Context("with mutual TLS for the sidecar without peer verification rules", func() {
+				It("an error should be returned", func() {
+					spec.SidecarContainer.EnableTLS = true
+					spec.SidecarAuthentication = &fdbtypes.SidecarAuthenticationConfig{Mode: fdbtypes.SidecarAuthenticationModeMutualTLS}
+					err := NormalizeClusterSpec(cluster, DeprecationOptions{})
+					Expect(err).To(MatchError("sidecar authentication mode MutualTLS requires enableTls and peerVerificationRules on the sidecar container"))
+				})
+			})
+
+			Context("with mutual TLS for the sidecar and peer verification rules", func() {
+				It("no error should be returned", func() {
+					spec.SidecarContainer.EnableTLS = true
+					spec.SidecarContainer.PeerVerificationRules = "S.CN=fdb-kubernetes-operator.foundationdb.example"
+					spec.SidecarAuthentication = &fdbtypes.SidecarAuthenticationConfig{Mode: fdbtypes.SidecarAuthenticationModeMutualTLS}
+					err := NormalizeClusterSpec(cluster, DeprecationOptions{})
+					Expect(err).NotTo(HaveOccurred())
+				})
+			})
+
+			Context("with an unknown substitution variable in the ProcessSettings", func() {
+				It("an error should be returned", func() {
+					spec.Processes = map[fdbtypes.ProcessClass]fdbtypes.ProcessSettings{
+						fdbtypes.ProcessClassGeneral: {
+							CustomParameters: fdbtypes.FoundationDBCustomParameters{
+								"locality_rack=$FDB_RACK",
+							},
+						},
+					}
+					err := NormalizeClusterSpec(cluster, DeprecationOptions{})
+					Expect(err).To(MatchError("cluster references unknown substitution variables: FDB_RACK"))
+				})
+			})
 		})
 
 		Describe("defaults", func() {
@@ -795,6 +828,43 @@ var _ = Describe("[internal] deprecations", func() {
 						Expect(spec.Processes["general"].PodTemplate.Spec.InitContainers).To(HaveLen(0))
 					})
 				})
+
+				Context("with a data hall key", func() {
+					BeforeEach(func() {
+						spec.FaultDomain.DataHallKey = "topology.kubernetes.io/zone"
+					})
+
+					It("should read the data hall from the node label", func() {
+						Expect(spec.DataHall).To(Equal("$FDB_DATA_HALL"))
+						Expect(spec.SubstitutionVariables).To(Equal([]fdbtypes.SubstitutionVariable{
+							{Name: "FDB_DATA_HALL", NodeLabel: "topology.kubernetes.io/zone"},
+						}))
+					})
+
+					Context("with an explicit data hall", func() {
+						BeforeEach(func() {
+							spec.DataHall = "dh1"
+						})
+
+						It("should keep the data hall", func() {
+							Expect(spec.DataHall).To(Equal("dh1"))
+							Expect(spec.SubstitutionVariables).To(BeEmpty())
+						})
+					})
+				})
+
+				Context("with a data hall from a node label", func() {
+					BeforeEach(func() {
+						spec.DataHall = "$DATA_HALL"
+						spec.SubstitutionVariables = []fdbtypes.SubstitutionVariable{
+							{Name: "DATA_HALL", NodeLabel: "topology.kubernetes.io/zone"},
+						}
+					})
+
+					It("should use the node label as data hall key", func() {
+						Expect(spec.FaultDomain.DataHallKey).To(Equal("topology.kubernetes.io/zone"))
+					})
+				})
 			})
 
 			Context("with the current defaults, changes only", func() {
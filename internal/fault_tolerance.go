@@ -31,6 +31,45 @@ func hasDesiredFaultTolerance(expectedFaultTolerance int, maxZoneFailuresWithout
 	return maxZoneFailuresWithoutLosingData >= expectedFaultTolerance && maxZoneFailuresWithoutLosingAvailability >= expectedFaultTolerance
 }
 
+// minimumZonesPerDataHall defines how many zones every data hall must contain
+// in the three_data_hall redundancy mode.
+const minimumZonesPerDataHall = 2
+
+// hasDesiredDataHalls checks if the processes are spread across enough data
+// halls, with enough zones in every data hall, to tolerate the loss of a data
+// hall in the three_data_hall redundancy mode.
+func hasDesiredDataHalls(cluster *fdbtypes.FoundationDBCluster, processes map[string]fdbtypes.FoundationDBStatusProcessInfo) bool {
+	if cluster.Spec.DatabaseConfiguration.RedundancyMode != fdbtypes.RedundancyModeThreeDataHall {
+		return true
+	}
+
+	zonesPerDataHall := make(map[string]map[string]fdbtypes.None)
+	for _, process := range processes {
+		if process.Excluded {
+			continue
+		}
+
+		dataHall := process.Locality[fdbtypes.FDBLocalityDataHallKey]
+		if dataHall == "" {
+			continue
+		}
+
+		if _, ok := zonesPerDataHall[dataHall]; !ok {
+			zonesPerDataHall[dataHall] = make(map[string]fdbtypes.None)
+		}
+		zonesPerDataHall[dataHall][process.Locality[fdbtypes.FDBLocalityZoneIDKey]] = fdbtypes.None{}
+	}
+
+	validDataHalls := 0
+	for _, zones := range zonesPerDataHall {
+		if len(zones) >= minimumZonesPerDataHall {
+			validDataHalls++
+		}
+	}
+
+	return validDataHalls >= cluster.MinimumFaultDomains()
+}
+
 // HasDesiredFaultTolerance checks if the cluster has the desired fault tolerance.
 func HasDesiredFaultTolerance(adminClient fdbadminclient.AdminClient, cluster *fdbtypes.FoundationDBCluster) (bool, error) {
 	version, err := fdbtypes.ParseFdbVersion(cluster.Spec.Version)
@@ -63,6 +102,10 @@ func HasDesiredFaultTolerance(adminClient fdbadminclient.AdminClient, cluster *f
 	//	"maxZoneFailuresWithoutLosingData", status.Cluster.FaultTolerance.MaxZoneFailuresWithoutLosingData,
 	//	"maxZoneFailuresWithoutLosingAvailability", status.Cluster.FaultTolerance.MaxZoneFailuresWithoutLosingAvailability)
 
+	if !hasDesiredDataHalls(cluster, status.Cluster.Processes) {
+		return false, nil
+	}
+
 	return hasDesiredFaultTolerance(
 		expectedFaultTolerance,
 		status.Cluster.FaultTolerance.MaxZoneFailuresWithoutLosingData,
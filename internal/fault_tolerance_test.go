@@ -21,6 +21,9 @@
 package internal
 
 import (
+	"fmt"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
@@ -65,4 +68,47 @@ var _ = Describe("fault_tolerance", func() {
 				}),
 		)
 	})
+
+	Context("check if the processes are spread across enough data halls", func() {
+		var cluster *fdbtypes.FoundationDBCluster
+
+		BeforeEach(func() {
+			cluster = CreateDefaultCluster()
+			cluster.Spec.DatabaseConfiguration.RedundancyMode = fdbtypes.RedundancyModeThreeDataHall
+		})
+
+		newProcesses := func(localities ...[2]string) map[string]fdbtypes.FoundationDBStatusProcessInfo {
+			processes := make(map[string]fdbtypes.FoundationDBStatusProcessInfo, len(localities))
+			for index, locality := range localities {
+				processes[fmt.Sprintf("process-%d", index)] = fdbtypes.FoundationDBStatusProcessInfo{
+					Locality: map[string]string{
+						fdbtypes.FDBLocalityDataHallKey: locality[0],
+						fdbtypes.FDBLocalityZoneIDKey:   locality[1],
+					},
+				}
+			}
+
+			return processes
+		}
+
+		It("should accept three data halls with two zones each", func() {
+			processes := newProcesses([2]string{"a", "a1"}, [2]string{"a", "a2"}, [2]string{"b", "b1"}, [2]string{"b", "b2"}, [2]string{"c", "c1"}, [2]string{"c", "c2"})
+			Expect(hasDesiredDataHalls(cluster, processes)).To(BeTrue())
+		})
+
+		It("should reject a data hall with a single zone", func() {
+			processes := newProcesses([2]string{"a", "a1"}, [2]string{"a", "a2"}, [2]string{"b", "b1"}, [2]string{"b", "b2"}, [2]string{"c", "c1"}, [2]string{"c", "c1"})
+			Expect(hasDesiredDataHalls(cluster, processes)).To(BeFalse())
+		})
+
+		It("should reject two data halls", func() {
+			processes := newProcesses([2]string{"a", "a1"}, [2]string{"a", "a2"}, [2]string{"b", "b1"}, [2]string{"b", "b2"})
+			Expect(hasDesiredDataHalls(cluster, processes)).To(BeFalse())
+		})
+
+		It("should ignore the data halls for other redundancy modes", func() {
+			cluster.Spec.DatabaseConfiguration.RedundancyMode = fdbtypes.RedundancyModeTriple
+			Expect(hasDesiredDataHalls(cluster, nil)).To(BeTrue())
+		})
+	})
 })
@@ -33,7 +33,10 @@ import (
 )
 
 // GetStartCommand builds the expected start command for a process group.
-func GetStartCommand(cluster *fdbtypes.FoundationDBCluster, processClass fdbtypes.ProcessClass, podClient podclient.FdbPodClient, processNumber int, processCount int) (string, error) {
+//
+// The process group ID is used to apply the parameter overrides for the
+// process group.
+func GetStartCommand(cluster *fdbtypes.FoundationDBCluster, processClass fdbtypes.ProcessClass, processGroupID string, podClient podclient.FdbPodClient, processNumber int, processCount int) (string, error) {
 	substitutions, err := podClient.GetVariableSubstitutions()
 	if err != nil {
 		return "", err
@@ -44,7 +47,7 @@ func GetStartCommand(cluster *fdbtypes.FoundationDBCluster, processClass fdbtype
 	}
 
 	imageType := GetDesiredImageType(cluster)
-	config, err := GetMonitorProcessConfiguration(cluster, processClass, processCount, imageType, substitutions)
+	config, err := GetMonitorProcessConfiguration(cluster, processClass, processGroupID, processCount, imageType, substitutions)
 	if err != nil {
 		return "", err
 	}
@@ -91,7 +94,7 @@ func extractPlaceholderEnvVars(env map[string]string, arguments []monitorapi.Arg
 }
 
 // GetMonitorConf builds the monitor conf template
-func GetMonitorConf(cluster *fdbtypes.FoundationDBCluster, processClass fdbtypes.ProcessClass, podClient podclient.FdbPodClient, serversPerPod int) (string, error) {
+func GetMonitorConf(cluster *fdbtypes.FoundationDBCluster, processClass fdbtypes.ProcessClass, processGroupID string, podClient podclient.FdbPodClient, serversPerPod int) (string, error) {
 	if cluster.Status.ConnectionString == "" {
 		return "", nil
 	}
@@ -117,7 +120,7 @@ func GetMonitorConf(cluster *fdbtypes.FoundationDBCluster, processClass fdbtypes
 	if !cluster.Spec.Buggify.EmptyMonitorConf {
 		for i := 1; i <= serversPerPod; i++ {
 			confLines = append(confLines, fmt.Sprintf("[fdbserver.%d]", i))
-			commands, err := getMonitorConfStartCommandLines(cluster, processClass, substitutions, i, serversPerPod)
+			commands, err := getMonitorConfStartCommandLines(cluster, processClass, processGroupID, substitutions, i, serversPerPod)
 			if err != nil {
 				return "", err
 			}
@@ -128,10 +131,10 @@ func GetMonitorConf(cluster *fdbtypes.FoundationDBCluster, processClass fdbtypes
 	return strings.Join(confLines, "\n"), nil
 }
 
-func getMonitorConfStartCommandLines(cluster *fdbtypes.FoundationDBCluster, processClass fdbtypes.ProcessClass, substitutions map[string]string, processNumber int, processCount int) ([]string, error) {
+func getMonitorConfStartCommandLines(cluster *fdbtypes.FoundationDBCluster, processClass fdbtypes.ProcessClass, processGroupID string, substitutions map[string]string, processNumber int, processCount int) ([]string, error) {
 	confLines := make([]string, 0, 20)
 
-	config, err := GetMonitorProcessConfiguration(cluster, processClass, processCount, FDBImageTypeSplit, substitutions)
+	config, err := GetMonitorProcessConfiguration(cluster, processClass, processGroupID, processCount, FDBImageTypeSplit, substitutions)
 	if err != nil {
 		return nil, err
 	}
@@ -172,7 +175,10 @@ func getMonitorConfStartCommandLines(cluster *fdbtypes.FoundationDBCluster, proc
 }
 
 // GetMonitorProcessConfiguration builds the monitor conf template for the unifed image.
-func GetMonitorProcessConfiguration(cluster *fdbtypes.FoundationDBCluster, processClass fdbtypes.ProcessClass, processCount int, imageType FDBImageType, customParameterSubstitutions map[string]string) (monitorapi.ProcessConfiguration, error) {
+//
+// If the process group ID is empty, this will only use the custom parameters
+// from the process settings.
+func GetMonitorProcessConfiguration(cluster *fdbtypes.FoundationDBCluster, processClass fdbtypes.ProcessClass, processGroupID string, processCount int, imageType FDBImageType, customParameterSubstitutions map[string]string) (monitorapi.ProcessConfiguration, error) {
 	configuration := monitorapi.ProcessConfiguration{
 		Version: cluster.Spec.Version,
 	}
@@ -246,19 +252,30 @@ func GetMonitorProcessConfiguration(cluster *fdbtypes.FoundationDBCluster, proce
 		configuration.Arguments = append(configuration.Arguments, monitorapi.Argument{Value: fmt.Sprintf("--tls_verify_peers=%s", cluster.Spec.MainContainer.PeerVerificationRules)})
 	}
 
-	podSettings := cluster.GetProcessSettings(processClass)
+	if usesConfigurationDatabase(cluster) {
+		configuration.Arguments = append(configuration.Arguments, monitorapi.Argument{Value: fmt.Sprintf("--config_db=%s", configurationDatabaseType)})
+	}
+
+	customParameters := removeRuntimeKnobs(cluster.GetProcessGroupCustomParameters(processClass, processGroupID), cluster.GetRuntimeKnobs(), cluster.Status.RuntimeKnobs)
 
-	if podSettings.CustomParameters != nil {
+	if customParameters != nil {
 		equalPattern, err := regexp.Compile(`\s*=\s*`)
 		if err != nil {
 			return configuration, err
 		}
-		for _, argument := range podSettings.CustomParameters {
+		variables := make(map[string]bool, len(cluster.Spec.SubstitutionVariables))
+		if imageType == FDBImageTypeUnified {
+			for _, name := range GetSubstitutionVariableNames(cluster) {
+				variables[name] = true
+			}
+		}
+
+		for _, argument := range customParameters {
 			sanitizedArgument := "--" + equalPattern.ReplaceAllString(string(argument), "=")
 			for key, value := range customParameterSubstitutions {
 				sanitizedArgument = strings.Replace(sanitizedArgument, "$"+key, value, -1)
 			}
-			configuration.Arguments = append(configuration.Arguments, monitorapi.Argument{Value: sanitizedArgument})
+			configuration.Arguments = append(configuration.Arguments, buildCustomParameterArgument(sanitizedArgument, variables))
 		}
 	}
 
@@ -266,13 +283,67 @@ func GetMonitorProcessConfiguration(cluster *fdbtypes.FoundationDBCluster, proce
 		configuration.Arguments = append(configuration.Arguments, monitorapi.Argument{Value: fmt.Sprintf("--locality_dcid=%s", cluster.Spec.DataCenter)})
 	}
 
-	if cluster.Spec.DataHall != "" {
+	if strings.HasPrefix(cluster.Spec.DataHall, "$") {
+		configuration.Arguments = append(configuration.Arguments, monitorapi.Argument{ArgumentType: monitorapi.ConcatenateArgumentType, Values: []monitorapi.Argument{
+			{Value: "--locality_data_hall="},
+			{ArgumentType: monitorapi.EnvironmentArgumentType, Source: cluster.Spec.DataHall[1:]},
+		}})
+	} else if cluster.Spec.DataHall != "" {
 		configuration.Arguments = append(configuration.Arguments, monitorapi.Argument{Value: fmt.Sprintf("--locality_data_hall=%s", cluster.Spec.DataHall)})
 	}
 
+	if cluster.UseDNSInClusterFile() {
+		configuration.Arguments = append(configuration.Arguments, monitorapi.Argument{ArgumentType: monitorapi.ConcatenateArgumentType, Values: []monitorapi.Argument{
+			{Value: "--locality_dns_name="},
+			{ArgumentType: monitorapi.EnvironmentArgumentType, Source: "FDB_DNS_NAME"},
+		}})
+	}
+
 	return configuration, nil
 }
 
+// configurationDatabaseType defines the type of the configuration database
+// that fdbserver uses for the runtime knobs.
+const configurationDatabaseType = "simple"
+
+// usesConfigurationDatabase determines whether the processes need the
+// configuration database for the runtime knobs.
+func usesConfigurationDatabase(cluster *fdbtypes.FoundationDBCluster) bool {
+	if len(cluster.Spec.AutomationOptions.RuntimeKnobs) == 0 {
+		return false
+	}
+
+	version, err := fdbtypes.ParseFdbVersion(cluster.Spec.Version)
+	return err == nil && version.SupportsRuntimeKnobs()
+}
+
+// removeRuntimeKnobs removes the knobs that the operator sets through the
+// configuration database from the custom parameters.
+//
+// A knob is only removed once the status shows that the operator has written
+// it to the configuration database, so that a process that restarts before
+// the knob is written still gets it from the command line.
+func removeRuntimeKnobs(customParameters fdbtypes.FoundationDBCustomParameters, runtimeKnobs map[string]string, appliedKnobs map[string]string) fdbtypes.FoundationDBCustomParameters {
+	if len(runtimeKnobs) == 0 || len(appliedKnobs) == 0 || customParameters == nil {
+		return customParameters
+	}
+
+	filtered := make(fdbtypes.FoundationDBCustomParameters, 0, len(customParameters))
+	for _, parameter := range customParameters {
+		name := parameter.GetName()
+		if strings.HasPrefix(name, "knob_") {
+			_, isRuntimeKnob := runtimeKnobs[strings.TrimPrefix(name, "knob_")]
+			_, isApplied := appliedKnobs[strings.TrimPrefix(name, "knob_")]
+			if isRuntimeKnob && isApplied {
+				continue
+			}
+		}
+		filtered = append(filtered, parameter)
+	}
+
+	return filtered
+}
+
 // buildIPArgument builds an argument that takes an IP address from an environment variable
 func buildIPArgument(parameter string, environmentVariable string, imageType FDBImageType, sampleAddresses []fdbtypes.ProcessAddress) []monitorapi.Argument {
 	var leftIPWrap string
@@ -54,7 +54,7 @@ var _ = Describe("pod_models", func() {
 			It("generates conf with an no processes", func() {
 				Expect(cluster).NotTo(BeNil())
 				cluster.Status.ConnectionString = ""
-				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, 1, FDBImageTypeUnified, nil)
+				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, "", 1, FDBImageTypeUnified, nil)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(config.RunServers).NotTo(BeNil())
 				Expect(*config.RunServers).To(BeFalse())
@@ -64,7 +64,7 @@ var _ = Describe("pod_models", func() {
 
 		When("running a storage instance", func() {
 			It("generates the conf", func() {
-				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, 1, FDBImageTypeUnified, nil)
+				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, "", 1, FDBImageTypeUnified, nil)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(config.Version).To(Equal(fdbtypes.Versions.Default.String()))
 				Expect(config.BinaryPath).To(BeEmpty())
@@ -100,7 +100,7 @@ var _ = Describe("pod_models", func() {
 
 		When("running a log instance", func() {
 			It("generates the conf", func() {
-				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassLog, 1, FDBImageTypeUnified, nil)
+				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassLog, "", 1, FDBImageTypeUnified, nil)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(config.Version).To(Equal(fdbtypes.Versions.Default.String()))
 				Expect(config.BinaryPath).To(BeEmpty())
@@ -113,7 +113,7 @@ var _ = Describe("pod_models", func() {
 
 		When("using the split image type", func() {
 			It("generates the conf", func() {
-				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, 1, FDBImageTypeSplit, nil)
+				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, "", 1, FDBImageTypeSplit, nil)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(config.Version).To(Equal(fdbtypes.Versions.Default.String()))
 				Expect(config.BinaryPath).To(BeEmpty())
@@ -149,7 +149,7 @@ var _ = Describe("pod_models", func() {
 
 		When("running multiple processes", func() {
 			It("adds a process ID argument", func() {
-				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, 2, FDBImageTypeUnified, nil)
+				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, "", 2, FDBImageTypeUnified, nil)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(config.Arguments).To(HaveLen(baseArgumentLength + 1))
 				Expect(config.Arguments[7]).To(Equal(monitorapi.Argument{ArgumentType: monitorapi.ConcatenateArgumentType, Values: []monitorapi.Argument{
@@ -165,7 +165,7 @@ var _ = Describe("pod_models", func() {
 			})
 
 			It("includes the process number in the data directory", func() {
-				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, 2, FDBImageTypeUnified, nil)
+				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, "", 2, FDBImageTypeUnified, nil)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(config.Arguments[6]).To(Equal(monitorapi.Argument{
 					ArgumentType: monitorapi.ConcatenateArgumentType,
@@ -184,7 +184,7 @@ var _ = Describe("pod_models", func() {
 			})
 
 			It("does not have a listen address", func() {
-				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, 1, FDBImageTypeUnified, nil)
+				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, "", 1, FDBImageTypeUnified, nil)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(config.Arguments).To(HaveLen(baseArgumentLength))
 				Expect(config.Arguments[2]).To(Equal(monitorapi.Argument{ArgumentType: monitorapi.ConcatenateArgumentType, Values: []monitorapi.Argument{
@@ -204,7 +204,7 @@ var _ = Describe("pod_models", func() {
 			})
 
 			It("adds a separate listen address", func() {
-				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, 1, FDBImageTypeUnified, nil)
+				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, "", 1, FDBImageTypeUnified, nil)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(config.Arguments).To(HaveLen(baseArgumentLength + 1))
 				Expect(config.Arguments[2]).To(Equal(monitorapi.Argument{ArgumentType: monitorapi.ConcatenateArgumentType, Values: []monitorapi.Argument{
@@ -227,7 +227,7 @@ var _ = Describe("pod_models", func() {
 				})
 
 				It("does not have a listen address", func() {
-					config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, 1, FDBImageTypeUnified, nil)
+					config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, "", 1, FDBImageTypeUnified, nil)
 					Expect(err).NotTo(HaveOccurred())
 					Expect(config.Arguments).To(HaveLen(baseArgumentLength))
 					Expect(config.Arguments[2]).To(Equal(monitorapi.Argument{ArgumentType: monitorapi.ConcatenateArgumentType, Values: []monitorapi.Argument{
@@ -248,7 +248,7 @@ var _ = Describe("pod_models", func() {
 			})
 
 			It("includes the TLS flag in the address", func() {
-				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, 1, FDBImageTypeUnified, nil)
+				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, "", 1, FDBImageTypeUnified, nil)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(config.Arguments).To(HaveLen(baseArgumentLength))
 				Expect(config.Arguments[2]).To(Equal(monitorapi.Argument{ArgumentType: monitorapi.ConcatenateArgumentType, Values: []monitorapi.Argument{
@@ -269,7 +269,7 @@ var _ = Describe("pod_models", func() {
 			})
 
 			It("includes both addresses", func() {
-				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, 1, FDBImageTypeUnified, nil)
+				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, "", 1, FDBImageTypeUnified, nil)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(config.Arguments).To(HaveLen(baseArgumentLength))
 				Expect(config.Arguments[2]).To(Equal(monitorapi.Argument{ArgumentType: monitorapi.ConcatenateArgumentType, Values: []monitorapi.Argument{
@@ -294,7 +294,7 @@ var _ = Describe("pod_models", func() {
 			})
 
 			It("includes both addresses", func() {
-				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, 1, FDBImageTypeUnified, nil)
+				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, "", 1, FDBImageTypeUnified, nil)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(config.Arguments).To(HaveLen(baseArgumentLength))
 				Expect(config.Arguments[2]).To(Equal(monitorapi.Argument{ArgumentType: monitorapi.ConcatenateArgumentType, Values: []monitorapi.Argument{
@@ -320,7 +320,7 @@ var _ = Describe("pod_models", func() {
 				})
 
 				It("includes the custom parameters", func() {
-					config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, 1, FDBImageTypeUnified, nil)
+					config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, "", 1, FDBImageTypeUnified, nil)
 					Expect(err).NotTo(HaveOccurred())
 					Expect(config.Arguments).To(HaveLen(baseArgumentLength + 1))
 					Expect(config.Arguments[10]).To(Equal(monitorapi.Argument{Value: "--knob_disable_posix_kernel_aio=1"}))
@@ -343,7 +343,7 @@ var _ = Describe("pod_models", func() {
 				})
 
 				It("includes the custom parameters for that class", func() {
-					config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, 1, FDBImageTypeUnified, nil)
+					config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, "", 1, FDBImageTypeUnified, nil)
 					Expect(err).NotTo(HaveOccurred())
 					Expect(config.Arguments).To(HaveLen(baseArgumentLength + 1))
 					Expect(config.Arguments[10]).To(Equal(monitorapi.Argument{Value: "--knob_test=test1"}))
@@ -351,6 +351,40 @@ var _ = Describe("pod_models", func() {
 			})
 		})
 
+		When("the cluster sets knobs through the configuration database", func() {
+			BeforeEach(func() {
+				cluster.Spec.Version = fdbtypes.Versions.WithRuntimeKnobs.String()
+				cluster.Status.RunningVersion = cluster.Spec.Version
+				cluster.Spec.AutomationOptions.RuntimeKnobs = []string{"max_trace_lines"}
+				cluster.Spec.Processes = map[fdbtypes.ProcessClass]fdbtypes.ProcessSettings{fdbtypes.ProcessClassGeneral: {CustomParameters: fdbtypes.FoundationDBCustomParameters{
+					"knob_max_trace_lines=1000",
+				}}}
+			})
+
+			When("the knob is not in the configuration database yet", func() {
+				It("includes the knob and enables the configuration database", func() {
+					config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, "", 1, FDBImageTypeUnified, nil)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(config.Arguments).To(HaveLen(baseArgumentLength + 2))
+					Expect(config.Arguments[10]).To(Equal(monitorapi.Argument{Value: "--config_db=simple"}))
+					Expect(config.Arguments[11]).To(Equal(monitorapi.Argument{Value: "--knob_max_trace_lines=1000"}))
+				})
+			})
+
+			When("the knob is in the configuration database", func() {
+				BeforeEach(func() {
+					cluster.Status.RuntimeKnobs = map[string]string{"max_trace_lines": "500"}
+				})
+
+				It("leaves out the knob", func() {
+					config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, "", 1, FDBImageTypeUnified, nil)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(config.Arguments).To(HaveLen(baseArgumentLength + 1))
+					Expect(config.Arguments[10]).To(Equal(monitorapi.Argument{Value: "--config_db=simple"}))
+				})
+			})
+		})
+
 		When("the cluster has an alternative fault domain variable", func() {
 			BeforeEach(func() {
 				cluster.Spec.FaultDomain = fdbtypes.FoundationDBClusterFaultDomain{
@@ -360,7 +394,7 @@ var _ = Describe("pod_models", func() {
 			})
 
 			It("uses the variable as the zone ID", func() {
-				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, 1, FDBImageTypeUnified, nil)
+				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, "", 1, FDBImageTypeUnified, nil)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(config.Arguments).To(HaveLen(baseArgumentLength))
 
@@ -377,7 +411,7 @@ var _ = Describe("pod_models", func() {
 			})
 
 			It("includes the verification rules", func() {
-				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, 1, FDBImageTypeUnified, nil)
+				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, "", 1, FDBImageTypeUnified, nil)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(config.Arguments).To(HaveLen(baseArgumentLength + 1))
 				Expect(config.Arguments[10]).To(Equal(monitorapi.Argument{Value: "--tls_verify_peers=S.CN=foundationdb.org"}))
@@ -390,7 +424,7 @@ var _ = Describe("pod_models", func() {
 			})
 
 			It("includes the log group", func() {
-				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, 1, FDBImageTypeUnified, nil)
+				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, "", 1, FDBImageTypeUnified, nil)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(config.Arguments).To(HaveLen(baseArgumentLength))
 				Expect(config.Arguments[5]).To(Equal(monitorapi.Argument{Value: "--loggroup=test-fdb-cluster"}))
@@ -403,7 +437,7 @@ var _ = Describe("pod_models", func() {
 			})
 
 			It("adds an argument for the data center", func() {
-				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, 1, FDBImageTypeUnified, nil)
+				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, "", 1, FDBImageTypeUnified, nil)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(config.Arguments).To(HaveLen(baseArgumentLength + 1))
 				Expect(config.Arguments[10]).To(Equal(monitorapi.Argument{Value: "--locality_dcid=dc01"}))
@@ -416,12 +450,45 @@ var _ = Describe("pod_models", func() {
 			})
 
 			It("adds an argument for the data hall", func() {
-				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, 1, FDBImageTypeUnified, nil)
+				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, "", 1, FDBImageTypeUnified, nil)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(config.Arguments).To(HaveLen(baseArgumentLength + 1))
 				Expect(config.Arguments[10]).To(Equal(monitorapi.Argument{Value: "--locality_data_hall=dh01"}))
 			})
 		})
+
+		When("the spec reads the data hall from an environment variable", func() {
+			BeforeEach(func() {
+				cluster.Spec.DataHall = "$DATA_HALL"
+			})
+
+			It("adds an argument that reads the data hall from the environment", func() {
+				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, "", 1, FDBImageTypeUnified, nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config.Arguments).To(HaveLen(baseArgumentLength + 1))
+				Expect(config.Arguments[10]).To(Equal(monitorapi.Argument{ArgumentType: monitorapi.ConcatenateArgumentType, Values: []monitorapi.Argument{
+					{Value: "--locality_data_hall="},
+					{ArgumentType: monitorapi.EnvironmentArgumentType, Source: "DATA_HALL"},
+				}}))
+			})
+		})
+
+		When("the cluster uses DNS names in the cluster file", func() {
+			BeforeEach(func() {
+				cluster.Spec.Version = "7.0.0"
+				cluster.Spec.Routing.UseDNSInClusterFile = pointer.Bool(true)
+			})
+
+			It("adds an argument for the DNS name", func() {
+				config, err := GetMonitorProcessConfiguration(cluster, fdbtypes.ProcessClassStorage, "", 1, FDBImageTypeUnified, nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config.Arguments).To(HaveLen(baseArgumentLength + 1))
+				Expect(config.Arguments[10]).To(Equal(monitorapi.Argument{ArgumentType: monitorapi.ConcatenateArgumentType, Values: []monitorapi.Argument{
+					{Value: "--locality_dns_name="},
+					{ArgumentType: monitorapi.EnvironmentArgumentType, Source: "FDB_DNS_NAME"},
+				}}))
+			})
+		})
 	})
 
 	Describe("GetStartCommand", func() {
@@ -441,7 +508,7 @@ var _ = Describe("pod_models", func() {
 			It("should substitute the variables in the start command", func() {
 				podClient, err := NewMockFdbPodClient(cluster, pod)
 				Expect(err).NotTo(HaveOccurred())
-				command, err = GetStartCommand(cluster, processClass, podClient, 1, 1)
+				command, err = GetStartCommand(cluster, processClass, processGroupID, podClient, 1, 1)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(command).To(Equal(strings.Join([]string{
@@ -467,7 +534,7 @@ var _ = Describe("pod_models", func() {
 
 					podClient, err := NewMockFdbPodClient(cluster, pod)
 					Expect(err).NotTo(HaveOccurred())
-					command, err = GetStartCommand(cluster, processClass, podClient, 1, 1)
+					command, err = GetStartCommand(cluster, processClass, processGroupID, podClient, 1, 1)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(command).To(Equal(strings.Join([]string{
@@ -486,6 +553,35 @@ var _ = Describe("pod_models", func() {
 					}, " ")))
 				})
 			})
+
+			Context("with a parameter override for the process group", func() {
+				BeforeEach(func() {
+					settings := cluster.Spec.Processes["general"]
+					settings.CustomParameters = []fdbtypes.FoundationDBCustomParameter{"knob_test=1", "locality_disk_id=$FDB_INSTANCE_ID"}
+					cluster.Spec.Processes["general"] = settings
+					cluster.Spec.ProcessGroupParameterOverrides = map[string]fdbtypes.ProcessGroupParameterOverride{
+						processGroupID: {CustomParameters: fdbtypes.FoundationDBCustomParameters{"knob_test=2"}},
+					}
+				})
+
+				It("should apply the override to the process group", func() {
+					podClient, err := NewMockFdbPodClient(cluster, pod)
+					Expect(err).NotTo(HaveOccurred())
+					command, err = GetStartCommand(cluster, processClass, processGroupID, podClient, 1, 1)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(command).To(ContainSubstring("--knob_test=2 "))
+					Expect(command).NotTo(ContainSubstring("--knob_test=1"))
+					Expect(command).To(ContainSubstring(fmt.Sprintf("--locality_disk_id=%s", processGroupID)))
+				})
+
+				It("should not apply the override to other process groups", func() {
+					podClient, err := NewMockFdbPodClient(cluster, pod)
+					Expect(err).NotTo(HaveOccurred())
+					command, err = GetStartCommand(cluster, processClass, "storage-2", podClient, 1, 1)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(command).To(ContainSubstring("--knob_test=1 "))
+				})
+			})
 		})
 
 		When("using the unified image", func() {
@@ -496,7 +592,7 @@ var _ = Describe("pod_models", func() {
 			It("should generate the unsorted command-line", func() {
 				podClient, err := NewMockFdbPodClient(cluster, pod)
 				Expect(err).NotTo(HaveOccurred())
-				command, err = GetStartCommand(cluster, processClass, podClient, 1, 1)
+				command, err = GetStartCommand(cluster, processClass, processGroupID, podClient, 1, 1)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(command).To(Equal(strings.Join([]string{
@@ -518,7 +614,7 @@ var _ = Describe("pod_models", func() {
 				It("should fill in the process number", func() {
 					podClient, err := NewMockFdbPodClient(cluster, pod)
 					Expect(err).NotTo(HaveOccurred())
-					command, err = GetStartCommand(cluster, processClass, podClient, 2, 3)
+					command, err = GetStartCommand(cluster, processClass, processGroupID, podClient, 2, 3)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(command).To(Equal(strings.Join([]string{
@@ -547,7 +643,7 @@ var _ = Describe("pod_models", func() {
 			It("should generate the sorted command-line", func() {
 				podClient, err := NewMockFdbPodClient(cluster, pod)
 				Expect(err).NotTo(HaveOccurred())
-				command, err = GetStartCommand(cluster, processClass, podClient, 1, 1)
+				command, err = GetStartCommand(cluster, processClass, processGroupID, podClient, 1, 1)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(command).To(Equal(strings.Join([]string{
@@ -570,7 +666,7 @@ var _ = Describe("pod_models", func() {
 			It("should substitute the variables in the start command", func() {
 				podClient, err := NewMockFdbPodClient(cluster, pod)
 				Expect(err).NotTo(HaveOccurred())
-				command, err = GetStartCommand(cluster, processClass, podClient, 1, 2)
+				command, err = GetStartCommand(cluster, processClass, processGroupID, podClient, 1, 2)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(command).To(Equal(strings.Join([]string{
@@ -588,7 +684,7 @@ var _ = Describe("pod_models", func() {
 					"--seed_cluster_file=/var/dynamic-conf/fdb.cluster",
 				}, " ")))
 
-				command, err = GetStartCommand(cluster, processClass, podClient, 2, 2)
+				command, err = GetStartCommand(cluster, processClass, processGroupID, podClient, 2, 2)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(command).To(Equal(strings.Join([]string{
 					"/usr/bin/fdbserver",
@@ -613,7 +709,7 @@ var _ = Describe("pod_models", func() {
 				cluster.Spec.FaultDomain = fdbtypes.FoundationDBClusterFaultDomain{}
 
 				podClient, _ := NewMockFdbPodClient(cluster, pod)
-				command, err = GetStartCommand(cluster, processClass, podClient, 1, 1)
+				command, err = GetStartCommand(cluster, processClass, processGroupID, podClient, 1, 1)
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -644,7 +740,7 @@ var _ = Describe("pod_models", func() {
 				}
 
 				podClient, _ := NewMockFdbPodClient(cluster, pod)
-				command, err = GetStartCommand(cluster, processClass, podClient, 1, 1)
+				command, err = GetStartCommand(cluster, processClass, processGroupID, podClient, 1, 1)
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -671,7 +767,7 @@ var _ = Describe("pod_models", func() {
 				cluster.Status.RunningVersion = fdbtypes.Versions.WithBinariesFromMainContainer.String()
 				podClient, _ := NewMockFdbPodClient(cluster, pod)
 
-				command, err = GetStartCommand(cluster, processClass, podClient, 1, 1)
+				command, err = GetStartCommand(cluster, processClass, processGroupID, podClient, 1, 1)
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -697,7 +793,7 @@ var _ = Describe("pod_models", func() {
 				cluster.Spec.Version = fdbtypes.Versions.WithoutBinariesFromMainContainer.String()
 				cluster.Status.RunningVersion = fdbtypes.Versions.WithoutBinariesFromMainContainer.String()
 				podClient, _ := NewMockFdbPodClient(cluster, pod)
-				command, err = GetStartCommand(cluster, processClass, podClient, 1, 1)
+				command, err = GetStartCommand(cluster, processClass, processGroupID, podClient, 1, 1)
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -0,0 +1,69 @@
+/*
+ * pdb_helper.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2021 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"fmt"
+	"strings"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// GetPodDisruptionBudgetName returns the name of the PodDisruptionBudget for
+// a process class.
+func GetPodDisruptionBudgetName(cluster *fdbtypes.FoundationDBCluster, processClass fdbtypes.ProcessClass) string {
+	return fmt.Sprintf("%s-%s", cluster.Name, strings.ReplaceAll(string(processClass), "_", "-"))
+}
+
+// GetPodDisruptionBudgetMaxUnavailable returns how many pods of a process
+// class can be disrupted at the same time without losing more fault domains
+// than the redundancy mode tolerates.
+func GetPodDisruptionBudgetMaxUnavailable(cluster *fdbtypes.FoundationDBCluster) intstr.IntOrString {
+	faultTolerance := cluster.DesiredFaultTolerance()
+	if faultTolerance <= 0 {
+		return intstr.FromInt(0)
+	}
+
+	return intstr.FromInt(faultTolerance)
+}
+
+// GetPodDisruptionBudget builds the PodDisruptionBudget for a process class.
+// Every pod is selected by the budget of its own process class only, since
+// the eviction API refuses to evict pods that are selected by more than one
+// budget.
+func GetPodDisruptionBudget(cluster *fdbtypes.FoundationDBCluster, processClass fdbtypes.ProcessClass, maxUnavailable intstr.IntOrString) *policyv1.PodDisruptionBudget {
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: GetObjectMetadata(cluster, nil, processClass, ""),
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MaxUnavailable: &maxUnavailable,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: GetPodMatchLabels(cluster, processClass, ""),
+			},
+		},
+	}
+	pdb.ObjectMeta.Name = GetPodDisruptionBudgetName(cluster, processClass)
+	pdb.ObjectMeta.OwnerReferences = BuildOwnerReference(cluster.TypeMeta, cluster.ObjectMeta)
+
+	return pdb
+}
@@ -0,0 +1,64 @@
+/*
+ * pdb_helper_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2021 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var _ = Describe("pdb_helper", func() {
+	var cluster *fdbtypes.FoundationDBCluster
+
+	BeforeEach(func() {
+		cluster = CreateDefaultCluster()
+		err := NormalizeClusterSpec(cluster, DeprecationOptions{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	DescribeTable("getting the max unavailable pods",
+		func(redundancyMode fdbtypes.RedundancyMode, faultDomainKey string, expected intstr.IntOrString) {
+			cluster.Spec.DatabaseConfiguration.RedundancyMode = redundancyMode
+			cluster.Spec.FaultDomain.Key = faultDomainKey
+			Expect(GetPodDisruptionBudgetMaxUnavailable(cluster)).To(Equal(expected))
+		},
+		Entry("single redundancy", fdbtypes.RedundancyModeSingle, "kubernetes.io/hostname", intstr.FromInt(0)),
+		Entry("double redundancy", fdbtypes.RedundancyModeDouble, "kubernetes.io/hostname", intstr.FromInt(1)),
+		Entry("triple redundancy", fdbtypes.RedundancyModeTriple, "kubernetes.io/hostname", intstr.FromInt(2)),
+		Entry("double redundancy with the Kubernetes cluster as fault domain", fdbtypes.RedundancyModeDouble, "foundationdb.org/kubernetes-cluster", intstr.FromInt(1)),
+		Entry("single redundancy with the Kubernetes cluster as fault domain", fdbtypes.RedundancyModeSingle, "foundationdb.org/kubernetes-cluster", intstr.FromInt(0)),
+	)
+
+	When("building the PodDisruptionBudget for a process class", func() {
+		It("should select the pods of the process class", func() {
+			pdb := GetPodDisruptionBudget(cluster, fdbtypes.ProcessClassClusterController, intstr.FromInt(1))
+			Expect(pdb.Name).To(Equal("operator-test-1-cluster-controller"))
+			Expect(pdb.Namespace).To(Equal(cluster.Namespace))
+			Expect(*pdb.Spec.MaxUnavailable).To(Equal(intstr.FromInt(1)))
+			Expect(pdb.Spec.Selector.MatchLabels).To(Equal(GetPodMatchLabels(cluster, fdbtypes.ProcessClassClusterController, "")))
+			Expect(pdb.Spec.Selector.MatchLabels).To(HaveKeyWithValue(cluster.GetProcessClassLabel(), string(fdbtypes.ProcessClassClusterController)))
+			Expect(pdb.OwnerReferences).To(HaveLen(1))
+		})
+	})
+})
@@ -86,6 +86,10 @@ type realFdbPodSidecarClient struct {
 	// sidecar.
 	tlsConfig *tls.Config
 
+	// token contains the token that is sent to the sidecar when the cluster
+	// uses token authentication.
+	token string
+
 	// logger is used to add common fields to log messages.
 	logger logr.Logger
 }
@@ -123,35 +127,93 @@ func NewFdbPodClient(cluster *fdbtypes.FoundationDBCluster, pod *corev1.Pod) (po
 
 	var tlsConfig = &tls.Config{}
 	if useTLS {
-		certFile := os.Getenv("FDB_TLS_CERTIFICATE_FILE")
-		keyFile := os.Getenv("FDB_TLS_KEY_FILE")
-		caFile := os.Getenv("FDB_TLS_CA_FILE")
-
-		if certFile == "" || keyFile == "" || caFile == "" {
-			return nil, errors.New("missing one or more TLS env vars: FDB_TLS_CERTIFICATE_FILE, FDB_TLS_KEY_FILE or FDB_TLS_CA_FILE")
-		}
-
-		cert, err := tls.LoadX509KeyPair(
-			certFile,
-			keyFile,
-		)
+		var err error
+		tlsConfig, err = getSidecarTLSConfig(cluster.Spec.SidecarAuthentication)
 		if err != nil {
 			return nil, err
 		}
-		tlsConfig.Certificates = []tls.Certificate{cert}
-		if os.Getenv("DISABLE_SIDECAR_TLS_CHECK") == "1" {
-			tlsConfig.InsecureSkipVerify = true
-		}
-		certPool := x509.NewCertPool()
-		caList, err := os.ReadFile(caFile)
+	}
+
+	var token string
+	if cluster.Spec.SidecarAuthentication.GetMode() == fdbtypes.SidecarAuthenticationModeToken {
+		var err error
+		token, err = getSidecarToken(cluster.Spec.SidecarAuthentication)
 		if err != nil {
 			return nil, err
 		}
-		certPool.AppendCertsFromPEM(caList)
-		tlsConfig.RootCAs = certPool
 	}
 
-	return &realFdbPodSidecarClient{Cluster: cluster, Pod: pod, useTLS: useTLS, tlsConfig: tlsConfig, logger: logger}, nil
+	return &realFdbPodSidecarClient{Cluster: cluster, Pod: pod, useTLS: useTLS, tlsConfig: tlsConfig, token: token, logger: logger}, nil
+}
+
+// getAuthenticationFile returns the path from the sidecar authentication
+// config, falling back to the path in an environment variable.
+func getAuthenticationFile(path string, envName string) string {
+	if path != "" {
+		return path
+	}
+	return os.Getenv(envName)
+}
+
+// getSidecarTLSConfig builds the TLS configuration for connections to the
+// sidecar, with the operator's client certificate and the CAs that are
+// trusted for the sidecar's certificate.
+func getSidecarTLSConfig(config *fdbtypes.SidecarAuthenticationConfig) (*tls.Config, error) {
+	if config == nil {
+		config = &fdbtypes.SidecarAuthenticationConfig{}
+	}
+
+	certFile := getAuthenticationFile(config.CertificateFile, "FDB_TLS_CERTIFICATE_FILE")
+	keyFile := getAuthenticationFile(config.KeyFile, "FDB_TLS_KEY_FILE")
+	caFile := getAuthenticationFile(config.CAFile, "FDB_TLS_CA_FILE")
+
+	if certFile == "" || keyFile == "" || caFile == "" {
+		return nil, errors.New("missing one or more TLS env vars: FDB_TLS_CERTIFICATE_FILE, FDB_TLS_KEY_FILE or FDB_TLS_CA_FILE")
+	}
+
+	cert, err := tls.LoadX509KeyPair(
+		certFile,
+		keyFile,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{}
+	tlsConfig.Certificates = []tls.Certificate{cert}
+	if os.Getenv("DISABLE_SIDECAR_TLS_CHECK") == "1" {
+		tlsConfig.InsecureSkipVerify = true
+	}
+	certPool := x509.NewCertPool()
+	caList, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	certPool.AppendCertsFromPEM(caList)
+	tlsConfig.RootCAs = certPool
+
+	return tlsConfig, nil
+}
+
+// getSidecarToken reads the token that the operator sends to the sidecar in
+// the Token authentication mode.
+func getSidecarToken(config *fdbtypes.SidecarAuthenticationConfig) (string, error) {
+	tokenFile := getAuthenticationFile(config.TokenFile, "FDB_SIDECAR_TOKEN_FILE")
+	if tokenFile == "" {
+		return "", errors.New("missing sidecar token file: set tokenFile in the sidecar authentication or the FDB_SIDECAR_TOKEN_FILE env var")
+	}
+
+	contents, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return "", err
+	}
+
+	token := strings.TrimSpace(string(contents))
+	if token == "" {
+		return "", fmt.Errorf("sidecar token file %s is empty", tokenFile)
+	}
+
+	return token, nil
 }
 
 // getListenIP gets the IP address that a pod listens on.
@@ -183,15 +245,16 @@ func (client *realFdbPodSidecarClient) makeRequest(method string, path string) (
 	}
 
 	url := fmt.Sprintf("%s://%s:8080/%s", protocol, client.getListenIP(), path)
+	var req *retryablehttp.Request
 	switch method {
 	case http.MethodGet:
 		// We assume that a get request should be relative fast.
 		retryClient.HTTPClient.Timeout = 5 * time.Second
-		resp, err = retryClient.Get(url)
+		req, err = retryablehttp.NewRequest(http.MethodGet, url, nil)
 	case http.MethodPost:
 		// A post request could take a little bit longer since we copy sometimes files.
 		retryClient.HTTPClient.Timeout = 10 * time.Second
-		resp, err = retryClient.Post(url, "application/json", strings.NewReader(""))
+		req, err = retryablehttp.NewRequest(http.MethodPost, url, strings.NewReader(""))
 	default:
 		return "", fmt.Errorf("unknown HTTP method %s", method)
 	}
@@ -200,8 +263,25 @@ func (client *realFdbPodSidecarClient) makeRequest(method string, path string) (
 		return "", err
 	}
 
+	if method == http.MethodPost {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if client.token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", client.token))
+	}
+
+	resp, err = retryClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return "", fmt.Errorf("sidecar in pod %s/%s rejected the request to %s with status %d", client.Pod.Namespace, client.Pod.Name, path, resp.StatusCode)
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	bodyText := string(body)
 
@@ -319,8 +399,9 @@ func (client *realFdbPodAnnotationClient) GetVariableSubstitutions() (map[string
 
 // UpdateFile checks if a file is up-to-date and tries to update it.
 func (client *realFdbPodAnnotationClient) UpdateFile(name string, contents string) (bool, error) {
-	if name == "fdb.cluster" {
-		// We can ignore cluster file updates in the unified image.
+	if name == "fdb.cluster" || name == corev1.TLSCertKey || name == corev1.TLSPrivateKeyKey {
+		// We can ignore cluster file and certificate updates in the unified
+		// image, since it reads these files directly from the volume.
 		return true, nil
 	}
 	if name == "fdbmonitor.conf" {
@@ -426,6 +507,27 @@ func (client *mockFdbPodClient) GetVariableSubstitutions() (map[string]string, e
 
 	substitutions["FDB_INSTANCE_ID"] = GetProcessGroupIDFromMeta(client.Cluster, client.Pod.ObjectMeta)
 
+	if client.Cluster.UseDNSInClusterFile() {
+		substitutions["FDB_DNS_NAME"] = GetPodDNSName(client.Cluster, client.Pod.Name)
+	}
+
+	for _, variable := range client.Cluster.Spec.SubstitutionVariables {
+		var value string
+		if variable.NodeLabel != "" {
+			value = client.Pod.Annotations[variable.GetNodeLabelAnnotation()]
+		} else if variable.PodAnnotation != "" {
+			value = client.Pod.Annotations[variable.PodAnnotation]
+		} else if variable.FieldPath == "spec.nodeName" {
+			value = client.Pod.Spec.NodeName
+		} else if variable.FieldPath == "metadata.name" {
+			value = client.Pod.Name
+		}
+
+		if value != "" {
+			substitutions[variable.Name] = value
+		}
+	}
+
 	version, err := fdbtypes.ParseFdbVersion(client.Cluster.Spec.Version)
 	if err != nil {
 		return nil, err
@@ -21,9 +21,14 @@
 package internal
 
 import (
+	"os"
+	"path/filepath"
+	"time"
+
 	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 )
 
 var _ = Describe("pod_client", func() {
@@ -58,4 +63,65 @@ var _ = Describe("pod_client", func() {
 			Expect(podHasSidecarTLS(pod)).To(BeTrue())
 		})
 	})
+
+	Context("with sidecar authentication", func() {
+		var directory string
+
+		BeforeEach(func() {
+			var err error
+			directory, err = os.MkdirTemp("", "sidecar-authentication")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(directory)).NotTo(HaveOccurred())
+		})
+
+		It("should load the client certificate from the configured paths", func() {
+			data, err := CreateTestTLSCertificate(time.Now().Add(24 * time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+
+			config := &fdbtypes.SidecarAuthenticationConfig{
+				CertificateFile: filepath.Join(directory, "client.crt"),
+				KeyFile:         filepath.Join(directory, "client.key"),
+				CAFile:          filepath.Join(directory, "ca.pem"),
+			}
+			Expect(os.WriteFile(config.CertificateFile, data[corev1.TLSCertKey], 0600)).NotTo(HaveOccurred())
+			Expect(os.WriteFile(config.KeyFile, data[corev1.TLSPrivateKeyKey], 0600)).NotTo(HaveOccurred())
+			Expect(os.WriteFile(config.CAFile, data[corev1.TLSCertKey], 0600)).NotTo(HaveOccurred())
+
+			tlsConfig, err := getSidecarTLSConfig(config)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tlsConfig.Certificates).To(HaveLen(1))
+			Expect(tlsConfig.RootCAs).NotTo(BeNil())
+		})
+
+		It("should read the token from the token file", func() {
+			config := &fdbtypes.SidecarAuthenticationConfig{TokenFile: filepath.Join(directory, "token")}
+			Expect(os.WriteFile(config.TokenFile, []byte("secret-token\n"), 0600)).NotTo(HaveOccurred())
+
+			token, err := getSidecarToken(config)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token).To(Equal("secret-token"))
+		})
+
+		It("should reject an empty token file", func() {
+			config := &fdbtypes.SidecarAuthenticationConfig{TokenFile: filepath.Join(directory, "token")}
+			Expect(os.WriteFile(config.TokenFile, []byte("\n"), 0600)).NotTo(HaveOccurred())
+
+			_, err := getSidecarToken(config)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should provide the token to the sidecar in the token mode", func() {
+			cluster.Spec.SidecarAuthentication = &fdbtypes.SidecarAuthenticationConfig{Mode: fdbtypes.SidecarAuthenticationModeToken}
+			spec, err := GetPodSpec(cluster, fdbtypes.ProcessClassStorage, 1)
+			Expect(err).NotTo(HaveOccurred())
+
+			env := GetEnvVars(spec.Containers[1])
+			Expect(env["FDB_SIDECAR_TOKEN"].ValueFrom.SecretKeyRef.Name).To(Equal("operator-test-1-sidecar-token"))
+			Expect(env["FDB_SIDECAR_TOKEN"].ValueFrom.SecretKeyRef.Key).To(Equal(SidecarTokenKey))
+			Expect(GetEnvVars(spec.InitContainers[0])).NotTo(HaveKey("FDB_SIDECAR_TOKEN"))
+		})
+	})
 })
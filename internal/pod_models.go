@@ -35,6 +35,10 @@ import (
 	"k8s.io/utils/pointer"
 )
 
+// SidecarTokenKey provides the key in the sidecar token secret that holds
+// the token for authenticating requests to the sidecar.
+const SidecarTokenKey = "token"
+
 var processClassSanitizationPattern = regexp.MustCompile("[^a-z0-9-]")
 
 // GetProcessGroupID generates an ID for a process group.
@@ -87,16 +91,11 @@ func GetService(cluster *fdbtypes.FoundationDBCluster, processClass fdbtypes.Pro
 	metadata.Name = name
 	metadata.OwnerReferences = owner
 
-	processesPerPod := 1
-	if processClass == fdbtypes.ProcessClassStorage {
-		processesPerPod = cluster.GetStorageServersPerPod()
-	}
-
 	return &corev1.Service{
 		ObjectMeta: metadata,
 		Spec: corev1.ServiceSpec{
 			Type:                     corev1.ServiceTypeClusterIP,
-			Ports:                    generateServicePorts(processesPerPod),
+			Ports:                    generateServicePorts(cluster.GetDesiredServersPerPod(processClass)),
 			PublishNotReadyAddresses: true,
 			Selector:                 GetPodMatchLabels(cluster, "", id),
 		},
@@ -150,6 +149,7 @@ func GetPodSpec(cluster *fdbtypes.FoundationDBCluster, processClass fdbtypes.Pro
 	processSettings := cluster.GetProcessSettings(processClass)
 	podSpec := processSettings.PodTemplate.Spec.DeepCopy()
 	useUnifiedImages := pointer.BoolDeref(cluster.Spec.UseUnifiedImage, false)
+	serversPerPod := cluster.GetDesiredServersPerPod(processClass)
 
 	var mainContainer *corev1.Container
 	var sidecarContainer *corev1.Container
@@ -222,10 +222,10 @@ func GetPodSpec(cluster *fdbtypes.FoundationDBCluster, processClass fdbtypes.Pro
 			"--log-path", "/var/log/fdb-trace-logs/monitor.log",
 		}
 
-		if cluster.Spec.StorageServersPerPod > 1 && processClass == fdbtypes.ProcessClassStorage {
-			storageServers := fmt.Sprintf("%d", cluster.Spec.StorageServersPerPod)
-			mainContainer.Args = append(mainContainer.Args, "--process-count", storageServers)
-			mainContainer.Env = append(mainContainer.Env, corev1.EnvVar{Name: "STORAGE_SERVERS_PER_POD", Value: storageServers})
+		if serversPerPod > 1 {
+			serversPerPodString := fmt.Sprintf("%d", serversPerPod)
+			mainContainer.Args = append(mainContainer.Args, "--process-count", serversPerPodString)
+			mainContainer.Env = append(mainContainer.Env, corev1.EnvVar{Name: getServersPerPodEnvironmentVariable(processClass), Value: serversPerPodString})
 		}
 
 		mainContainer.VolumeMounts = append(mainContainer.VolumeMounts,
@@ -324,8 +324,17 @@ func GetPodSpec(cluster *fdbtypes.FoundationDBCluster, processClass fdbtypes.Pro
 			return nil, err
 		}
 
-		if processClass == fdbtypes.ProcessClassStorage && cluster.GetStorageServersPerPod() > 1 {
-			sidecarContainer.Env = append(sidecarContainer.Env, corev1.EnvVar{Name: "STORAGE_SERVERS_PER_POD", Value: fmt.Sprintf("%d", cluster.GetStorageServersPerPod())})
+		if serversPerPod > 1 {
+			sidecarContainer.Env = append(sidecarContainer.Env, corev1.EnvVar{Name: getServersPerPodEnvironmentVariable(processClass), Value: fmt.Sprintf("%d", serversPerPod)})
+		}
+	}
+
+	if cluster.UseDNSInClusterFile() {
+		dnsNameEnv := corev1.EnvVar{Name: "FDB_DNS_NAME", Value: GetPodDNSName(cluster, podName)}
+		extendEnv(mainContainer, dnsNameEnv)
+		extendEnv(sidecarContainer, dnsNameEnv)
+		if !useUnifiedImages {
+			extendEnv(initContainer, dnsNameEnv)
 		}
 	}
 
@@ -344,7 +353,12 @@ func GetPodSpec(cluster *fdbtypes.FoundationDBCluster, processClass fdbtypes.Pro
 		mainVolumeSource.EmptyDir = &corev1.EmptyDirVolumeSource{}
 	}
 
-	monitorConfKey := GetConfigMapMonitorConfEntry(processClass, GetDesiredImageType(cluster), cluster.GetStorageServersPerPod())
+	// The key stays the same when the override expires, so the pod doesn't
+	// have to be recreated to go back to the monitor conf for its class.
+	monitorConfKey := GetConfigMapMonitorConfEntry(processClass, GetDesiredImageType(cluster), serversPerPod)
+	if cluster.HasParameterOverride(processGroupID) {
+		monitorConfKey = GetConfigMapProcessGroupMonitorConfEntry(processClass, processGroupID, GetDesiredImageType(cluster), serversPerPod)
+	}
 
 	var monitorConfFile string
 	if useUnifiedImages {
@@ -394,6 +408,14 @@ func GetPodSpec(cluster *fdbtypes.FoundationDBCluster, processClass fdbtypes.Pro
 		faultDomainKey = "kubernetes.io/hostname"
 	}
 
+	labelSelectors := make(map[string]string, len(cluster.Spec.LabelConfig.MatchLabels)+1)
+	for key, value := range cluster.Spec.LabelConfig.MatchLabels {
+		labelSelectors[key] = value
+	}
+
+	processClassLabel := cluster.GetProcessClassLabel()
+	labelSelectors[processClassLabel] = string(processClass)
+
 	if faultDomainKey != "foundationdb.org/none" && faultDomainKey != "foundationdb.org/kubernetes-cluster" {
 		if podSpec.Affinity == nil {
 			podSpec.Affinity = &corev1.Affinity{}
@@ -403,14 +425,6 @@ func GetPodSpec(cluster *fdbtypes.FoundationDBCluster, processClass fdbtypes.Pro
 			podSpec.Affinity.PodAntiAffinity = &corev1.PodAntiAffinity{}
 		}
 
-		labelSelectors := make(map[string]string, len(cluster.Spec.LabelConfig.MatchLabels)+1)
-		for key, value := range cluster.Spec.LabelConfig.MatchLabels {
-			labelSelectors[key] = value
-		}
-
-		processClassLabel := cluster.GetProcessClassLabel()
-		labelSelectors[processClassLabel] = string(processClass)
-
 		podSpec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(podSpec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
 			corev1.WeightedPodAffinityTerm{
 				Weight: 1,
@@ -419,6 +433,16 @@ func GetPodSpec(cluster *fdbtypes.FoundationDBCluster, processClass fdbtypes.Pro
 					LabelSelector: &metav1.LabelSelector{MatchLabels: labelSelectors},
 				},
 			})
+
+		if cluster.GetUseTopologySpreadConstraints() {
+			addTopologySpreadConstraint(podSpec, faultDomainKey, corev1.ScheduleAnyway, labelSelectors)
+		}
+	}
+
+	// The data halls must be balanced, otherwise losing a single data hall
+	// could take out more replicas than the redundancy mode tolerates.
+	if cluster.Spec.FaultDomain.DataHallKey != "" {
+		addTopologySpreadConstraint(podSpec, cluster.Spec.FaultDomain.DataHallKey, corev1.DoNotSchedule, labelSelectors)
 	}
 
 	for _, noSchedulePID := range cluster.Spec.Buggify.NoSchedule {
@@ -447,11 +471,23 @@ func GetPodSpec(cluster *fdbtypes.FoundationDBCluster, processClass fdbtypes.Pro
 		}
 	}
 
+	configureTLSCertificates(cluster, volumes, mainContainer, sidecarContainer, useUnifiedImages)
+
 	if !useUnifiedImages {
 		replaceContainers(podSpec.InitContainers, initContainer)
 	}
 	replaceContainers(podSpec.Containers, mainContainer, sidecarContainer)
 
+	if HasNodeLabelSubstitutionVariables(cluster) {
+		nodeLabelImage := sidecarContainer.Image
+		if !useUnifiedImages {
+			nodeLabelImage = initContainer.Image
+		}
+
+		podSpec.InitContainers = append(podSpec.InitContainers, getNodeLabelInitContainer(cluster, nodeLabelImage))
+		volumes = append(volumes, getPodAnnotationsVolume())
+	}
+
 	podSpec.Volumes = append(podSpec.Volumes, volumes...)
 
 	headlessService := GetHeadlessService(cluster)
@@ -464,6 +500,24 @@ func GetPodSpec(cluster *fdbtypes.FoundationDBCluster, processClass fdbtypes.Pro
 	return podSpec, nil
 }
 
+// addTopologySpreadConstraint adds a topology spread constraint for the
+// given topology key to the pod spec, unless the pod spec already contains a
+// constraint for that key.
+func addTopologySpreadConstraint(podSpec *corev1.PodSpec, topologyKey string, whenUnsatisfiable corev1.UnsatisfiableConstraintAction, labelSelectors map[string]string) {
+	for _, constraint := range podSpec.TopologySpreadConstraints {
+		if constraint.TopologyKey == topologyKey {
+			return
+		}
+	}
+
+	podSpec.TopologySpreadConstraints = append(podSpec.TopologySpreadConstraints, corev1.TopologySpreadConstraint{
+		MaxSkew:           1,
+		TopologyKey:       topologyKey,
+		WhenUnsatisfiable: whenUnsatisfiable,
+		LabelSelector:     &metav1.LabelSelector{MatchLabels: labelSelectors},
+	})
+}
+
 // configureSidecarContainerForCluster sets up a sidecar container for a sidecar
 // in the FDB cluster.
 func configureSidecarContainerForCluster(cluster *fdbtypes.FoundationDBCluster, container *corev1.Container, initMode bool, processGroupID string, allowOverride bool) error {
@@ -501,6 +555,11 @@ func configureSidecarContainer(container *corev1.Container, initMode bool, proce
 		if hasTrustedCAs {
 			sidecarArgs = append(sidecarArgs, "--copy-file", "ca.pem")
 		}
+		if optionalCluster != nil && optionalCluster.Spec.TLSCertificates != nil {
+			for _, file := range TLSCertificateFiles {
+				sidecarArgs = append(sidecarArgs, "--copy-file", file)
+			}
+		}
 		if optionalCluster != nil {
 			sidecarArgs = append(sidecarArgs,
 				"--input-monitor-conf", "fdbmonitor.conf",
@@ -547,9 +606,15 @@ func configureSidecarContainer(container *corev1.Container, initMode bool, proce
 			for _, substitution := range cluster.Spec.SidecarVariables {
 				sidecarArgs = append(sidecarArgs, "--substitute-variable", substitution)
 			}
+			for _, substitution := range GetSubstitutionVariableNames(cluster) {
+				sidecarArgs = append(sidecarArgs, "--substitute-variable", substitution)
+			}
 			if !version.HasInstanceIDInSidecarSubstitutions() {
 				sidecarArgs = append(sidecarArgs, "--substitute-variable", "FDB_INSTANCE_ID")
 			}
+			if cluster.UseDNSInClusterFile() {
+				sidecarArgs = append(sidecarArgs, "--substitute-variable", "FDB_DNS_NAME")
+			}
 		}
 
 		sidecarEnv = append(sidecarEnv, getEnvForMonitorConfigSubstitution(cluster, processGroupID)...)
@@ -625,6 +690,15 @@ func configureSidecarContainer(container *corev1.Container, initMode bool, proce
 
 	extendEnv(container, corev1.EnvVar{Name: "FDB_TLS_VERIFY_PEERS", Value: overrides.PeerVerificationRules})
 
+	if optionalCluster != nil && optionalCluster.Spec.SidecarAuthentication.GetMode() == fdbtypes.SidecarAuthenticationModeToken {
+		extendEnv(container, corev1.EnvVar{Name: "FDB_SIDECAR_TOKEN", ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: optionalCluster.Spec.SidecarAuthentication.GetTokenSecretName(optionalCluster.Name)},
+				Key:                  SidecarTokenKey,
+			},
+		}})
+	}
+
 	if hasTrustedCAs {
 		extendEnv(container, corev1.EnvVar{Name: "FDB_TLS_CA_FILE", Value: "/var/input-files/ca.pem"})
 	}
@@ -702,6 +776,7 @@ func getEnvForMonitorConfigSubstitution(cluster *fdbtypes.FoundationDBCluster, i
 	}
 
 	env = append(env, corev1.EnvVar{Name: "FDB_INSTANCE_ID", Value: instanceID})
+	env = append(env, getEnvForSubstitutionVariables(cluster)...)
 
 	return env
 }
@@ -718,7 +793,7 @@ func usePvc(cluster *fdbtypes.FoundationDBCluster, processClass fdbtypes.Process
 			storage = &storageCopy
 		}
 	}
-	return processClass.IsStateful() && (storage == nil || !storage.IsZero())
+	return (processClass.IsStateful() || cluster.IsDedicatedCoordinator(processClass)) && (storage == nil || !storage.IsZero())
 }
 
 // GetPvc builds a persistent volume claim for a FoundationDB process group.
@@ -753,7 +828,11 @@ func GetPvc(cluster *fdbtypes.FoundationDBCluster, processClass fdbtypes.Process
 
 	storage := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
 	if (&storage).IsZero() {
-		pvc.Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse("128G")
+		if cluster.IsDedicatedCoordinator(processClass) {
+			pvc.Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse("16G")
+		} else {
+			pvc.Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse("128G")
+		}
 	}
 
 	specHash, err := GetJSONHash(pvc.Spec)
@@ -943,23 +1022,43 @@ func GetBackupDeployment(backup *fdbtypes.FoundationDBBackup) (*appsv1.Deploymen
 	return deployment, nil
 }
 
-// GetStorageServersPerPodForPod returns the value of STORAGE_SERVERS_PER_POD from the sidecar or 1
-func GetStorageServersPerPodForPod(pod *corev1.Pod) (int, error) {
+// GetServersPerPodForPod returns the value of STORAGE_SERVERS_PER_POD for
+// storage Pods or LOG_SERVERS_PER_POD for log Pods from the sidecar or 1
+func GetServersPerPodForPod(pod *corev1.Pod, pClass fdbtypes.ProcessClass) (int, error) {
 	// If not specified we will default to 1
-	storageServersPerPod := 1
+	serversPerPod := 1
 	if pod == nil {
-		return storageServersPerPod, nil
+		return serversPerPod, nil
+	}
+
+	var variableName string
+	if pClass == fdbtypes.ProcessClassStorage {
+		variableName = "STORAGE_SERVERS_PER_POD"
+	} else if pClass.IsLogProcess() {
+		variableName = "LOG_SERVERS_PER_POD"
+	} else {
+		return serversPerPod, nil
 	}
 
 	for _, container := range pod.Spec.Containers {
 		for _, env := range container.Env {
-			if env.Name == "STORAGE_SERVERS_PER_POD" {
+			if env.Name == variableName {
 				return strconv.Atoi(env.Value)
 			}
 		}
 	}
 
-	return storageServersPerPod, nil
+	return serversPerPod, nil
+}
+
+// getServersPerPodEnvironmentVariable returns the name of the environment
+// variable that tells a Pod how many processes it runs.
+func getServersPerPodEnvironmentVariable(pClass fdbtypes.ProcessClass) string {
+	if pClass.IsLogProcess() {
+		return "LOG_SERVERS_PER_POD"
+	}
+
+	return "STORAGE_SERVERS_PER_POD"
 }
 
 // GetPodMetadata returns the metadata for a specific Pod
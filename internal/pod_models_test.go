@@ -22,6 +22,7 @@ package internal
 
 import (
 	"fmt"
+	"time"
 
 	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
 	. "github.com/onsi/ginkgo"
@@ -683,6 +684,39 @@ var _ = Describe("pod_models", func() {
 				})
 			})
 
+			When("running multiple log servers per disk", func() {
+				BeforeEach(func() {
+					cluster.Spec.LogServersPerPod = 2
+					spec, err = GetPodSpec(cluster, fdbtypes.ProcessClassLog, 1)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("should pass the process count to the main container", func() {
+					mainContainer := spec.Containers[0]
+					Expect(mainContainer.Name).To(Equal("foundationdb"))
+					Expect(mainContainer.Args).To(Equal([]string{
+						"--input-dir", "/var/dynamic-conf",
+						"--log-path", "/var/log/fdb-trace-logs/monitor.log",
+						"--process-count", "2",
+					}))
+					Expect(mainContainer.Env).To(ContainElement(corev1.EnvVar{Name: "LOG_SERVERS_PER_POD", Value: "2"}))
+					Expect(mainContainer.Env).NotTo(ContainElement(corev1.EnvVar{Name: "STORAGE_SERVERS_PER_POD", Value: "2"}))
+				})
+
+				It("mounts the multiple-log config map", func() {
+					Expect(spec.Volumes[2]).To(Equal(corev1.Volume{
+						Name: "config-map",
+						VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+							LocalObjectReference: corev1.LocalObjectReference{Name: fmt.Sprintf("%s-config", cluster.Name)},
+							Items: []corev1.KeyToPath{
+								{Key: "fdbmonitor-conf-log-json-multiple", Path: "config.json"},
+								{Key: ClusterFileKey, Path: "fdb.cluster"},
+							},
+						}},
+					}))
+				})
+			})
+
 			Context("with an instance that is crash looping", func() {
 				BeforeEach(func() {
 					cluster.Spec.Buggify.CrashLoop = []string{"storage-1"}
@@ -956,6 +990,33 @@ var _ = Describe("pod_models", func() {
 			})
 		})
 
+		Context("with a basic log process group with multiple log servers per disk", func() {
+			BeforeEach(func() {
+				cluster.Spec.LogServersPerPod = 2
+				spec, err = GetPodSpec(cluster, fdbtypes.ProcessClassLog, 1)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should pass the process count to the sidecar", func() {
+				sidecarContainer := spec.Containers[1]
+				Expect(sidecarContainer.Name).To(Equal("foundationdb-kubernetes-sidecar"))
+				Expect(sidecarContainer.Env).To(ContainElement(corev1.EnvVar{Name: "LOG_SERVERS_PER_POD", Value: "2"}))
+			})
+
+			It("mounts the log density config map", func() {
+				Expect(spec.Volumes[2]).To(Equal(corev1.Volume{
+					Name: "config-map",
+					VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: fmt.Sprintf("%s-config", cluster.Name)},
+						Items: []corev1.KeyToPath{
+							{Key: "fdbmonitor-conf-log-density-2", Path: "fdbmonitor.conf"},
+							{Key: ClusterFileKey, Path: "fdb.cluster"},
+						},
+					}},
+				}))
+			})
+		})
+
 		Context("with a basic storage process group with multiple storage servers per disk", func() {
 			BeforeEach(func() {
 				cluster.Spec.StorageServersPerPod = 2
@@ -1346,6 +1407,31 @@ var _ = Describe("pod_models", func() {
 			})
 		})
 
+		Context("with DNS names in the cluster file", func() {
+			BeforeEach(func() {
+				cluster.Spec.Version = "7.0.0"
+				cluster.Spec.Routing.UseDNSInClusterFile = pointer.Bool(true)
+				spec, err = GetPodSpec(cluster, fdbtypes.ProcessClassStorage, 1)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should have the hostname and subdomain set", func() {
+				Expect(spec.Hostname).To(Equal("operator-test-1-storage-1"))
+				Expect(spec.Subdomain).To(Equal("operator-test-1"))
+			})
+
+			It("should pass the DNS name to the containers", func() {
+				dnsNameEnv := corev1.EnvVar{Name: "FDB_DNS_NAME", Value: "operator-test-1-storage-1.operator-test-1.my-ns.svc.cluster.local"}
+				Expect(spec.InitContainers[0].Env).To(ContainElement(dnsNameEnv))
+				Expect(spec.Containers[0].Env).To(ContainElement(dnsNameEnv))
+				Expect(spec.Containers[1].Env).To(ContainElement(dnsNameEnv))
+			})
+
+			It("should substitute the DNS name in the sidecar", func() {
+				Expect(spec.Containers[1].Args).To(ContainElements("--substitute-variable", "FDB_DNS_NAME"))
+			})
+		})
+
 		Context("with custom resources", func() {
 			BeforeEach(func() {
 				cluster = CreateDefaultCluster()
@@ -1572,6 +1658,94 @@ var _ = Describe("pod_models", func() {
 			})
 		})
 
+		Context("with topology spread constraints", func() {
+			BeforeEach(func() {
+				cluster.Spec.FaultDomain = fdbtypes.FoundationDBClusterFaultDomain{
+					Key:                          "topology.kubernetes.io/zone",
+					UseTopologySpreadConstraints: pointer.Bool(true),
+				}
+				spec, err = GetPodSpec(cluster, fdbtypes.ProcessClassStorage, 1)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should spread the pods across the fault domains", func() {
+				Expect(spec.TopologySpreadConstraints).To(Equal([]corev1.TopologySpreadConstraint{
+					{
+						MaxSkew:           1,
+						TopologyKey:       "topology.kubernetes.io/zone",
+						WhenUnsatisfiable: corev1.ScheduleAnyway,
+						LabelSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{
+								OldFDBClusterLabel:      cluster.Name,
+								OldFDBProcessClassLabel: string(fdbtypes.ProcessClassStorage),
+							},
+						},
+					},
+				}))
+			})
+
+			It("should keep the pod anti affinity", func() {
+				Expect(spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution).To(HaveLen(1))
+			})
+		})
+
+		Context("with a data hall key", func() {
+			BeforeEach(func() {
+				cluster.Spec.FaultDomain = fdbtypes.FoundationDBClusterFaultDomain{
+					DataHallKey: "topology.kubernetes.io/zone",
+				}
+				spec, err = GetPodSpec(cluster, fdbtypes.ProcessClassStorage, 1)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should spread the pods across the data halls", func() {
+				Expect(spec.TopologySpreadConstraints).To(Equal([]corev1.TopologySpreadConstraint{
+					{
+						MaxSkew:           1,
+						TopologyKey:       "topology.kubernetes.io/zone",
+						WhenUnsatisfiable: corev1.DoNotSchedule,
+						LabelSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{
+								OldFDBClusterLabel:      cluster.Name,
+								OldFDBProcessClassLabel: string(fdbtypes.ProcessClassStorage),
+							},
+						},
+					},
+				}))
+			})
+
+			It("should set the pod anti affinity for the zones", func() {
+				Expect(spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution).To(HaveLen(1))
+				Expect(spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution[0].PodAffinityTerm.TopologyKey).To(Equal("kubernetes.io/hostname"))
+			})
+
+			When("the pod template already defines a constraint for the data hall key", func() {
+				BeforeEach(func() {
+					cluster.Spec.Processes = map[fdbtypes.ProcessClass]fdbtypes.ProcessSettings{fdbtypes.ProcessClassGeneral: {PodTemplate: &corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+								{
+									MaxSkew:           2,
+									TopologyKey:       "topology.kubernetes.io/zone",
+									WhenUnsatisfiable: corev1.ScheduleAnyway,
+								},
+							},
+						},
+					}}}
+					err = NormalizeClusterSpec(cluster, DeprecationOptions{})
+					Expect(err).NotTo(HaveOccurred())
+
+					spec, err = GetPodSpec(cluster, fdbtypes.ProcessClassStorage, 1)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("should keep the constraint from the pod template", func() {
+					Expect(spec.TopologySpreadConstraints).To(HaveLen(1))
+					Expect(spec.TopologySpreadConstraints[0].MaxSkew).To(BeNumerically("==", 2))
+				})
+			})
+		})
+
 		Context("with custom containers", func() {
 			BeforeEach(func() {
 				cluster = CreateDefaultCluster()
@@ -1894,6 +2068,36 @@ var _ = Describe("pod_models", func() {
 			})
 		})
 
+		Context("with a parameter override for the process group", func() {
+			BeforeEach(func() {
+				cluster.Spec.ProcessGroupParameterOverrides = map[string]fdbtypes.ProcessGroupParameterOverride{
+					"storage-1": {CustomParameters: fdbtypes.FoundationDBCustomParameters{"knob_trace_level=1"}},
+				}
+				spec, err = GetPodSpec(cluster, fdbtypes.ProcessClassStorage, 1)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("mounts the monitor conf for the process group", func() {
+				Expect(spec.Volumes[2].Name).To(Equal("config-map"))
+				Expect(spec.Volumes[2].ConfigMap.Items[0]).To(Equal(corev1.KeyToPath{Key: "fdbmonitor-conf-storage-process-group-storage-1", Path: "fdbmonitor.conf"}))
+			})
+
+			It("uses the monitor conf for the process class for other process groups", func() {
+				spec, err = GetPodSpec(cluster, fdbtypes.ProcessClassStorage, 2)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(spec.Volumes[2].ConfigMap.Items[0]).To(Equal(corev1.KeyToPath{Key: "fdbmonitor-conf-storage", Path: "fdbmonitor.conf"}))
+			})
+
+			It("keeps the monitor conf for the process group when the override expires", func() {
+				override := cluster.Spec.ProcessGroupParameterOverrides["storage-1"]
+				override.ExpirationTime = &metav1.Time{Time: time.Now().Add(-1 * time.Minute)}
+				cluster.Spec.ProcessGroupParameterOverrides["storage-1"] = override
+				spec, err = GetPodSpec(cluster, fdbtypes.ProcessClassStorage, 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(spec.Volumes[2].ConfigMap.Items[0]).To(Equal(corev1.KeyToPath{Key: "fdbmonitor-conf-storage-process-group-storage-1", Path: "fdbmonitor.conf"}))
+			})
+		})
+
 		Context("with custom sidecar version", func() {
 			BeforeEach(func() {
 				cluster.Spec.SidecarContainer.ImageConfigs = []fdbtypes.ImageConfig{
@@ -2468,6 +2672,22 @@ var _ = Describe("pod_models", func() {
 				}))
 			})
 		})
+
+		Context("with a log process group with multiple log servers per Pod", func() {
+			BeforeEach(func() {
+				cluster.Spec.LogServersPerPod = 2
+				service, err = GetService(cluster, fdbtypes.ProcessClassLog, 1)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should add the ports for both processes", func() {
+				Expect(len(service.Spec.Ports)).To(Equal(4))
+				Expect(service.Spec.Ports[2].Name).To(Equal("tls-2"))
+				Expect(service.Spec.Ports[2].Port).To(Equal(int32(4502)))
+				Expect(service.Spec.Ports[3].Name).To(Equal("non-tls-2"))
+				Expect(service.Spec.Ports[3].Port).To(Equal(int32(4503)))
+			})
+		})
 	})
 
 	Describe("GetPvc", func() {
@@ -2504,6 +2724,132 @@ var _ = Describe("pod_models", func() {
 			})
 		})
 
+		Context("with a dedicated coordinator process group", func() {
+			BeforeEach(func() {
+				cluster = CreateDefaultCluster()
+				cluster.Spec.UseDedicatedCoordinators = pointer.Bool(true)
+			})
+
+			JustBeforeEach(func() {
+				err = NormalizeClusterSpec(cluster, DeprecationOptions{})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should use a small volume by default", func() {
+				pvc, err = GetPvc(cluster, fdbtypes.ProcessClassCoordinator, 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pvc.Name).To(Equal(fmt.Sprintf("%s-coordinator-1-data", cluster.Name)))
+				Expect(pvc.Spec.Resources.Requests[corev1.ResourceStorage]).To(Equal(resource.MustParse("16G")))
+			})
+
+			It("should use small resources for the main container by default", func() {
+				spec, err := GetPodSpec(cluster, fdbtypes.ProcessClassCoordinator, 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(spec.Containers[0].Name).To(Equal("foundationdb"))
+				Expect(spec.Containers[0].Resources).To(Equal(corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("512Mi"),
+					},
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("512Mi"),
+					},
+				}))
+			})
+
+			It("should keep the default resources for other process classes", func() {
+				spec, err := GetPodSpec(cluster, fdbtypes.ProcessClassStorage, 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(spec.Containers[0].Resources.Requests[corev1.ResourceCPU]).To(Equal(resource.MustParse("1")))
+			})
+
+			When("the general process settings define resources and storage", func() {
+				BeforeEach(func() {
+					cluster.Spec.Processes = map[fdbtypes.ProcessClass]fdbtypes.ProcessSettings{
+						fdbtypes.ProcessClassGeneral: {
+							PodTemplate: &corev1.PodTemplateSpec{
+								Spec: corev1.PodSpec{
+									Containers: []corev1.Container{
+										{
+											Name: "foundationdb",
+											Resources: corev1.ResourceRequirements{
+												Requests: corev1.ResourceList{
+													corev1.ResourceCPU: resource.MustParse("2"),
+												},
+											},
+										},
+									},
+								},
+							},
+							VolumeClaimTemplate: &corev1.PersistentVolumeClaim{
+								Spec: corev1.PersistentVolumeClaimSpec{
+									Resources: corev1.ResourceRequirements{
+										Requests: corev1.ResourceList{
+											corev1.ResourceStorage: resource.MustParse("32G"),
+										},
+									},
+								},
+							},
+						},
+					}
+				})
+
+				It("should only use the defaults for the values that are not set", func() {
+					spec, err := GetPodSpec(cluster, fdbtypes.ProcessClassCoordinator, 1)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(spec.Containers[0].Resources).To(Equal(corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("2"),
+							corev1.ResourceMemory: resource.MustParse("512Mi"),
+						},
+						Limits: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("2"),
+							corev1.ResourceMemory: resource.MustParse("512Mi"),
+						},
+					}))
+				})
+
+				It("should use the volume from the general settings", func() {
+					pvc, err = GetPvc(cluster, fdbtypes.ProcessClassCoordinator, 1)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(pvc.Spec.Resources.Requests[corev1.ResourceStorage]).To(Equal(resource.MustParse("32G")))
+				})
+			})
+
+			When("the coordinator process class has its own settings", func() {
+				BeforeEach(func() {
+					cluster.Spec.Processes = map[fdbtypes.ProcessClass]fdbtypes.ProcessSettings{
+						fdbtypes.ProcessClassCoordinator: {
+							VolumeClaimTemplate: &corev1.PersistentVolumeClaim{
+								Spec: corev1.PersistentVolumeClaimSpec{
+									Resources: corev1.ResourceRequirements{
+										Requests: corev1.ResourceList{
+											corev1.ResourceStorage: resource.MustParse("4G"),
+										},
+									},
+								},
+							},
+						},
+					}
+				})
+
+				It("should use the volume from the settings", func() {
+					pvc, err = GetPvc(cluster, fdbtypes.ProcessClassCoordinator, 1)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(pvc.Spec.Resources.Requests[corev1.ResourceStorage]).To(Equal(resource.MustParse("4G")))
+				})
+			})
+		})
+
+		Context("with a coordinator process group without dedicated coordinators", func() {
+			It("should not create a PVC", func() {
+				pvc, err = GetPvc(cluster, fdbtypes.ProcessClassCoordinator, 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pvc).To(BeNil())
+			})
+		})
+
 		Context("with custom resource labels", func() {
 			BeforeEach(func() {
 				cluster.Spec.LabelConfig = fdbtypes.LabelConfig{
@@ -2734,6 +3080,7 @@ var _ = Describe("pod_models", func() {
 					Selector: map[string]string{
 						OldFDBClusterLabel: "operator-test-1",
 					},
+					PublishNotReadyAddresses: true,
 				}))
 			})
 		})
@@ -2765,6 +3112,7 @@ var _ = Describe("pod_models", func() {
 						"fdb-custom-name":         "operator-test-1",
 						"fdb-managed-by-operator": "true",
 					},
+					PublishNotReadyAddresses: true,
 				}))
 			})
 		})
@@ -3251,7 +3599,7 @@ var _ = Describe("pod_models", func() {
 		})
 	})
 
-	Describe("GetStorageServersPerPodForPod", func() {
+	Describe("GetServersPerPodForPod", func() {
 		Context("when env var is set with 1", func() {
 			It("should return 1", func() {
 				pod := &corev1.Pod{
@@ -3267,7 +3615,7 @@ var _ = Describe("pod_models", func() {
 					},
 				}
 
-				storageServersPerPod, err := GetStorageServersPerPodForPod(pod)
+				storageServersPerPod, err := GetServersPerPodForPod(pod, fdbtypes.ProcessClassStorage)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(storageServersPerPod).To(Equal(1))
 			})
@@ -3288,12 +3636,43 @@ var _ = Describe("pod_models", func() {
 					},
 				}
 
-				storageServersPerPod, err := GetStorageServersPerPodForPod(pod)
+				storageServersPerPod, err := GetServersPerPodForPod(pod, fdbtypes.ProcessClassStorage)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(storageServersPerPod).To(Equal(2))
 			})
 		})
 
+		Context("when the log env var is set with 2", func() {
+			var pod *corev1.Pod
+
+			BeforeEach(func() {
+				pod = &corev1.Pod{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{
+							Env: []corev1.EnvVar{
+								{
+									Name:  "LOG_SERVERS_PER_POD",
+									Value: "2",
+								},
+							},
+						}},
+					},
+				}
+			})
+
+			It("should return 2 for a log Pod", func() {
+				logServersPerPod, err := GetServersPerPodForPod(pod, fdbtypes.ProcessClassLog)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(logServersPerPod).To(Equal(2))
+			})
+
+			It("should return 1 for a storage Pod", func() {
+				storageServersPerPod, err := GetServersPerPodForPod(pod, fdbtypes.ProcessClassStorage)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(storageServersPerPod).To(Equal(1))
+			})
+		})
+
 		Context("when env var is unset", func() {
 			It("should return 1", func() {
 				pod := &corev1.Pod{
@@ -3304,7 +3683,7 @@ var _ = Describe("pod_models", func() {
 					},
 				}
 
-				storageServersPerPod, err := GetStorageServersPerPodForPod(pod)
+				storageServersPerPod, err := GetServersPerPodForPod(pod, fdbtypes.ProcessClassStorage)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(storageServersPerPod).To(Equal(1))
 			})
@@ -3312,7 +3691,7 @@ var _ = Describe("pod_models", func() {
 
 		Context("when pod is nil", func() {
 			It("should return 1", func() {
-				storageServersPerPod, err := GetStorageServersPerPodForPod(nil)
+				storageServersPerPod, err := GetServersPerPodForPod(nil, fdbtypes.ProcessClassStorage)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(storageServersPerPod).To(Equal(1))
 			})
@@ -3322,7 +3701,7 @@ var _ = Describe("pod_models", func() {
 			It("should return 1", func() {
 				pod := &corev1.Pod{}
 
-				storageServersPerPod, err := GetStorageServersPerPodForPod(pod)
+				storageServersPerPod, err := GetServersPerPodForPod(pod, fdbtypes.ProcessClassStorage)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(storageServersPerPod).To(Equal(1))
 			})
@@ -3334,7 +3713,7 @@ var _ = Describe("pod_models", func() {
 					Spec: corev1.PodSpec{},
 				}
 
-				storageServersPerPod, err := GetStorageServersPerPodForPod(pod)
+				storageServersPerPod, err := GetServersPerPodForPod(pod, fdbtypes.ProcessClassStorage)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(storageServersPerPod).To(Equal(1))
 			})
@@ -176,7 +176,7 @@ func processGroupNeedsRemoval(cluster *fdbtypes.FoundationDBCluster, pod *corev1
 
 	if processClass == fdbtypes.ProcessClassStorage {
 		// Replace the process group if the storage servers differ
-		storageServersPerPod, err := internal.GetStorageServersPerPodForPod(pod)
+		storageServersPerPod, err := internal.GetServersPerPodForPod(pod, processClass)
 		if err != nil {
 			return false, err
 		}
@@ -186,6 +186,33 @@ func processGroupNeedsRemoval(cluster *fdbtypes.FoundationDBCluster, pod *corev1
 				"reason", fmt.Sprintf("storageServersPerPod has changed from %d to %d", storageServersPerPod, cluster.GetStorageServersPerPod()))
 			return true, nil
 		}
+
+		// Replace the process group if its storage servers use a different
+		// storage engine than the database, the replacement is requested and
+		// the database doesn't move them to the new storage engine by itself.
+		if cluster.GetReplaceOnStorageEngineChange() && processGroupStatus.StorageWiggle != nil && !cluster.UsesDatabaseStorageMigration() {
+			storageEngine := processGroupStatus.StorageWiggle.StorageEngine
+			desiredStorageEngine := cluster.Status.DatabaseConfiguration.StorageEngine
+			if storageEngine != "" && desiredStorageEngine != "" && storageEngine != desiredStorageEngine {
+				logger.Info("Replace process group",
+					"reason", fmt.Sprintf("storageEngine has changed from %s to %s", storageEngine, desiredStorageEngine))
+				return true, nil
+			}
+		}
+	}
+
+	if processClass.IsLogProcess() {
+		// Replace the process group if the log servers differ
+		logServersPerPod, err := internal.GetServersPerPodForPod(pod, processClass)
+		if err != nil {
+			return false, err
+		}
+
+		if logServersPerPod != cluster.GetLogServersPerPod() {
+			logger.Info("Replace process group",
+				"reason", fmt.Sprintf("logServersPerPod has changed from %d to %d", logServersPerPod, cluster.GetLogServersPerPod()))
+			return true, nil
+		}
 	}
 
 	expectedNodeSelector := cluster.GetProcessSettings(processClass).PodTemplate.Spec.NodeSelector
@@ -241,6 +241,102 @@ var _ = Describe("replace_misconfigured_pods", func() {
 		})
 	})
 
+	When("the storage servers use a different storage engine than the database", func() {
+		var status *fdbtypes.ProcessGroupStatus
+
+		BeforeEach(func() {
+			cluster.Status.DatabaseConfiguration.StorageEngine = fdbtypes.StorageEngineSSD2
+			status = &fdbtypes.ProcessGroupStatus{
+				ProcessGroupID: processGroupName,
+				StorageWiggle: &fdbtypes.StorageWiggleStatus{
+					StorageEngine: fdbtypes.StorageEngineMemory2,
+				},
+			}
+		})
+
+		It("should not need a removal", func() {
+			needsRemoval, err := processGroupNeedsRemoval(cluster, pod, status, log)
+			Expect(needsRemoval).To(BeFalse())
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		When("the replacement on storage engine changes is enabled", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.ReplaceOnStorageEngineChange = pointer.Bool(true)
+			})
+
+			It("should need a removal", func() {
+				needsRemoval, err := processGroupNeedsRemoval(cluster, pod, status, log)
+				Expect(needsRemoval).To(BeTrue())
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should not need a removal when the storage engine matches", func() {
+				status.StorageWiggle.StorageEngine = fdbtypes.StorageEngineSSD2
+				needsRemoval, err := processGroupNeedsRemoval(cluster, pod, status, log)
+				Expect(needsRemoval).To(BeFalse())
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			When("the perpetual storage wiggle migrates the storage servers", func() {
+				BeforeEach(func() {
+					cluster.Spec.Version = fdbtypes.Versions.WithPerpetualStorageWiggle.String()
+					cluster.Spec.DatabaseConfiguration.PerpetualStorageWiggle = pointer.Int(1)
+				})
+
+				It("should not need a removal", func() {
+					needsRemoval, err := processGroupNeedsRemoval(cluster, pod, status, log)
+					Expect(needsRemoval).To(BeFalse())
+					Expect(err).NotTo(HaveOccurred())
+				})
+			})
+		})
+	})
+
+	Context("when the logServersPerPod is changed for a log class process group", func() {
+		It("should need a removal", func() {
+			pod.ObjectMeta = metav1.ObjectMeta{
+				Labels: map[string]string{
+					fdbtypes.FDBProcessGroupIDLabel:    fmt.Sprintf("%s-1337", fdbtypes.ProcessClassLog),
+					fdbtypes.FDBProcessClassLabel:      string(fdbtypes.ProcessClassLog),
+					internal.OldFDBProcessGroupIDLabel: fmt.Sprintf("%s-1337", fdbtypes.ProcessClassLog),
+					internal.OldFDBProcessClassLabel:   string(fdbtypes.ProcessClassLog),
+				},
+				Annotations: map[string]string{},
+			}
+
+			status := &fdbtypes.ProcessGroupStatus{
+				ProcessGroupID: processGroupName,
+				Remove:         false,
+			}
+			needsRemoval, err := processGroupNeedsRemoval(cluster, pod, status, log)
+			Expect(needsRemoval).To(BeFalse())
+			Expect(err).NotTo(HaveOccurred())
+
+			cluster.Spec.LogServersPerPod = 2
+			needsRemoval, err = processGroupNeedsRemoval(cluster, pod, status, log)
+			Expect(needsRemoval).To(BeTrue())
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("when the logServersPerPod is changed for a storage class process group", func() {
+		It("should not need a removal", func() {
+			status := &fdbtypes.ProcessGroupStatus{
+				ProcessGroupID: processGroupName,
+				Remove:         false,
+			}
+			needsRemoval, err := processGroupNeedsRemoval(cluster, pod, status, log)
+			Expect(needsRemoval).To(BeFalse())
+			Expect(err).NotTo(HaveOccurred())
+
+			cluster.Spec.LogServersPerPod = 2
+			needsRemoval, err = processGroupNeedsRemoval(cluster, pod, status, log)
+			Expect(needsRemoval).To(BeFalse())
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
 	Context("when the nodeSelector changes", func() {
 		It("should need a removal", func() {
 			status := &fdbtypes.ProcessGroupStatus{
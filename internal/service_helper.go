@@ -21,14 +21,15 @@
 package internal
 
 import (
+	"fmt"
+
 	"github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
 	v1 "k8s.io/api/core/v1"
 )
 
 // GetHeadlessService builds a headless service for a FoundationDB cluster.
 func GetHeadlessService(cluster *v1beta1.FoundationDBCluster) *v1.Service {
-	headless := cluster.Spec.Routing.HeadlessService
-	if headless == nil || !*headless {
+	if !cluster.NeedsHeadlessService() {
 		return nil
 	}
 
@@ -38,6 +39,14 @@ func GetHeadlessService(cluster *v1beta1.FoundationDBCluster) *v1.Service {
 	service.ObjectMeta.Name = cluster.ObjectMeta.Name
 	service.Spec.ClusterIP = "None"
 	service.Spec.Selector = cluster.Spec.LabelConfig.MatchLabels
+	// The DNS records must exist before the processes are ready, since the
+	// coordinators can be referenced by their DNS names in the cluster file.
+	service.Spec.PublishNotReadyAddresses = true
 
 	return service
 }
+
+// GetPodDNSName determines the fully qualified DNS name for a pod.
+func GetPodDNSName(cluster *v1beta1.FoundationDBCluster, podName string) string {
+	return fmt.Sprintf("%s.%s.%s.svc.%s", podName, cluster.Name, cluster.Namespace, cluster.GetDNSDomain())
+}
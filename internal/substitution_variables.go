@@ -0,0 +1,353 @@
+/*
+ * substitution_variables.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2021 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	monitorapi "github.com/apple/foundationdb/fdbkubernetesmonitor/api"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// NodeLabelInitContainerName provides the name of the init container
+	// that waits for the annotations with the node labels.
+	NodeLabelInitContainerName = "foundationdb-node-labels"
+
+	// dataHallVariableName provides the name of the substitution variable
+	// that reads the data hall from the node label for the data hall key.
+	dataHallVariableName = "FDB_DATA_HALL"
+
+	// podAnnotationsVolumeName provides the name of the volume that exposes
+	// the annotations of the pod.
+	podAnnotationsVolumeName = "pod-annotations"
+
+	// podAnnotationsMountPath provides the path where the annotations of the
+	// pod are mounted.
+	podAnnotationsMountPath = "/var/pod-info"
+)
+
+// variableReferencePattern matches references to variables like $FDB_ZONE_ID.
+var variableReferencePattern = regexp.MustCompile(`\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// builtInSubstitutionVariables provides the variables that the operator
+// always makes available to the processes.
+var builtInSubstitutionVariables = []string{
+	"BINARY_DIR",
+	"FDB_DNS_NAME",
+	"FDB_INSTANCE_ID",
+	"FDB_MACHINE_ID",
+	"FDB_POD_IP",
+	"FDB_POD_NAME",
+	"FDB_POD_NAMESPACE",
+	"FDB_PUBLIC_IP",
+	"FDB_ZONE_ID",
+}
+
+// GetSubstitutionVariableNames provides the names of the substitution
+// variables from the cluster spec.
+func GetSubstitutionVariableNames(cluster *fdbtypes.FoundationDBCluster) []string {
+	names := make([]string, 0, len(cluster.Spec.SubstitutionVariables))
+	for _, variable := range cluster.Spec.SubstitutionVariables {
+		names = append(names, variable.Name)
+	}
+	return names
+}
+
+// getEnvForSubstitutionVariables provides the environment variables for the
+// substitution variables from the cluster spec that have a source.
+func getEnvForSubstitutionVariables(cluster *fdbtypes.FoundationDBCluster) []corev1.EnvVar {
+	env := make([]corev1.EnvVar, 0, len(cluster.Spec.SubstitutionVariables))
+	for _, variable := range cluster.Spec.SubstitutionVariables {
+		var fieldPath string
+		if variable.NodeLabel != "" {
+			fieldPath = fmt.Sprintf("metadata.annotations['%s']", variable.GetNodeLabelAnnotation())
+		} else if variable.PodAnnotation != "" {
+			fieldPath = fmt.Sprintf("metadata.annotations['%s']", variable.PodAnnotation)
+		} else if variable.FieldPath != "" {
+			fieldPath = variable.FieldPath
+		} else {
+			continue
+		}
+
+		env = append(env, corev1.EnvVar{Name: variable.Name, ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{FieldPath: fieldPath},
+		}})
+	}
+
+	return env
+}
+
+// GetNodeLabelAnnotations provides the annotations that hold the values of
+// the substitution variables that are read from the labels of a node.
+func GetNodeLabelAnnotations(cluster *fdbtypes.FoundationDBCluster, node *corev1.Node) map[string]string {
+	annotations := make(map[string]string)
+	for _, variable := range cluster.Spec.SubstitutionVariables {
+		if variable.NodeLabel == "" {
+			continue
+		}
+
+		// The annotation is set even when the node doesn't have the label,
+		// so the pod doesn't wait for it forever.
+		annotations[variable.GetNodeLabelAnnotation()] = node.Labels[variable.NodeLabel]
+	}
+
+	return annotations
+}
+
+// HasNodeLabelSubstitutionVariables determines whether any substitution
+// variable in the cluster spec reads its value from a node label.
+func HasNodeLabelSubstitutionVariables(cluster *fdbtypes.FoundationDBCluster) bool {
+	for _, variable := range cluster.Spec.SubstitutionVariables {
+		if variable.NodeLabel != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getNodeLabelInitContainer provides an init container that waits until the
+// operator has copied the node labels for the substitution variables to the
+// annotations of the pod. The environment of the containers is resolved when
+// they start, so without this they would start before the node is known and
+// never see the values.
+func getNodeLabelInitContainer(cluster *fdbtypes.FoundationDBCluster, image string) corev1.Container {
+	annotationsFile := podAnnotationsMountPath + "/annotations"
+	checks := make([]string, 0, len(cluster.Spec.SubstitutionVariables))
+	for _, variable := range cluster.Spec.SubstitutionVariables {
+		if variable.NodeLabel == "" {
+			continue
+		}
+
+		// The downward API writes one key="value" line per annotation.
+		key := strings.ReplaceAll(variable.GetNodeLabelAnnotation(), ".", "\\.")
+		checks = append(checks, fmt.Sprintf("until grep -q '^%s=' %s; do sleep 1; done", key, annotationsFile))
+	}
+
+	readOnlyRootFilesystem := true
+	return corev1.Container{
+		Name:    NodeLabelInitContainerName,
+		Image:   image,
+		Command: []string{"sh", "-c"},
+		Args:    []string{strings.Join(checks, "; ")},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: podAnnotationsVolumeName, MountPath: podAnnotationsMountPath, ReadOnly: true},
+		},
+		SecurityContext: &corev1.SecurityContext{ReadOnlyRootFilesystem: &readOnlyRootFilesystem},
+	}
+}
+
+// getPodAnnotationsVolume provides the volume that exposes the annotations
+// of the pod to the init container for the node labels.
+func getPodAnnotationsVolume() corev1.Volume {
+	return corev1.Volume{Name: podAnnotationsVolumeName, VolumeSource: corev1.VolumeSource{
+		DownwardAPI: &corev1.DownwardAPIVolumeSource{Items: []corev1.DownwardAPIVolumeFile{
+			{Path: "annotations", FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.annotations"}},
+		}},
+	}}
+}
+
+// getTemplateEnvNames provides the names of the environment variables that
+// are defined for the containers in the pod templates and container overrides.
+//
+// The second return value indicates whether a container in the pod templates
+// loads variables through envFrom, in which case the names are incomplete.
+func getTemplateEnvNames(cluster *fdbtypes.FoundationDBCluster) (map[string]bool, bool) {
+	names := make(map[string]bool)
+	hasEnvFrom := false
+	addContainers := func(containers []corev1.Container) {
+		for _, container := range containers {
+			for _, envVar := range container.Env {
+				names[envVar.Name] = true
+			}
+			if len(container.EnvFrom) > 0 {
+				hasEnvFrom = true
+			}
+		}
+	}
+
+	for _, settings := range cluster.Spec.Processes {
+		if settings.PodTemplate == nil {
+			continue
+		}
+		addContainers(settings.PodTemplate.Spec.InitContainers)
+		addContainers(settings.PodTemplate.Spec.Containers)
+	}
+
+	for _, envVar := range cluster.Spec.MainContainer.Env {
+		names[envVar.Name] = true
+	}
+
+	for _, envVar := range cluster.Spec.SidecarContainer.Env {
+		names[envVar.Name] = true
+	}
+
+	return names, hasEnvFrom
+}
+
+// ValidateSubstitutionVariables checks that every variable that is referenced
+// in the custom parameters, the parameter overrides and the data hall is either provided by the
+// operator, declared in the cluster spec, or defined in the pod template.
+//
+// The variables can't be checked when a container in the pod templates loads
+// variables through envFrom, since their names are only known in the pod.
+func ValidateSubstitutionVariables(cluster *fdbtypes.FoundationDBCluster) error {
+	known, hasEnvFrom := getTemplateEnvNames(cluster)
+	if hasEnvFrom {
+		return nil
+	}
+
+	for _, name := range builtInSubstitutionVariables {
+		known[name] = true
+	}
+	for _, name := range cluster.Spec.SidecarVariables {
+		known[name] = true
+	}
+	for _, name := range GetSubstitutionVariableNames(cluster) {
+		known[name] = true
+	}
+
+	references := make([]string, 0)
+	for _, settings := range cluster.Spec.Processes {
+		for _, parameter := range settings.CustomParameters {
+			references = append(references, string(parameter))
+		}
+	}
+
+	for _, override := range cluster.Spec.ProcessGroupParameterOverrides {
+		for _, parameter := range override.CustomParameters {
+			references = append(references, string(parameter))
+		}
+	}
+
+	if strings.HasPrefix(cluster.Spec.DataHall, "$") {
+		references = append(references, cluster.Spec.DataHall)
+	}
+
+	unknown := make(map[string]bool)
+	for _, reference := range references {
+		for _, match := range variableReferencePattern.FindAllStringSubmatch(reference, -1) {
+			if !known[match[1]] {
+				unknown[match[1]] = true
+			}
+		}
+	}
+
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(unknown))
+	for name := range unknown {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return fmt.Errorf("cluster references unknown substitution variables: %s", strings.Join(names, ", "))
+}
+
+// GetMissingSubstitutionVariables provides the substitution variables from the
+// cluster spec that have no value in the substitutions from a pod.
+func GetMissingSubstitutionVariables(cluster *fdbtypes.FoundationDBCluster, substitutions map[string]string) []string {
+	var missing []string
+	for _, name := range GetSubstitutionVariableNames(cluster) {
+		if substitutions[name] == "" {
+			missing = append(missing, name)
+		}
+	}
+
+	return missing
+}
+
+// HasAnnotationSubstitutionVariables determines whether any substitution
+// variable in the cluster spec reads its value from a pod annotation, either
+// directly or through the annotation that holds a node label.
+func HasAnnotationSubstitutionVariables(cluster *fdbtypes.FoundationDBCluster) bool {
+	for _, variable := range cluster.Spec.SubstitutionVariables {
+		if variable.NodeLabel != "" || variable.PodAnnotation != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetStaleSubstitutionVariables provides the substitution variables that read
+// their value from a pod annotation, where the annotation on the pod holds a
+// different value than the substitutions from the pod.
+//
+// The environment of a container is resolved when the container starts, so
+// an annotation that is changed afterwards, like the annotation for a node
+// label that was changed on the node, only takes effect when the pod is
+// recreated.
+func GetStaleSubstitutionVariables(cluster *fdbtypes.FoundationDBCluster, pod *corev1.Pod, substitutions map[string]string) []string {
+	var stale []string
+	for _, variable := range cluster.Spec.SubstitutionVariables {
+		var value string
+		if variable.NodeLabel != "" {
+			value = pod.Annotations[variable.GetNodeLabelAnnotation()]
+		} else if variable.PodAnnotation != "" {
+			value = pod.Annotations[variable.PodAnnotation]
+		}
+
+		if value != "" && substitutions[variable.Name] != value {
+			stale = append(stale, variable.Name)
+		}
+	}
+
+	return stale
+}
+
+// buildCustomParameterArgument builds the argument for a custom parameter,
+// reading the substitution variables from the cluster spec from the
+// environment.
+func buildCustomParameterArgument(parameter string, variables map[string]bool) monitorapi.Argument {
+	matches := variableReferencePattern.FindAllStringSubmatchIndex(parameter, -1)
+	values := make([]monitorapi.Argument, 0, 2*len(matches)+1)
+	start := 0
+	for _, match := range matches {
+		name := parameter[match[2]:match[3]]
+		if !variables[name] {
+			continue
+		}
+
+		if match[0] > start {
+			values = append(values, monitorapi.Argument{Value: parameter[start:match[0]]})
+		}
+		values = append(values, monitorapi.Argument{ArgumentType: monitorapi.EnvironmentArgumentType, Source: name})
+		start = match[1]
+	}
+
+	if start == 0 {
+		return monitorapi.Argument{Value: parameter}
+	}
+
+	if start < len(parameter) {
+		values = append(values, monitorapi.Argument{Value: parameter[start:]})
+	}
+
+	return monitorapi.Argument{ArgumentType: monitorapi.ConcatenateArgumentType, Values: values}
+}
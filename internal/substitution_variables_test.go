@@ -0,0 +1,198 @@
+/*
+ * substitution_variables_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2021 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	monitorapi "github.com/apple/foundationdb/fdbkubernetesmonitor/api"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("substitution_variables", func() {
+	var cluster *fdbtypes.FoundationDBCluster
+
+	BeforeEach(func() {
+		cluster = CreateDefaultCluster()
+		err := NormalizeClusterSpec(cluster, DeprecationOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		cluster.Spec.SubstitutionVariables = []fdbtypes.SubstitutionVariable{
+			{Name: "FDB_RACK", NodeLabel: "topology.example.com/rack"},
+			{Name: "FDB_TEAM", PodAnnotation: "example.com/team"},
+			{Name: "FDB_NODE", FieldPath: "spec.nodeName"},
+			{Name: "FDB_CUSTOM"},
+		}
+	})
+
+	Describe("ValidateSubstitutionVariables", func() {
+		setCustomParameters := func(parameters ...fdbtypes.FoundationDBCustomParameter) {
+			settings := cluster.Spec.Processes[fdbtypes.ProcessClassGeneral]
+			settings.CustomParameters = parameters
+			cluster.Spec.Processes[fdbtypes.ProcessClassGeneral] = settings
+		}
+
+		It("should accept built-in and declared variables", func() {
+			setCustomParameters("locality_disk_id=$FDB_INSTANCE_ID", "locality_rack=$FDB_RACK")
+			Expect(ValidateSubstitutionVariables(cluster)).NotTo(HaveOccurred())
+		})
+
+		It("should reject unknown variables", func() {
+			setCustomParameters("locality_rack=$RACK", "locality_row=$ROW")
+			Expect(ValidateSubstitutionVariables(cluster)).To(MatchError("cluster references unknown substitution variables: RACK, ROW"))
+		})
+
+		It("should reject an unknown variable in the data hall", func() {
+			cluster.Spec.DataHall = "$DATA_HALL"
+			Expect(ValidateSubstitutionVariables(cluster)).To(HaveOccurred())
+		})
+
+		It("should accept variables from the pod template", func() {
+			cluster.Spec.DataHall = "$RACK"
+			settings := cluster.Spec.Processes[fdbtypes.ProcessClassGeneral]
+			settings.PodTemplate.Spec.Containers = append(settings.PodTemplate.Spec.Containers, corev1.Container{
+				Name: "foundationdb-kubernetes-sidecar",
+				Env:  []corev1.EnvVar{{Name: "RACK", Value: "rack-1"}},
+			})
+			cluster.Spec.Processes[fdbtypes.ProcessClassGeneral] = settings
+			Expect(ValidateSubstitutionVariables(cluster)).NotTo(HaveOccurred())
+		})
+
+		It("should accept unknown variables when the pod template loads variables through envFrom", func() {
+			setCustomParameters("locality_rack=$RACK")
+			settings := cluster.Spec.Processes[fdbtypes.ProcessClassGeneral]
+			settings.PodTemplate.Spec.Containers = append(settings.PodTemplate.Spec.Containers, corev1.Container{
+				Name: "foundationdb-kubernetes-sidecar",
+				EnvFrom: []corev1.EnvFromSource{{
+					ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "locality"}},
+				}},
+			})
+			cluster.Spec.Processes[fdbtypes.ProcessClassGeneral] = settings
+			Expect(ValidateSubstitutionVariables(cluster)).NotTo(HaveOccurred())
+		})
+
+		It("should not prevent building the config map with unknown variables", func() {
+			setCustomParameters("locality_rack=$RACK")
+			_, err := GetConfigMap(cluster)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Describe("GetPodSpec", func() {
+		It("should provide the variables to the sidecar", func() {
+			spec, err := GetPodSpec(cluster, fdbtypes.ProcessClassStorage, 1)
+			Expect(err).NotTo(HaveOccurred())
+
+			env := GetEnvVars(spec.Containers[1])
+			Expect(env["FDB_RACK"].ValueFrom.FieldRef.FieldPath).To(Equal("metadata.annotations['foundationdb.org/substitution-fdb-rack']"))
+			Expect(env["FDB_TEAM"].ValueFrom.FieldRef.FieldPath).To(Equal("metadata.annotations['example.com/team']"))
+			Expect(env["FDB_NODE"].ValueFrom.FieldRef.FieldPath).To(Equal("spec.nodeName"))
+			Expect(env).NotTo(HaveKey("FDB_CUSTOM"))
+			Expect(spec.Containers[1].Args).To(ContainElements("FDB_RACK", "FDB_TEAM", "FDB_NODE", "FDB_CUSTOM"))
+		})
+
+		It("should wait for the node labels before the containers start", func() {
+			spec, err := GetPodSpec(cluster, fdbtypes.ProcessClassStorage, 1)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(spec.InitContainers).To(HaveLen(2))
+			initContainer := spec.InitContainers[1]
+			Expect(initContainer.Name).To(Equal(NodeLabelInitContainerName))
+			Expect(initContainer.Image).To(Equal(spec.InitContainers[0].Image))
+			Expect(initContainer.Args).To(Equal([]string{
+				"until grep -q '^foundationdb\\.org/substitution-fdb-rack=' /var/pod-info/annotations; do sleep 1; done",
+			}))
+			Expect(initContainer.VolumeMounts).To(Equal([]corev1.VolumeMount{
+				{Name: "pod-annotations", MountPath: "/var/pod-info", ReadOnly: true},
+			}))
+			Expect(spec.Volumes).To(ContainElement(corev1.Volume{Name: "pod-annotations", VolumeSource: corev1.VolumeSource{
+				DownwardAPI: &corev1.DownwardAPIVolumeSource{Items: []corev1.DownwardAPIVolumeFile{
+					{Path: "annotations", FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.annotations"}},
+				}},
+			}}))
+		})
+
+		It("should not add the init container without node labels", func() {
+			cluster.Spec.SubstitutionVariables = []fdbtypes.SubstitutionVariable{
+				{Name: "FDB_TEAM", PodAnnotation: "example.com/team"},
+			}
+			spec, err := GetPodSpec(cluster, fdbtypes.ProcessClassStorage, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec.InitContainers).To(HaveLen(1))
+		})
+	})
+
+	Describe("GetNodeLabelAnnotations", func() {
+		It("should provide the annotations for the node labels", func() {
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"topology.example.com/rack": "rack-1"}}}
+			Expect(GetNodeLabelAnnotations(cluster, node)).To(Equal(map[string]string{
+				"foundationdb.org/substitution-fdb-rack": "rack-1",
+			}))
+		})
+
+		It("should provide an empty annotation for a missing label", func() {
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{}}}
+			Expect(GetNodeLabelAnnotations(cluster, node)).To(Equal(map[string]string{
+				"foundationdb.org/substitution-fdb-rack": "",
+			}))
+		})
+	})
+
+	Describe("GetMissingSubstitutionVariables", func() {
+		It("should report the variables without a value", func() {
+			substitutions := map[string]string{"FDB_RACK": "rack-1", "FDB_TEAM": "", "FDB_NODE": "node-1"}
+			Expect(GetMissingSubstitutionVariables(cluster, substitutions)).To(Equal([]string{"FDB_TEAM", "FDB_CUSTOM"}))
+		})
+	})
+
+	Describe("GetStaleSubstitutionVariables", func() {
+		It("should report the variables whose annotations changed after the containers started", func() {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+				"foundationdb.org/substitution-fdb-rack": "rack-1",
+				"example.com/team":                       "team-1",
+			}}}
+			substitutions := map[string]string{"FDB_TEAM": "team-1", "FDB_NODE": "node-1"}
+			Expect(GetStaleSubstitutionVariables(cluster, pod, substitutions)).To(Equal([]string{"FDB_RACK"}))
+		})
+
+		It("should not report variables without an annotation", func() {
+			pod := &corev1.Pod{}
+			Expect(GetStaleSubstitutionVariables(cluster, pod, map[string]string{})).To(BeEmpty())
+		})
+	})
+
+	Describe("buildCustomParameterArgument", func() {
+		It("should read the declared variables from the environment", func() {
+			argument := buildCustomParameterArgument("--locality_rack=$FDB_RACK-$FDB_INSTANCE_ID", map[string]bool{"FDB_RACK": true})
+			Expect(argument).To(Equal(monitorapi.Argument{ArgumentType: monitorapi.ConcatenateArgumentType, Values: []monitorapi.Argument{
+				{Value: "--locality_rack="},
+				{ArgumentType: monitorapi.EnvironmentArgumentType, Source: "FDB_RACK"},
+				{Value: "-$FDB_INSTANCE_ID"},
+			}}))
+		})
+
+		It("should keep arguments without declared variables", func() {
+			argument := buildCustomParameterArgument("--knob_test=1", map[string]bool{"FDB_RACK": true})
+			Expect(argument).To(Equal(monitorapi.Argument{Value: "--knob_test=1"}))
+		})
+	})
+})
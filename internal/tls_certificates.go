@@ -0,0 +1,113 @@
+/*
+ * tls_certificates.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2021 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TLSCertificateFiles provides the keys in the TLS secret that are copied
+// into the pods, in the order they are copied.
+var TLSCertificateFiles = []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey}
+
+// GetTLSCertificateStatus parses the certificate in a TLS secret and builds a
+// status that identifies the certificate and provides its expiry.
+func GetTLSCertificateStatus(secret *corev1.Secret) (*fdbtypes.TLSCertificateStatus, error) {
+	for _, key := range TLSCertificateFiles {
+		if len(secret.Data[key]) == 0 {
+			return nil, fmt.Errorf("secret %s/%s is missing the %s key", secret.Namespace, secret.Name, key)
+		}
+	}
+
+	block, _ := pem.Decode(secret.Data[corev1.TLSCertKey])
+	if block == nil {
+		return nil, fmt.Errorf("secret %s/%s does not contain a PEM encoded certificate", secret.Namespace, secret.Name)
+	}
+
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.New()
+	for _, key := range TLSCertificateFiles {
+		hash.Write(secret.Data[key])
+	}
+
+	return &fdbtypes.TLSCertificateStatus{
+		Hash:     hex.EncodeToString(hash.Sum(nil)),
+		NotAfter: &metav1.Time{Time: certificate.NotAfter},
+	}, nil
+}
+
+// configureTLSCertificates mounts the TLS certificates from the secret in the
+// cluster spec into the config map volume and points the containers to them.
+func configureTLSCertificates(cluster *fdbtypes.FoundationDBCluster, volumes []corev1.Volume, mainContainer *corev1.Container, sidecarContainer *corev1.Container, useUnifiedImages bool) {
+	if cluster.Spec.TLSCertificates == nil {
+		return
+	}
+
+	items := make([]corev1.KeyToPath, 0, len(TLSCertificateFiles))
+	for _, key := range TLSCertificateFiles {
+		items = append(items, corev1.KeyToPath{Key: key, Path: key})
+	}
+
+	for index, volume := range volumes {
+		if volume.Name != "config-map" || volume.ConfigMap == nil {
+			continue
+		}
+
+		volumes[index].VolumeSource = corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{
+					{ConfigMap: &corev1.ConfigMapProjection{
+						LocalObjectReference: volume.ConfigMap.LocalObjectReference,
+						Items:                volume.ConfigMap.Items,
+					}},
+					{Secret: &corev1.SecretProjection{
+						LocalObjectReference: corev1.LocalObjectReference{Name: cluster.Spec.TLSCertificates.GetSecretName(cluster.Name)},
+						Items:                items,
+					}},
+				},
+			},
+		}
+	}
+
+	extendEnv(mainContainer,
+		corev1.EnvVar{Name: "FDB_TLS_CERTIFICATE_FILE", Value: fmt.Sprintf("/var/dynamic-conf/%s", corev1.TLSCertKey)},
+		corev1.EnvVar{Name: "FDB_TLS_KEY_FILE", Value: fmt.Sprintf("/var/dynamic-conf/%s", corev1.TLSPrivateKeyKey)},
+	)
+
+	if !useUnifiedImages {
+		extendEnv(sidecarContainer,
+			corev1.EnvVar{Name: "FDB_TLS_CERTIFICATE_FILE", Value: fmt.Sprintf("/var/input-files/%s", corev1.TLSCertKey)},
+			corev1.EnvVar{Name: "FDB_TLS_KEY_FILE", Value: fmt.Sprintf("/var/input-files/%s", corev1.TLSPrivateKeyKey)},
+		)
+	}
+}
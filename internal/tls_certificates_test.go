@@ -0,0 +1,134 @@
+/*
+ * tls_certificates_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2021 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"time"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("tls_certificates", func() {
+	Describe("GetTLSCertificateStatus", func() {
+		var secret *corev1.Secret
+		var notAfter time.Time
+
+		BeforeEach(func() {
+			notAfter = time.Now().Add(24 * time.Hour).Truncate(time.Second)
+			data, err := CreateTestTLSCertificate(notAfter)
+			Expect(err).NotTo(HaveOccurred())
+			secret = &corev1.Secret{Data: data}
+		})
+
+		It("should provide the expiry of the certificate", func() {
+			status, err := GetTLSCertificateStatus(secret)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status.Hash).To(HaveLen(64))
+			Expect(status.NotAfter.Time.Equal(notAfter)).To(BeTrue())
+		})
+
+		It("should change the hash when the key changes", func() {
+			status, err := GetTLSCertificateStatus(secret)
+			Expect(err).NotTo(HaveOccurred())
+
+			secret.Data[corev1.TLSPrivateKeyKey] = []byte("other")
+			newStatus, err := GetTLSCertificateStatus(secret)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(newStatus.Hash).NotTo(Equal(status.Hash))
+		})
+
+		When("the secret is missing the private key", func() {
+			BeforeEach(func() {
+				delete(secret.Data, corev1.TLSPrivateKeyKey)
+			})
+
+			It("should return an error", func() {
+				_, err := GetTLSCertificateStatus(secret)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		When("the certificate is not PEM encoded", func() {
+			BeforeEach(func() {
+				secret.Data[corev1.TLSCertKey] = []byte("invalid")
+			})
+
+			It("should return an error", func() {
+				_, err := GetTLSCertificateStatus(secret)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("GetPodSpec", func() {
+		var cluster *fdbtypes.FoundationDBCluster
+		var spec *corev1.PodSpec
+
+		BeforeEach(func() {
+			cluster = CreateDefaultCluster()
+			err := NormalizeClusterSpec(cluster, DeprecationOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			cluster.Spec.TLSCertificates = &fdbtypes.TLSCertificateConfig{}
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			spec, err = GetPodSpec(cluster, fdbtypes.ProcessClassStorage, 1)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should project the TLS secret into the config map volume", func() {
+			var configMapVolume *corev1.Volume
+			for index := range spec.Volumes {
+				if spec.Volumes[index].Name == "config-map" {
+					configMapVolume = &spec.Volumes[index]
+				}
+			}
+			Expect(configMapVolume).NotTo(BeNil())
+			Expect(configMapVolume.ConfigMap).To(BeNil())
+			Expect(configMapVolume.Projected.Sources).To(HaveLen(2))
+			Expect(configMapVolume.Projected.Sources[0].ConfigMap.Name).To(Equal("operator-test-1-config"))
+			Expect(configMapVolume.Projected.Sources[1].Secret.Name).To(Equal("operator-test-1-tls"))
+			Expect(configMapVolume.Projected.Sources[1].Secret.Items).To(Equal([]corev1.KeyToPath{
+				{Key: "tls.crt", Path: "tls.crt"},
+				{Key: "tls.key", Path: "tls.key"},
+			}))
+		})
+
+		It("should point the containers to the certificates", func() {
+			mainEnv := GetEnvVars(spec.Containers[0])
+			Expect(mainEnv["FDB_TLS_CERTIFICATE_FILE"].Value).To(Equal("/var/dynamic-conf/tls.crt"))
+			Expect(mainEnv["FDB_TLS_KEY_FILE"].Value).To(Equal("/var/dynamic-conf/tls.key"))
+
+			sidecarEnv := GetEnvVars(spec.Containers[1])
+			Expect(sidecarEnv["FDB_TLS_CERTIFICATE_FILE"].Value).To(Equal("/var/input-files/tls.crt"))
+			Expect(sidecarEnv["FDB_TLS_KEY_FILE"].Value).To(Equal("/var/input-files/tls.key"))
+		})
+
+		It("should copy the certificates in the sidecar", func() {
+			Expect(spec.InitContainers[0].Args).To(ContainElements("tls.crt", "tls.key"))
+			Expect(spec.Containers[1].Args).To(ContainElements("tls.crt", "tls.key"))
+		})
+	})
+})
@@ -205,6 +205,11 @@ func analyzeCluster(cmd *cobra.Command, kubeClient client.Client, clusterName st
 	} else {
 		foundIssues = true
 		printStatement(cmd, "Cluster is not reconciled", true)
+
+		for _, entry := range cluster.Status.ReconciliationHistory {
+			statement := fmt.Sprintf("Reconciliation of generation %d was stopped by %s %d time(s), last at %s: %s", entry.Generation, entry.SubReconciler, entry.Count, entry.Timestamp.String(), getReconciliationHistoryReason(entry))
+			printStatement(cmd, statement, true)
+		}
 	}
 
 	// We could add here more fields from cluster.Status.Generations and check if they are present.
@@ -340,6 +345,20 @@ func analyzeCluster(cmd *cobra.Command, kubeClient client.Client, clusterName st
 	return nil
 }
 
+// getReconciliationHistoryReason returns the reason that is reported for a
+// reconciliation history entry.
+func getReconciliationHistoryReason(entry fdbtypes.ReconciliationHistoryEntry) string {
+	if entry.Error == "" {
+		return entry.Message
+	}
+
+	if entry.Message == "" || entry.Message == entry.Error {
+		return fmt.Sprintf("error: %s", entry.Error)
+	}
+
+	return fmt.Sprintf("%s, error: %s", entry.Message, entry.Error)
+}
+
 func filterDeletePods(replacements []string, killPods []corev1.Pod) []corev1.Pod {
 	res := make([]corev1.Pod, 0, len(killPods))
 
@@ -87,6 +87,11 @@ func getPodList(clusterName string, namespace string, status corev1.PodStatus, d
 
 }
 
+func getClusterWithReconciliationHistory(cluster *fdbtypes.FoundationDBCluster, history []fdbtypes.ReconciliationHistoryEntry) *fdbtypes.FoundationDBCluster {
+	cluster.Status.ReconciliationHistory = history
+	return cluster
+}
+
 var _ = Describe("[plugin] analyze cluster", func() {
 	clusterName := "test"
 	namespace := "test"
@@ -199,6 +204,36 @@ var _ = Describe("[plugin] analyze cluster", func() {
 ✔ Cluster is available
 ✔ Cluster is fully replicated
 ✔ ProcessGroups are all in ready condition
+✔ Pods are all running and available`,
+					AutoFix:   false,
+					HasErrors: true,
+				}),
+			Entry("Cluster is not reconciled and has a reconciliation history",
+				testCase{
+					cluster: getClusterWithReconciliationHistory(getCluster(clusterName, namespace, true, true, true, 1, []*fdbtypes.ProcessGroupStatus{}), []fdbtypes.ReconciliationHistoryEntry{
+						{
+							Timestamp:     metav1.Unix(1000, 0),
+							SubReconciler: "controllers.bounceProcesses",
+							Message:       "Kills are disabled",
+							Count:         3,
+						},
+						{
+							Timestamp:     metav1.Unix(2000, 0),
+							SubReconciler: "controllers.updateStatus",
+							Error:         "unavailable",
+							Count:         1,
+						},
+					}),
+					podList: getPodList(clusterName, namespace, corev1.PodStatus{
+						Phase: corev1.PodRunning,
+					}, nil),
+					ExpectedErrMsg: fmt.Sprintf(`✖ Cluster is not reconciled
+✖ Reconciliation of generation 0 was stopped by controllers.bounceProcesses 3 time(s), last at %s: Kills are disabled
+✖ Reconciliation of generation 0 was stopped by controllers.updateStatus 1 time(s), last at %s: error: unavailable`, metav1.Unix(1000, 0).String(), metav1.Unix(2000, 0).String()),
+					ExpectedStdouMsg: `Checking cluster: test/test
+✔ Cluster is available
+✔ Cluster is fully replicated
+✔ ProcessGroups are all in ready condition
 ✔ Pods are all running and available`,
 					AutoFix:   false,
 					HasErrors: true,
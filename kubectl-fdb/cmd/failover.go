@@ -0,0 +1,158 @@
+/*
+ * failover.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2021 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	ctx "context"
+	"fmt"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newFailOverCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newFDBOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:   "failover",
+		Short: "Fails over the cluster to another data center.",
+		Long:  "Fails over the cluster to another data center by setting the primary data center in the cluster spec.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			force, err := cmd.Root().Flags().GetBool("force")
+			if err != nil {
+				return err
+			}
+			target, err := cmd.Flags().GetString("to")
+			if err != nil {
+				return err
+			}
+			failBack, err := cmd.Flags().GetBool("fail-back")
+			if err != nil {
+				return err
+			}
+
+			scheme := runtime.NewScheme()
+			_ = clientgoscheme.AddToScheme(scheme)
+			_ = fdbtypes.AddToScheme(scheme)
+
+			config, err := o.configFlags.ToRESTConfig()
+			if err != nil {
+				return err
+			}
+
+			kubeClient, err := client.New(config, client.Options{Scheme: scheme})
+			if err != nil {
+				return err
+			}
+
+			namespace, err := getNamespace(*o.configFlags.Namespace)
+			if err != nil {
+				return err
+			}
+
+			return failOverCluster(kubeClient, args[0], namespace, target, failBack, force)
+		},
+		Example: `
+The operator will wait until the new primary data center has caught up, change the region priorities
+and wait for the database to recover. The progress is reported in the failOver field of the cluster status.
+
+# Fail over the cluster c1 to the other data center
+kubectl fdb failover c1
+
+# Fail over the cluster c1 to the data center dc2
+kubectl fdb failover c1 --to dc2
+
+# Fail back the cluster c1 to the priorities from the database configuration
+kubectl fdb failover c1 --fail-back
+`,
+	}
+	cmd.SetOut(o.Out)
+	cmd.SetErr(o.ErrOut)
+	cmd.SetIn(o.In)
+
+	cmd.Flags().String("to", "", "the data center that should become the primary. Defaults to the other main data center.")
+	cmd.Flags().Bool("fail-back", false, "defines if the cluster should fail back to the priorities from the database configuration.")
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// failOverCluster sets the primary data center of the cluster to trigger a
+// fail over or a fail back.
+func failOverCluster(kubeClient client.Client, clusterName string, namespace string, target string, failBack bool, force bool) error {
+	cluster, err := loadCluster(kubeClient, namespace, clusterName)
+	if err != nil {
+		return err
+	}
+
+	if failBack && target != "" {
+		return fmt.Errorf("it's not allowed to use --to and --fail-back together")
+	}
+
+	configuration := cluster.Spec.DatabaseConfiguration
+	currentPrimary := configuration.WithPrimaryDataCenter(cluster.Spec.PrimaryDataCenter).GetPrimaryDataCenter()
+
+	if failBack {
+		target = configuration.GetPrimaryDataCenter()
+	} else if target == "" {
+		for _, region := range configuration.Regions {
+			for _, dataCenter := range region.DataCenters {
+				if dataCenter.Satellite != 0 || dataCenter.ID == currentPrimary {
+					continue
+				}
+
+				if target != "" {
+					return fmt.Errorf("cluster %s/%s has more than two main data centers, please specify the target with --to", namespace, clusterName)
+				}
+				target = dataCenter.ID
+			}
+		}
+	}
+
+	if target == "" || !configuration.HasMainDataCenter(target) {
+		return fmt.Errorf("cluster %s/%s has no main data center to fail over to", namespace, clusterName)
+	}
+
+	if target == currentPrimary {
+		return fmt.Errorf("data center %s is already the primary of cluster %s/%s", target, namespace, clusterName)
+	}
+
+	if !force {
+		confirmed := confirmAction(fmt.Sprintf("Fail over cluster %s/%s from %s to %s", namespace, clusterName, currentPrimary, target))
+		if !confirmed {
+			return fmt.Errorf("user aborted the fail over")
+		}
+	}
+
+	patch := client.MergeFrom(cluster.DeepCopy())
+	if failBack {
+		cluster.Spec.PrimaryDataCenter = ""
+	} else {
+		cluster.Spec.PrimaryDataCenter = target
+	}
+
+	return kubeClient.Patch(ctx.TODO(), cluster, patch)
+}
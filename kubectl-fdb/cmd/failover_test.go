@@ -0,0 +1,106 @@
+/*
+ * failover_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2021 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("[plugin] failover command", func() {
+	When("failing over a multi region cluster", func() {
+		var cluster *fdbtypes.FoundationDBCluster
+		var kubeClient client.Client
+
+		BeforeEach(func() {
+			cluster = &fdbtypes.FoundationDBCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "test",
+				},
+				Spec: fdbtypes.FoundationDBClusterSpec{
+					DatabaseConfiguration: fdbtypes.DatabaseConfiguration{
+						Regions: []fdbtypes.Region{
+							{
+								DataCenters: []fdbtypes.DataCenter{
+									{ID: "primary", Priority: 1},
+									{ID: "primary-sat", Priority: 1, Satellite: 1},
+								},
+							},
+							{
+								DataCenters: []fdbtypes.DataCenter{
+									{ID: "remote", Priority: 0},
+									{ID: "remote-sat", Priority: 1, Satellite: 1},
+								},
+							},
+						},
+					},
+				},
+			}
+
+			scheme := runtime.NewScheme()
+			_ = clientgoscheme.AddToScheme(scheme)
+			_ = fdbtypes.AddToScheme(scheme)
+			kubeClient = fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(cluster).Build()
+		})
+
+		getPrimaryDataCenter := func() string {
+			var result fdbtypes.FoundationDBCluster
+			Expect(kubeClient.Get(context.Background(), client.ObjectKey{Namespace: "test", Name: "test"}, &result)).NotTo(HaveOccurred())
+			return result.Spec.PrimaryDataCenter
+		}
+
+		It("should fail over to the other main data center", func() {
+			Expect(failOverCluster(kubeClient, "test", "test", "", false, true)).NotTo(HaveOccurred())
+			Expect(getPrimaryDataCenter()).To(Equal("remote"))
+		})
+
+		It("should fail over to the given data center", func() {
+			Expect(failOverCluster(kubeClient, "test", "test", "remote", false, true)).NotTo(HaveOccurred())
+			Expect(getPrimaryDataCenter()).To(Equal("remote"))
+		})
+
+		It("should not fail over to a satellite", func() {
+			Expect(failOverCluster(kubeClient, "test", "test", "remote-sat", false, true)).To(HaveOccurred())
+			Expect(getPrimaryDataCenter()).To(Equal(""))
+		})
+
+		It("should not fail over to the current primary", func() {
+			Expect(failOverCluster(kubeClient, "test", "test", "primary", false, true)).To(HaveOccurred())
+		})
+
+		It("should fail back after a fail over", func() {
+			Expect(failOverCluster(kubeClient, "test", "test", "", false, true)).NotTo(HaveOccurred())
+			Expect(getPrimaryDataCenter()).To(Equal("remote"))
+
+			Expect(failOverCluster(kubeClient, "test", "test", "", true, true)).NotTo(HaveOccurred())
+			Expect(getPrimaryDataCenter()).To(Equal(""))
+		})
+	})
+})
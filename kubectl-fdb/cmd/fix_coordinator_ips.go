@@ -185,6 +185,13 @@ func updateIPsInConnectionString(cluster *fdbtypes.FoundationDBCluster) error {
 		if err != nil {
 			return err
 		}
+
+		// Coordinators with DNS names don't change when the pod IPs change.
+		if coordinatorAddress.IsDNSAddress() {
+			newCoordinators[coordinatorIndex] = coordinator
+			continue
+		}
+
 		for _, processGroup := range cluster.Status.ProcessGroups {
 			for _, address := range processGroup.Addresses {
 				if address == coordinatorAddress.IPAddress.String() {
@@ -232,5 +232,17 @@ var _ = Describe("[plugin] fix-coordinator-ips command", func() {
 				},
 			),
 		)
+
+		When("the coordinators use DNS names", func() {
+			It("should not change the DNS names", func() {
+				connectionString := "test:asdfkjh@test-storage-1.test.test.svc.cluster.local:4501,127.0.0.2:4501,127.0.0.3:4501"
+				cluster.Status.ConnectionString = connectionString
+				cluster.Status.ProcessGroups[0].Addresses = append(cluster.Status.ProcessGroups[0].Addresses, "127.0.1.1")
+				cluster.Status.ProcessGroups[1].Addresses = append(cluster.Status.ProcessGroups[1].Addresses, "127.0.1.2")
+
+				Expect(updateIPsInConnectionString(&cluster)).NotTo(HaveOccurred())
+				Expect(cluster.Status.ConnectionString).To(Equal("test:asdfkjh@test-storage-1.test.test.svc.cluster.local:4501,127.0.1.2:4501,127.0.0.3:4501"))
+			})
+		})
 	})
 })
@@ -82,6 +82,8 @@ func NewRootCmd(streams genericclioptions.IOStreams) *cobra.Command {
 		newDeprecationCmd(streams),
 		newFixCoordinatorIPsCmd(streams),
 		newGetCmd(streams),
+		newFailOverCmd(streams),
+		newUpgradeCmd(streams),
 	)
 
 	return cmd
@@ -0,0 +1,614 @@
+/*
+ * upgrade.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2021 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	ctx "context"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newUpgradeCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newFDBOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrades a cluster to a new version.",
+		Long:  "Runs the pre-flight checks, changes the version of the cluster and follows the progress until all processes report the new version.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			force, err := cmd.Root().Flags().GetBool("force")
+			if err != nil {
+				return err
+			}
+			version, err := cmd.Flags().GetString("version")
+			if err != nil {
+				return err
+			}
+			abort, err := cmd.Flags().GetBool("abort")
+			if err != nil {
+				return err
+			}
+			skipChecks, err := cmd.Flags().GetBool("skip-checks")
+			if err != nil {
+				return err
+			}
+			checkTimeout, err := cmd.Flags().GetDuration("check-timeout")
+			if err != nil {
+				return err
+			}
+			timeout, err := cmd.Flags().GetDuration("timeout")
+			if err != nil {
+				return err
+			}
+			wait, err := cmd.Flags().GetBool("wait")
+			if err != nil {
+				return err
+			}
+
+			scheme := runtime.NewScheme()
+			_ = clientgoscheme.AddToScheme(scheme)
+			_ = fdbtypes.AddToScheme(scheme)
+
+			config, err := o.configFlags.ToRESTConfig()
+			if err != nil {
+				return err
+			}
+
+			kubeClient, err := client.New(config, client.Options{Scheme: scheme})
+			if err != nil {
+				return err
+			}
+
+			namespace, err := getNamespace(*o.configFlags.Namespace)
+			if err != nil {
+				return err
+			}
+
+			if abort {
+				if version != "" {
+					return fmt.Errorf("it's not allowed to use --version and --abort together")
+				}
+
+				return abortUpgrade(kubeClient, args[0], namespace, force)
+			}
+
+			if version == "" {
+				return fmt.Errorf("the --version flag is required to start an upgrade")
+			}
+
+			watcher, err := newUpgradeWatcher(kubeClient, args[0], namespace, version)
+			if err != nil {
+				return err
+			}
+
+			err = startUpgrade(kubeClient, args[0], namespace, version, skipChecks, checkTimeout, force, cmd.OutOrStdout())
+			if err != nil {
+				return err
+			}
+
+			if !wait {
+				return nil
+			}
+
+			return watcher.watch(timeout, 5*time.Second, cmd.OutOrStdout())
+		},
+		Example: `
+The upgrade runs the pre-flight checks, changes the version in the cluster spec and prints the
+progress until all processes report the new version. The upgrade can be aborted as long as the
+operator hasn't bounced the processes.
+
+# Upgrade the cluster c1 to 6.3.13
+kubectl fdb upgrade c1 --version 6.3.13
+
+# Upgrade the cluster c1 to 6.3.13 without waiting for the upgrade to finish
+kubectl fdb upgrade c1 --version 6.3.13 --wait=false
+
+# Abort the upgrade of the cluster c1
+kubectl fdb upgrade c1 --abort
+
+# Run the pre-flight checks for an upgrade of the cluster c1 to 6.3.13
+kubectl fdb upgrade check c1 --version 6.3.13
+`,
+	}
+
+	cmd.SetOut(o.Out)
+	cmd.SetErr(o.ErrOut)
+	cmd.SetIn(o.In)
+
+	cmd.Flags().String("version", "", "the version of FoundationDB the cluster should be upgraded to.")
+	cmd.Flags().Bool("abort", false, "aborts a pending upgrade by changing the version back to the running version.")
+	cmd.Flags().Bool("skip-checks", false, "defines if the pre-flight checks should be skipped.")
+	cmd.Flags().Duration("check-timeout", 2*time.Minute, "the time to wait for the operator to run the pre-flight checks.")
+	cmd.Flags().Duration("timeout", 0, "the time to wait for the upgrade to finish. A value of 0 waits without a timeout.")
+	cmd.Flags().Bool("wait", true, "defines if the progress of the upgrade should be printed until all processes report the new version.")
+	cmd.AddCommand(newUpgradeCheckCmd(streams))
+
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func newUpgradeCheckCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newFDBOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Runs the pre-flight checks for an upgrade of a cluster.",
+		Long:  "Runs the pre-flight checks for an upgrade of a cluster and prints a report of the problems that would block the upgrade.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			version, err := cmd.Flags().GetString("version")
+			if err != nil {
+				return err
+			}
+			timeout, err := cmd.Flags().GetDuration("timeout")
+			if err != nil {
+				return err
+			}
+
+			scheme := runtime.NewScheme()
+			_ = clientgoscheme.AddToScheme(scheme)
+			_ = fdbtypes.AddToScheme(scheme)
+
+			config, err := o.configFlags.ToRESTConfig()
+			if err != nil {
+				return err
+			}
+
+			kubeClient, err := client.New(config, client.Options{Scheme: scheme})
+			if err != nil {
+				return err
+			}
+
+			namespace, err := getNamespace(*o.configFlags.Namespace)
+			if err != nil {
+				return err
+			}
+
+			result, err := runUpgradeCheck(kubeClient, args[0], namespace, version, timeout)
+			if err != nil {
+				return err
+			}
+
+			cmd.Println(formatUpgradeCheck(result))
+			if !result.Passed {
+				return fmt.Errorf("the upgrade of cluster %s/%s to version %s is blocked", namespace, args[0], version)
+			}
+
+			return nil
+		},
+		Example: `
+The operator runs the checks against the live cluster and reports the result in the upgradeCheck field
+of the cluster status. The checks cover the connected clients, the images for the new version and the
+support of the sidecars.
+
+# Run the pre-flight checks for an upgrade of the cluster c1 to 6.3.13
+kubectl fdb upgrade check c1 --version 6.3.13
+`,
+	}
+	cmd.SetOut(o.Out)
+	cmd.SetErr(o.ErrOut)
+	cmd.SetIn(o.In)
+
+	cmd.Flags().String("version", "", "the version of FoundationDB the cluster should be upgraded to.")
+	cmd.Flags().Duration("timeout", 2*time.Minute, "the time to wait for the operator to run the checks.")
+	err := cmd.MarkFlagRequired("version")
+	if err != nil {
+		log.Fatal(err)
+	}
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// runUpgradeCheck requests the pre-flight checks for the given version and
+// waits until the operator has reported the result.
+func runUpgradeCheck(kubeClient client.Client, clusterName string, namespace string, version string, timeout time.Duration) (*fdbtypes.UpgradeCheckStatus, error) {
+	_, err := fdbtypes.ParseFdbVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	requestID, err := requestUpgradeCheck(kubeClient, clusterName, namespace, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return waitForUpgradeCheck(kubeClient, clusterName, namespace, requestID, timeout)
+}
+
+// requestUpgradeCheck sets the annotations that tell the operator to run the
+// pre-flight checks for the given version. This returns the ID of the request,
+// which the operator reports with the result, so an older result for the same
+// version is not mistaken for the result of this request.
+func requestUpgradeCheck(kubeClient client.Client, clusterName string, namespace string, version string) (string, error) {
+	cluster, err := loadCluster(kubeClient, namespace, clusterName)
+	if err != nil {
+		return "", err
+	}
+
+	requestID := strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	patch := client.MergeFrom(cluster.DeepCopy())
+	if cluster.Annotations == nil {
+		cluster.Annotations = make(map[string]string)
+	}
+	cluster.Annotations[fdbtypes.UpgradeCheckVersionAnnotation] = version
+	cluster.Annotations[fdbtypes.UpgradeCheckRequestAnnotation] = requestID
+
+	return requestID, kubeClient.Patch(ctx.TODO(), cluster, patch)
+}
+
+// waitForUpgradeCheck waits until the cluster status contains the result of
+// the pre-flight checks for the given request.
+func waitForUpgradeCheck(kubeClient client.Client, clusterName string, namespace string, requestID string, timeout time.Duration) (*fdbtypes.UpgradeCheckStatus, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		cluster, err := loadCluster(kubeClient, namespace, clusterName)
+		if err != nil {
+			return nil, err
+		}
+
+		if cluster.Status.UpgradeCheck != nil && cluster.Status.UpgradeCheck.RequestID == requestID {
+			return cluster.Status.UpgradeCheck, nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for the upgrade check of cluster %s/%s", namespace, clusterName)
+		}
+
+		time.Sleep(1 * time.Second)
+	}
+}
+
+// formatUpgradeCheck builds a human-readable report from the result of the
+// pre-flight checks.
+func formatUpgradeCheck(result *fdbtypes.UpgradeCheckStatus) string {
+	var sb strings.Builder
+
+	if result.Passed {
+		sb.WriteString(fmt.Sprintf("Upgrade to version %s: passed\n", result.Version))
+	} else {
+		sb.WriteString(fmt.Sprintf("Upgrade to version %s: blocked\n", result.Version))
+	}
+
+	if result.Timestamp != nil {
+		sb.WriteString(fmt.Sprintf("Checked at: %s\n", result.Timestamp.UTC().Format(time.RFC3339)))
+	}
+
+	sb.WriteString(fmt.Sprintf("Images resolved: %t\n", result.ImagesResolved))
+	for _, image := range result.Images {
+		sb.WriteString(fmt.Sprintf("\t%s\n", image))
+	}
+
+	sb.WriteString(fmt.Sprintf("Sidecars supported: %t\n", result.SidecarsSupported))
+
+	sb.WriteString(fmt.Sprintf("Unsupported clients: %d\n", len(result.UnsupportedClients)))
+	for _, client := range result.UnsupportedClients {
+		if client.Version == "" {
+			sb.WriteString(fmt.Sprintf("\t%s\n", client.Description()))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\t%s version %s\n", client.Description(), client.Version))
+	}
+
+	if len(result.Errors) > 0 {
+		sb.WriteString("Errors:\n")
+		for _, message := range result.Errors {
+			sb.WriteString(fmt.Sprintf("\t%s\n", message))
+		}
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// startUpgrade runs the pre-flight checks and changes the version of the
+// cluster.
+func startUpgrade(kubeClient client.Client, clusterName string, namespace string, version string, skipChecks bool, checkTimeout time.Duration, force bool, out io.Writer) error {
+	cluster, err := loadCluster(kubeClient, namespace, clusterName)
+	if err != nil {
+		return err
+	}
+
+	_, err = fdbtypes.ParseFdbVersion(version)
+	if err != nil {
+		return err
+	}
+
+	if cluster.Spec.Version == version {
+		return fmt.Errorf("cluster %s/%s is already configured with version %s", namespace, clusterName, version)
+	}
+
+	if cluster.Status.RunningVersion != cluster.Spec.Version {
+		return fmt.Errorf("cluster %s/%s has a pending upgrade to version %s", namespace, clusterName, cluster.Spec.Version)
+	}
+
+	if !skipChecks {
+		result, err := runUpgradeCheck(kubeClient, clusterName, namespace, version, checkTimeout)
+		if err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintln(out, formatUpgradeCheck(result))
+		if !result.Passed {
+			return fmt.Errorf("the upgrade of cluster %s/%s to version %s is blocked", namespace, clusterName, version)
+		}
+
+		cluster, err = loadCluster(kubeClient, namespace, clusterName)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !force {
+		confirmed := confirmAction(fmt.Sprintf("Upgrade cluster %s/%s from %s to %s", namespace, clusterName, cluster.Spec.Version, version))
+		if !confirmed {
+			return fmt.Errorf("user aborted the upgrade")
+		}
+	}
+
+	patch := client.MergeFrom(cluster.DeepCopy())
+	cluster.Spec.Version = version
+
+	return kubeClient.Patch(ctx.TODO(), cluster, patch)
+}
+
+// abortUpgrade changes the version of the cluster back to the running version
+// if the operator hasn't bounced any processes into the new version.
+func abortUpgrade(kubeClient client.Client, clusterName string, namespace string, force bool) error {
+	cluster, err := loadCluster(kubeClient, namespace, clusterName)
+	if err != nil {
+		return err
+	}
+
+	if cluster.Status.RunningVersion == "" || cluster.Status.RunningVersion == cluster.Spec.Version {
+		return fmt.Errorf("cluster %s/%s has no pending upgrade", namespace, clusterName)
+	}
+
+	if cluster.Status.UpgradeProgress != nil && cluster.Status.UpgradeProgress.ProcessVersions[cluster.Spec.Version] > 0 {
+		return fmt.Errorf("cluster %s/%s has processes running version %s, the upgrade can't be aborted", namespace, clusterName, cluster.Spec.Version)
+	}
+
+	if !force {
+		confirmed := confirmAction(fmt.Sprintf("Abort the upgrade of cluster %s/%s to %s", namespace, clusterName, cluster.Spec.Version))
+		if !confirmed {
+			return fmt.Errorf("user declined to abort the upgrade")
+		}
+	}
+
+	patch := client.MergeFrom(cluster.DeepCopy())
+	cluster.Spec.Version = cluster.Status.RunningVersion
+
+	return kubeClient.Patch(ctx.TODO(), cluster, patch)
+}
+
+// upgradeEventReasons provides the event reasons that are shown in the
+// progress of an upgrade.
+var upgradeEventReasons = map[string]bool{
+	"BouncingProcesses": true,
+	"NeedsBounce":       true,
+	"UpgradeRequeued":   true,
+	"UnsupportedClient": true,
+}
+
+// upgradeWatcher follows the progress of an upgrade.
+type upgradeWatcher struct {
+	kubeClient   client.Client
+	clusterName  string
+	namespace    string
+	version      string
+	seenEvents   map[string]int32
+	lastProgress string
+}
+
+// newUpgradeWatcher creates a watcher for the upgrade of a cluster. Events that
+// exist when the watcher is created will not be printed.
+func newUpgradeWatcher(kubeClient client.Client, clusterName string, namespace string, version string) (*upgradeWatcher, error) {
+	watcher := &upgradeWatcher{
+		kubeClient:  kubeClient,
+		clusterName: clusterName,
+		namespace:   namespace,
+		version:     version,
+		seenEvents:  make(map[string]int32),
+	}
+
+	_, err := watcher.getNewEvents()
+	if err != nil {
+		return nil, err
+	}
+
+	return watcher, nil
+}
+
+// watch prints the progress of the upgrade until all processes report the new
+// version.
+func (watcher *upgradeWatcher) watch(timeout time.Duration, interval time.Duration, out io.Writer) error {
+	start := time.Now()
+	for {
+		done, err := watcher.printProgress(out)
+		if err != nil {
+			return err
+		}
+
+		if done {
+			return nil
+		}
+
+		if timeout > 0 && time.Since(start) > timeout {
+			return fmt.Errorf("timed out waiting for the upgrade of cluster %s/%s to version %s", watcher.namespace, watcher.clusterName, watcher.version)
+		}
+
+		time.Sleep(interval)
+	}
+}
+
+// printProgress prints the progress of the upgrade if it has changed since the
+// last call and any new events. This returns true if all processes report the
+// new version.
+func (watcher *upgradeWatcher) printProgress(out io.Writer) (bool, error) {
+	cluster, err := loadCluster(watcher.kubeClient, watcher.namespace, watcher.clusterName)
+	if err != nil {
+		return false, err
+	}
+
+	if cluster.Spec.Version != watcher.version {
+		return false, fmt.Errorf("cluster %s/%s is now configured with version %s, the upgrade was aborted", watcher.namespace, watcher.clusterName, cluster.Spec.Version)
+	}
+
+	progress := formatUpgradeProgress(cluster)
+	if progress != watcher.lastProgress {
+		_, _ = fmt.Fprintf(out, "[%s]\n%s\n", time.Now().Format(time.RFC3339), progress)
+		watcher.lastProgress = progress
+	}
+
+	events, err := watcher.getNewEvents()
+	if err != nil {
+		return false, err
+	}
+	for _, event := range events {
+		_, _ = fmt.Fprintf(out, "Event %s: %s\n", event.Reason, event.Message)
+	}
+
+	done := cluster.Status.RunningVersion == watcher.version && cluster.Status.UpgradeProgress == nil
+	if done {
+		_, _ = fmt.Fprintf(out, "All processes of cluster %s/%s report version %s\n", watcher.namespace, watcher.clusterName, watcher.version)
+	}
+
+	return done, nil
+}
+
+// getNewEvents returns the upgrade related events for the cluster that were
+// not returned before.
+func (watcher *upgradeWatcher) getNewEvents() ([]corev1.Event, error) {
+	events := &corev1.EventList{}
+	err := watcher.kubeClient.List(ctx.TODO(), events, client.InNamespace(watcher.namespace))
+	if err != nil {
+		return nil, err
+	}
+
+	newEvents := make([]corev1.Event, 0)
+	for _, event := range events.Items {
+		if event.InvolvedObject.Kind != "FoundationDBCluster" || event.InvolvedObject.Name != watcher.clusterName {
+			continue
+		}
+
+		if !upgradeEventReasons[event.Reason] {
+			continue
+		}
+
+		count, seen := watcher.seenEvents[event.Name]
+		if seen && count == event.Count {
+			continue
+		}
+
+		watcher.seenEvents[event.Name] = event.Count
+		newEvents = append(newEvents, event)
+	}
+
+	sort.SliceStable(newEvents, func(i, j int) bool {
+		return newEvents[i].LastTimestamp.Before(&newEvents[j].LastTimestamp)
+	})
+
+	return newEvents, nil
+}
+
+// formatUpgradeProgress builds a human-readable view of the progress of an
+// upgrade.
+func formatUpgradeProgress(cluster *fdbtypes.FoundationDBCluster) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("Version: %s, running version: %s\n", cluster.Spec.Version, cluster.Status.RunningVersion))
+	sb.WriteString(fmt.Sprintf("Reconciled generation: %d of %d\n", cluster.Status.Generations.Reconciled, cluster.ObjectMeta.Generation))
+
+	pendingActions := getPendingGenerationActions(cluster.Status.Generations)
+	if len(pendingActions) > 0 {
+		sb.WriteString(fmt.Sprintf("Pending actions: %s\n", strings.Join(pendingActions, ", ")))
+	}
+
+	progress := cluster.Status.UpgradeProgress
+	if progress != nil {
+		versions := make([]string, 0, len(progress.ProcessVersions))
+		for version := range progress.ProcessVersions {
+			versions = append(versions, version)
+		}
+		sort.Strings(versions)
+
+		processCounts := make([]string, 0, len(versions))
+		for _, version := range versions {
+			processCounts = append(processCounts, fmt.Sprintf("%s: %d", version, progress.ProcessVersions[version]))
+		}
+		sb.WriteString(fmt.Sprintf("Process versions: %s\n", strings.Join(processCounts, ", ")))
+
+		if cluster.ShouldUseLocks() {
+			sb.WriteString(fmt.Sprintf("Process groups ready for the upgrade: %d of %d\n", len(progress.PendingUpgrades), len(cluster.Status.ProcessGroups)))
+		}
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// getPendingGenerationActions returns the names of the actions from the
+// generation status that are pending.
+func getPendingGenerationActions(generations fdbtypes.ClusterGenerationStatus) []string {
+	actions := map[string]int64{
+		"needsConfigurationChange":      generations.NeedsConfigurationChange,
+		"needsCoordinatorChange":        generations.NeedsCoordinatorChange,
+		"needsBounce":                   generations.NeedsBounce,
+		"needsPodDeletion":              generations.NeedsPodDeletion,
+		"needsShrink":                   generations.NeedsShrink,
+		"needsGrow":                     generations.NeedsGrow,
+		"needsMonitorConfUpdate":        generations.NeedsMonitorConfUpdate,
+		"missingDatabaseStatus":         generations.DatabaseUnavailable,
+		"hasExtraListeners":             generations.HasExtraListeners,
+		"needsServiceUpdate":            generations.NeedsServiceUpdate,
+		"needsBackupAgentUpdate":        generations.NeedsBackupAgentUpdate,
+		"hasPendingRemoval":             generations.HasPendingRemoval,
+		"hasFailingPods":                generations.HasFailingPods,
+		"hasUnhealthyProcess":           generations.HasUnhealthyProcess,
+		"needsLockConfigurationChanges": generations.NeedsLockConfigurationChanges,
+		"needsRuntimeKnobUpdate":        generations.NeedsRuntimeKnobUpdate,
+	}
+
+	pendingActions := make([]string, 0)
+	for action, generation := range actions {
+		if generation == 0 {
+			continue
+		}
+		pendingActions = append(pendingActions, action)
+	}
+	sort.Strings(pendingActions)
+
+	return pendingActions
+}
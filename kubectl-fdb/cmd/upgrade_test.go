@@ -0,0 +1,336 @@
+/*
+ * upgrade_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2021 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("[plugin] upgrade command", func() {
+	var cluster *fdbtypes.FoundationDBCluster
+	var kubeClient client.Client
+
+	BeforeEach(func() {
+		cluster = &fdbtypes.FoundationDBCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "test",
+			},
+			Spec: fdbtypes.FoundationDBClusterSpec{
+				Version: "6.2.20",
+			},
+			Status: fdbtypes.FoundationDBClusterStatus{
+				RunningVersion: "6.2.20",
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		scheme := runtime.NewScheme()
+		_ = clientgoscheme.AddToScheme(scheme)
+		_ = fdbtypes.AddToScheme(scheme)
+		kubeClient = fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(cluster).Build()
+	})
+
+	// reportUpgradeCheck acts like the operator and reports the result for
+	// the next request for the upgrade checks.
+	reportUpgradeCheck := func(result fdbtypes.UpgradeCheckStatus) {
+		go func() {
+			defer GinkgoRecover()
+			for attempt := 0; attempt < 100; attempt++ {
+				current, err := loadCluster(kubeClient, "test", "test")
+				Expect(err).NotTo(HaveOccurred())
+				requestID := current.Annotations[fdbtypes.UpgradeCheckRequestAnnotation]
+				if requestID != "" {
+					result.RequestID = requestID
+					current.Status.UpgradeCheck = &result
+					Expect(kubeClient.Status().Update(context.TODO(), current)).NotTo(HaveOccurred())
+					return
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		}()
+	}
+
+	When("running the upgrade check", func() {
+		When("the operator has not reported a result", func() {
+			It("should set the annotation and time out", func() {
+				_, err := runUpgradeCheck(kubeClient, "test", "test", "7.0.0", 0)
+				Expect(err).To(HaveOccurred())
+
+				result, err := loadCluster(kubeClient, "test", "test")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Annotations).To(HaveKeyWithValue(fdbtypes.UpgradeCheckVersionAnnotation, "7.0.0"))
+				Expect(result.Annotations).To(HaveKey(fdbtypes.UpgradeCheckRequestAnnotation))
+			})
+		})
+
+		When("the operator has reported a result for an earlier request", func() {
+			BeforeEach(func() {
+				cluster.Status.UpgradeCheck = &fdbtypes.UpgradeCheckStatus{
+					Version:   "7.0.0",
+					RequestID: "earlier",
+					Passed:    true,
+				}
+			})
+
+			It("should not return the earlier result", func() {
+				_, err := runUpgradeCheck(kubeClient, "test", "test", "7.0.0", 0)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		When("the operator reports a result", func() {
+			JustBeforeEach(func() {
+				reportUpgradeCheck(fdbtypes.UpgradeCheckStatus{Version: "7.0.0", Passed: true})
+			})
+
+			It("should return the result", func() {
+				result, err := runUpgradeCheck(kubeClient, "test", "test", "7.0.0", time.Minute)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Passed).To(BeTrue())
+			})
+		})
+
+		When("the version is invalid", func() {
+			It("should return an error", func() {
+				_, err := runUpgradeCheck(kubeClient, "test", "test", "invalid", time.Minute)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	When("formatting the upgrade check", func() {
+		It("should list the blocking clients", func() {
+			result := &fdbtypes.UpgradeCheckStatus{
+				Version:           "7.0.0",
+				Images:            []string{"foundationdb/foundationdb:7.0.0"},
+				ImagesResolved:    true,
+				SidecarsSupported: true,
+				UnsupportedClients: []fdbtypes.UpgradeCheckClient{
+					{
+						FoundationDBStatusConnectedClient: fdbtypes.FoundationDBStatusConnectedClient{
+							Address:  "127.0.0.3:85891",
+							LogGroup: "app",
+						},
+						Version: "6.2.20",
+					},
+				},
+			}
+
+			Expect(formatUpgradeCheck(result)).To(Equal(`Upgrade to version 7.0.0: blocked
+Images resolved: true
+	foundationdb/foundationdb:7.0.0
+Sidecars supported: true
+Unsupported clients: 1
+	127.0.0.3:85891 (app) version 6.2.20`))
+		})
+	})
+
+	When("starting an upgrade", func() {
+		It("should change the version when the checks are skipped", func() {
+			Expect(startUpgrade(kubeClient, "test", "test", "7.0.0", true, 0, true, &bytes.Buffer{})).NotTo(HaveOccurred())
+
+			result, err := loadCluster(kubeClient, "test", "test")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Spec.Version).To(Equal("7.0.0"))
+		})
+
+		When("the checks are blocking the upgrade", func() {
+			JustBeforeEach(func() {
+				reportUpgradeCheck(fdbtypes.UpgradeCheckStatus{
+					Version: "7.0.0",
+					Errors:  []string{"the sidecar doesn't support version 7.0.0"},
+				})
+			})
+
+			It("should not change the version", func() {
+				out := &bytes.Buffer{}
+				Expect(startUpgrade(kubeClient, "test", "test", "7.0.0", false, time.Minute, true, out)).To(HaveOccurred())
+				Expect(out.String()).To(ContainSubstring("Upgrade to version 7.0.0: blocked"))
+
+				result, err := loadCluster(kubeClient, "test", "test")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Spec.Version).To(Equal("6.2.20"))
+			})
+		})
+
+		When("the checks passed", func() {
+			JustBeforeEach(func() {
+				reportUpgradeCheck(fdbtypes.UpgradeCheckStatus{Version: "7.0.0", Passed: true})
+			})
+
+			It("should change the version", func() {
+				Expect(startUpgrade(kubeClient, "test", "test", "7.0.0", false, time.Minute, true, &bytes.Buffer{})).NotTo(HaveOccurred())
+
+				result, err := loadCluster(kubeClient, "test", "test")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Spec.Version).To(Equal("7.0.0"))
+			})
+		})
+
+		When("another upgrade is pending", func() {
+			BeforeEach(func() {
+				cluster.Spec.Version = "6.3.13"
+			})
+
+			It("should return an error", func() {
+				Expect(startUpgrade(kubeClient, "test", "test", "7.0.0", true, 0, true, &bytes.Buffer{})).To(HaveOccurred())
+			})
+		})
+	})
+
+	When("aborting an upgrade", func() {
+		When("no upgrade is pending", func() {
+			It("should return an error", func() {
+				Expect(abortUpgrade(kubeClient, "test", "test", true)).To(HaveOccurred())
+			})
+		})
+
+		When("the processes were not bounced", func() {
+			BeforeEach(func() {
+				cluster.Spec.Version = "7.0.0"
+				cluster.Status.UpgradeProgress = &fdbtypes.UpgradeProgress{
+					Version:         "7.0.0",
+					ProcessVersions: map[string]int{"6.2.20": 8},
+				}
+			})
+
+			It("should change the version back to the running version", func() {
+				Expect(abortUpgrade(kubeClient, "test", "test", true)).NotTo(HaveOccurred())
+
+				result, err := loadCluster(kubeClient, "test", "test")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Spec.Version).To(Equal("6.2.20"))
+			})
+		})
+
+		When("some processes run the new version", func() {
+			BeforeEach(func() {
+				cluster.Spec.Version = "7.0.0"
+				cluster.Status.UpgradeProgress = &fdbtypes.UpgradeProgress{
+					Version:         "7.0.0",
+					ProcessVersions: map[string]int{"6.2.20": 6, "7.0.0": 2},
+				}
+			})
+
+			It("should return an error", func() {
+				Expect(abortUpgrade(kubeClient, "test", "test", true)).To(HaveOccurred())
+
+				result, err := loadCluster(kubeClient, "test", "test")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Spec.Version).To(Equal("7.0.0"))
+			})
+		})
+	})
+
+	When("following the progress of an upgrade", func() {
+		BeforeEach(func() {
+			cluster.ObjectMeta.Generation = 2
+			cluster.Spec.Version = "7.0.0"
+			cluster.Status.Generations = fdbtypes.ClusterGenerationStatus{
+				Reconciled:  1,
+				NeedsBounce: 2,
+			}
+			cluster.Status.UpgradeProgress = &fdbtypes.UpgradeProgress{
+				Version:         "7.0.0",
+				ProcessVersions: map[string]int{"6.2.20": 8},
+			}
+		})
+
+		It("should print the progress and new events", func() {
+			watcher, err := newUpgradeWatcher(kubeClient, "test", "test", "7.0.0")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(kubeClient.Create(context.TODO(), &corev1.Event{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test.1",
+					Namespace: "test",
+				},
+				InvolvedObject: corev1.ObjectReference{
+					Kind: "FoundationDBCluster",
+					Name: "test",
+				},
+				Reason:  "NeedsBounce",
+				Message: "Spec require a bounce of some processes, but killing processes is disabled",
+				Count:   1,
+			})).NotTo(HaveOccurred())
+
+			out := &bytes.Buffer{}
+			done, err := watcher.printProgress(out)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(done).To(BeFalse())
+			Expect(out.String()).To(ContainSubstring(`Version: 7.0.0, running version: 6.2.20
+Reconciled generation: 1 of 2
+Pending actions: needsBounce
+Process versions: 6.2.20: 8`))
+			Expect(out.String()).To(ContainSubstring("Event NeedsBounce: Spec require a bounce of some processes, but killing processes is disabled"))
+
+			out.Reset()
+			done, err = watcher.printProgress(out)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(done).To(BeFalse())
+			Expect(out.String()).To(BeEmpty())
+		})
+
+		When("all processes report the new version", func() {
+			BeforeEach(func() {
+				cluster.Status.RunningVersion = "7.0.0"
+				cluster.Status.UpgradeProgress = nil
+			})
+
+			It("should be done", func() {
+				watcher, err := newUpgradeWatcher(kubeClient, "test", "test", "7.0.0")
+				Expect(err).NotTo(HaveOccurred())
+
+				done, err := watcher.printProgress(&bytes.Buffer{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(done).To(BeTrue())
+			})
+		})
+
+		When("the upgrade was aborted", func() {
+			BeforeEach(func() {
+				cluster.Spec.Version = "6.2.20"
+			})
+
+			It("should return an error", func() {
+				watcher, err := newUpgradeWatcher(kubeClient, "test", "test", "7.0.0")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = watcher.printProgress(&bytes.Buffer{})
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+})
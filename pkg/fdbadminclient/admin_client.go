@@ -102,4 +102,13 @@ type AdminClient interface {
 
 	// SetKnobs sets the knobs that should be used for the commandline call.
 	SetKnobs([]string)
+
+	// SetRuntimeKnobs sets knobs in the configuration database. The keys are
+	// the knob names without the knob_ prefix. Knobs with an empty value are
+	// cleared from the configuration database.
+	SetRuntimeKnobs(knobs map[string]string) error
+
+	// GetRuntimeKnobs reads knobs from the configuration database. The result
+	// only contains the knobs from the names that are set.
+	GetRuntimeKnobs(names []string) (map[string]string, error)
 }
@@ -47,4 +47,12 @@ type LockClient interface {
 
 	// UpdateDenyList updates the deny list to match a list of entries.
 	UpdateDenyList(locks []v1beta1.LockDenyListEntry) error
+
+	// UpdateOperatorInstance registers an instance of the operator in the
+	// database.
+	UpdateOperatorInstance(instance v1beta1.OperatorInstance) error
+
+	// GetOperatorInstances returns the instances of the operator that are
+	// registered in the database.
+	GetOperatorInstances() ([]v1beta1.OperatorInstance, error)
 }
@@ -29,7 +29,9 @@ import (
 	"strings"
 	"time"
 
+	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -46,6 +48,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
 var setupLog = ctrl.Log.WithName("setup")
@@ -53,21 +56,28 @@ var operatorVersion = "latest"
 
 // Options provides all configuration Options for the operator
 type Options struct {
-	MetricsAddr             string
-	EnableLeaderElection    bool
-	LeaderElectionID        string
-	LogFile                 string
-	CliTimeout              int
-	DeprecationOptions      internal.DeprecationOptions
-	MaxConcurrentReconciles int
-	CleanUpOldLogFile       bool
-	LogFileMinAge           time.Duration
-	LogFileMaxSize          int
-	LogFileMaxAge           int
-	MaxNumberOfOldLogFiles  int
-	CompressOldFiles        bool
-	PrintVersion            bool
-	LabelSelector           string
+	MetricsAddr                  string
+	EnableLeaderElection         bool
+	LeaderElectionID             string
+	LogFile                      string
+	CliTimeout                   int
+	DeprecationOptions           internal.DeprecationOptions
+	MaxConcurrentReconciles      int
+	CleanUpOldLogFile            bool
+	LogFileMinAge                time.Duration
+	LogFileMaxSize               int
+	LogFileMaxAge                int
+	MaxNumberOfOldLogFiles       int
+	CompressOldFiles             bool
+	PrintVersion                 bool
+	LabelSelector                string
+	EnableNodeChecks             bool
+	EnableClientLibraryInjection bool
+	ClientConfigTargetNamespaces string
+	// CertificateIssuers defines the certificate issuers that are registered
+	// for the cluster reconciler. Custom operator builds can set this before
+	// calling StartManager, since issuers can't be configured through flags.
+	CertificateIssuers map[string]controllers.CertificateIssuer
 }
 
 // BindFlags will parse the given flagset for the operator option flags
@@ -91,6 +101,9 @@ func (o *Options) BindFlags(fs *flag.FlagSet) {
 	fs.BoolVar(&o.CompressOldFiles, "compress", false, "Defines whether the rotated log files should be compressed using gzip or not.")
 	fs.BoolVar(&o.PrintVersion, "version", false, "Prints the version of the operator and exits.")
 	fs.StringVar(&o.LabelSelector, "label-selector", "", "Defines a label-selector that will be used to select resources.")
+	fs.BoolVar(&o.EnableNodeChecks, "enable-node-checks", false, "Defines if the operator should check the nodes of the pods, e.g. to move coordinators off nodes that are drained. This requires permissions to read nodes.")
+	fs.StringVar(&o.ClientConfigTargetNamespaces, "client-config-target-namespaces", "", "Defines a comma-separated list of namespaces other than the namespace of the cluster where the operator may publish client config targets.")
+	fs.BoolVar(&o.EnableClientLibraryInjection, "enable-client-library-injection", false, "Defines if the operator should serve the admission webhook that injects client libraries into application pods.")
 }
 
 // StartManager will start the FoundationDB operator manager.
@@ -170,6 +183,19 @@ func StartManager(
 		clusterReconciler.Client = mgr.GetClient()
 		clusterReconciler.Recorder = mgr.GetEventRecorderFor("foundationdbcluster-controller")
 		clusterReconciler.DeprecationOptions = operatorOpts.DeprecationOptions
+		clusterReconciler.EnableNodeChecks = operatorOpts.EnableNodeChecks
+		clusterReconciler.NodeReader = mgr.GetAPIReader()
+		for name, issuer := range operatorOpts.CertificateIssuers {
+			if err := clusterReconciler.RegisterCertificateIssuer(name, issuer); err != nil {
+				setupLog.Error(err, "unable to register certificate issuer", "issuer", name)
+				os.Exit(1)
+			}
+		}
+		clusterReconciler.ClientConfigTargetReader = mgr.GetAPIReader()
+		if operatorOpts.ClientConfigTargetNamespaces != "" {
+			clusterReconciler.ClientConfigTargetNamespaces = strings.Split(operatorOpts.ClientConfigTargetNamespaces, ",")
+		}
+		clusterReconciler.UsePolicyV1PodDisruptionBudgets = hasPolicyV1PodDisruptionBudgets(mgr)
 		clusterReconciler.DatabaseClientProvider = fdbclient.NewDatabaseClientProvider()
 		clusterReconciler.Log = logr.WithName("controllers").WithName("FoundationDBCluster")
 
@@ -207,6 +233,12 @@ func StartManager(
 		}
 	}
 
+	if operatorOpts.EnableClientLibraryInjection {
+		mgr.GetWebhookServer().Register("/mutate-v1-pod", &webhook.Admission{
+			Handler: &controllers.ClientLibraryInjector{Reader: mgr.GetAPIReader()},
+		})
+	}
+
 	if operatorOpts.CleanUpOldLogFile {
 		setupLog.V(1).Info("setup log file cleaner", "LogFileMinAge", operatorOpts.LogFileMinAge.String())
 		ticker := time.NewTicker(operatorOpts.LogFileMinAge)
@@ -223,6 +255,13 @@ func StartManager(
 	return mgr, file
 }
 
+// hasPolicyV1PodDisruptionBudgets checks if the API server serves
+// PodDisruptionBudgets in policy/v1, which is the case since Kubernetes 1.21.
+func hasPolicyV1PodDisruptionBudgets(mgr manager.Manager) bool {
+	_, err := mgr.GetRESTMapper().RESTMapping(schema.GroupKind{Group: policyv1.GroupName, Kind: "PodDisruptionBudget"}, policyv1.SchemeGroupVersion.Version)
+	return err == nil
+}
+
 // MoveFDBBinaries moves FDB binaries that are pulled from setup containers into
 // the correct locations.
 func moveFDBBinaries() error {